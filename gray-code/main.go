@@ -34,6 +34,27 @@ func grayCode(n int) []int {
 	return result
 }
 
+// MaxGrayCodeBits bounds the sequence size the safe variant will generate.
+// Each extra bit doubles the output: n bits allocate 2^n ints (8*2^n bytes
+// on 64-bit), so n=30 already needs ~8 GiB. Beyond the platform's int width
+// minus one, 1<<n overflows outright.
+const MaxGrayCodeBits = 26 // 2^26 ints = 512 MiB, a sane upper bound
+
+// grayCodeSafe validates n before generating the sequence, returning an
+// error instead of overflowing 1<<n or allocating an absurd amount of
+// memory when callers pass a huge n. Uses the O(1)-extra-space formula
+// approach for the actual generation.
+func grayCodeSafe(n int) ([]int, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("n must be non-negative, got %d", n)
+	}
+	if n > MaxGrayCodeBits {
+		return nil, fmt.Errorf("n=%d exceeds the maximum of %d bits (2^%d values would need ~%d MiB)",
+			n, MaxGrayCodeBits, n, (int64(8)<<uint(n))/(1<<20))
+	}
+	return grayCodeFormula(n), nil
+}
+
 // Alternative optimized solution using mathematical formula
 // This is the most efficient approach with O(2^n) time and O(1) extra space
 func grayCodeFormula(n int) []int {
@@ -243,3 +243,54 @@ func TestEqualSlices(t *testing.T) {
 		})
 	}
 }
+
+// Test the guarded variant's input validation
+func TestGrayCodeSafe(t *testing.T) {
+	t.Run("Valid n", func(t *testing.T) {
+		result, err := grayCodeSafe(3)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []int{0, 1, 3, 2, 6, 7, 5, 4}
+		if !equalSlices(result, expected) {
+			t.Errorf("grayCodeSafe(3) = %v, expected %v", result, expected)
+		}
+	})
+
+	t.Run("Zero bits", func(t *testing.T) {
+		result, err := grayCodeSafe(0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result) != 1 || result[0] != 0 {
+			t.Errorf("grayCodeSafe(0) = %v, expected [0]", result)
+		}
+	})
+
+	t.Run("Negative n", func(t *testing.T) {
+		if _, err := grayCodeSafe(-1); err == nil {
+			t.Error("Expected error for negative n, got nil")
+		}
+	})
+
+	t.Run("Huge n rejected", func(t *testing.T) {
+		for _, n := range []int{MaxGrayCodeBits + 1, 63, 1 << 20} {
+			if _, err := grayCodeSafe(n); err == nil {
+				t.Errorf("Expected error for n=%d, got nil", n)
+			}
+		}
+	})
+
+	t.Run("Boundary n accepted", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping large allocation in short mode")
+		}
+		result, err := grayCodeSafe(MaxGrayCodeBits)
+		if err != nil {
+			t.Fatalf("Unexpected error at the boundary: %v", err)
+		}
+		if len(result) != 1<<MaxGrayCodeBits {
+			t.Errorf("Expected %d values, got %d", 1<<MaxGrayCodeBits, len(result))
+		}
+	})
+}
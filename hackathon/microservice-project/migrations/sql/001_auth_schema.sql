@@ -0,0 +1,80 @@
+-- Auth service schema backing the PostgreSQL repository manager.
+-- Applied via the migration framework (migrations/tools/migrate.sh).
+
+CREATE TABLE IF NOT EXISTS users (
+    id            VARCHAR(64) PRIMARY KEY,
+    username      VARCHAR(50) NOT NULL UNIQUE,
+    email         VARCHAR(255) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    first_name    VARCHAR(100) NOT NULL DEFAULT '',
+    last_name     VARCHAR(100) NOT NULL DEFAULT '',
+    role          VARCHAR(20) NOT NULL DEFAULT 'user',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ,
+    metadata      JSONB
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id           VARCHAR(64) PRIMARY KEY,
+    user_id      VARCHAR(64) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_id     TEXT NOT NULL,
+    ip_address   VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent   TEXT NOT NULL DEFAULT '',
+    fingerprint  VARCHAR(64) NOT NULL DEFAULT '',
+    country      VARCHAR(64) NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status       VARCHAR(20) NOT NULL DEFAULT 'active'
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_token_id ON sessions(token_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id         VARCHAR(128) PRIMARY KEY,
+    token_id   TEXT NOT NULL UNIQUE,
+    user_id    VARCHAR(64) NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id         VARCHAR(64) PRIMARY KEY,
+    user_id    VARCHAR(64) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token      TEXT NOT NULL UNIQUE,
+    used       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id         VARCHAR(64) PRIMARY KEY,
+    identifier VARCHAR(255) NOT NULL,
+    success    BOOLEAN NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    timestamp  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_identifier ON login_attempts(identifier, timestamp);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts(ip_address, timestamp);
+
+CREATE TABLE IF NOT EXISTS activity_logs (
+    id            VARCHAR(64) PRIMARY KEY,
+    user_id       VARCHAR(64),
+    action        VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL DEFAULT '',
+    resource_id   VARCHAR(64),
+    details       JSONB,
+    ip_address    VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent    TEXT NOT NULL DEFAULT '',
+    timestamp     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status        VARCHAR(20) NOT NULL DEFAULT 'success'
+);
+CREATE INDEX IF NOT EXISTS idx_activity_logs_user_id ON activity_logs(user_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_activity_logs_action ON activity_logs(action, timestamp);
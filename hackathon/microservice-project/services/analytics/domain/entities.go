@@ -22,37 +22,42 @@ const (
 	EventTypeError        EventType = "error"
 )
 
-// Event represents an analytics event
+// Event represents an analytics event. Events are partitioned by OrgID for
+// multi-tenant deployments; the backing table is indexed on (org_id, timestamp).
 type Event struct {
-	ID         uuid.UUID              `json:"id" db:"id"`
-	UserID     *uuid.UUID             `json:"user_id" db:"user_id"`
-	SessionID  *uuid.UUID             `json:"session_id" db:"session_id"`
-	Type       EventType              `json:"type" db:"type"`
-	Action     string                 `json:"action" db:"action"`
-	Resource   string                 `json:"resource" db:"resource"`
-	ResourceID *uuid.UUID             `json:"resource_id" db:"resource_id"`
-	Metadata   map[string]interface{} `json:"metadata" db:"metadata"`
-	IPAddress  string                 `json:"ip_address" db:"ip_address"`
-	UserAgent  string                 `json:"user_agent" db:"user_agent"`
-	Timestamp  time.Time              `json:"timestamp" db:"timestamp"`
-	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+	ID            uuid.UUID              `json:"id" db:"id"`
+	SchemaVersion int                    `json:"schema_version" db:"schema_version"`
+	OrgID         *uuid.UUID             `json:"org_id,omitempty" db:"org_id"`
+	UserID        *uuid.UUID             `json:"user_id" db:"user_id"`
+	SessionID     *uuid.UUID             `json:"session_id" db:"session_id"`
+	Type          EventType              `json:"type" db:"type"`
+	Action        string                 `json:"action" db:"action"`
+	Resource      string                 `json:"resource" db:"resource"`
+	ResourceID    *uuid.UUID             `json:"resource_id" db:"resource_id"`
+	Metadata      map[string]interface{} `json:"metadata" db:"metadata"`
+	AppVersion    string                 `json:"app_version,omitempty" db:"app_version"` // indexed release/version dimension
+	IPAddress     string                 `json:"ip_address" db:"ip_address"`
+	UserAgent     string                 `json:"user_agent" db:"user_agent"`
+	Timestamp     time.Time              `json:"timestamp" db:"timestamp"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
 }
 
 // UserActivity represents aggregated user activity
 type UserActivity struct {
-	ID              uuid.UUID `json:"id" db:"id"`
-	UserID          uuid.UUID `json:"user_id" db:"user_id"`
-	Date            time.Time `json:"date" db:"date"`
-	TotalEvents     int64     `json:"total_events" db:"total_events"`
-	FileUploads     int64     `json:"file_uploads" db:"file_uploads"`
-	FileDownloads   int64     `json:"file_downloads" db:"file_downloads"`
-	FileViews       int64     `json:"file_views" db:"file_views"`
-	FileShares      int64     `json:"file_shares" db:"file_shares"`
-	APICallsCount   int64     `json:"api_calls_count" db:"api_calls_count"`
-	ErrorsCount     int64     `json:"errors_count" db:"errors_count"`
-	SessionDuration int64     `json:"session_duration" db:"session_duration"` // in seconds
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	OrgID           *uuid.UUID `json:"org_id,omitempty" db:"org_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Date            time.Time  `json:"date" db:"date"`
+	TotalEvents     int64      `json:"total_events" db:"total_events"`
+	FileUploads     int64      `json:"file_uploads" db:"file_uploads"`
+	FileDownloads   int64      `json:"file_downloads" db:"file_downloads"`
+	FileViews       int64      `json:"file_views" db:"file_views"`
+	FileShares      int64      `json:"file_shares" db:"file_shares"`
+	APICallsCount   int64      `json:"api_calls_count" db:"api_calls_count"`
+	ErrorsCount     int64      `json:"errors_count" db:"errors_count"`
+	SessionDuration int64      `json:"session_duration" db:"session_duration"` // in seconds
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // SystemMetrics represents system-wide metrics
@@ -97,6 +102,10 @@ type APIMetrics struct {
 	RequestCount   int64     `json:"request_count" db:"request_count"`
 	SuccessCount   int64     `json:"success_count" db:"success_count"`
 	ErrorCount     int64     `json:"error_count" db:"error_count"`
+	Count2xx       int64     `json:"count_2xx" db:"count_2xx"`
+	Count3xx       int64     `json:"count_3xx" db:"count_3xx"`
+	Count4xx       int64     `json:"count_4xx" db:"count_4xx"`
+	Count5xx       int64     `json:"count_5xx" db:"count_5xx"`
 	AverageLatency float64   `json:"average_latency" db:"average_latency"`
 	MinLatency     float64   `json:"min_latency" db:"min_latency"`
 	MaxLatency     float64   `json:"max_latency" db:"max_latency"`
@@ -106,6 +115,22 @@ type APIMetrics struct {
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// APIMetricsSummary is an all-time rollup of one endpoint's metrics across
+// its per-day rows
+type APIMetricsSummary struct {
+	Endpoint       string     `json:"endpoint"`
+	Method         string     `json:"method"`
+	TotalRequests  int64      `json:"total_requests"`
+	SuccessCount   int64      `json:"success_count"`
+	ErrorCount     int64      `json:"error_count"`
+	SuccessRate    float64    `json:"success_rate"`
+	AverageLatency float64    `json:"average_latency"` // request-count weighted
+	MinLatency     float64    `json:"min_latency"`
+	MaxLatency     float64    `json:"max_latency"`
+	FirstSeen      *time.Time `json:"first_seen,omitempty"`
+	LastSeen       *time.Time `json:"last_seen,omitempty"`
+}
+
 // ErrorMetrics represents error tracking metrics
 type ErrorMetrics struct {
 	ID           uuid.UUID `json:"id" db:"id"`
@@ -152,6 +177,7 @@ type Report struct {
 	Type        ReportType             `json:"type" db:"type"`
 	Description string                 `json:"description" db:"description"`
 	Filters     map[string]interface{} `json:"filters" db:"filters"`
+	Format      string                 `json:"format" db:"format"` // "json", "csv", "pdf"
 	Data        []byte                 `json:"data" db:"data"`
 	GeneratedBy uuid.UUID              `json:"generated_by" db:"generated_by"`
 	StartDate   time.Time              `json:"start_date" db:"start_date"`
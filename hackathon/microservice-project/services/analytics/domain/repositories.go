@@ -38,6 +38,13 @@ type EventRepository interface {
 
 	// Delete deletes events older than the specified date
 	DeleteOlderThan(ctx context.Context, date time.Time) error
+
+	// ReassignUser rewrites the user reference on all of a user's events
+	// (used for anonymization); returns the number of events touched
+	ReassignUser(ctx context.Context, fromUserID, toUserID uuid.UUID) (int64, error)
+
+	// DeleteByUserID hard-deletes all of a user's events
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 // UserActivityRepository defines the interface for user activity data operations
@@ -59,6 +66,16 @@ type UserActivityRepository interface {
 
 	// GetAggregatedByDateRange retrieves aggregated activity within a date range
 	GetAggregatedByDateRange(ctx context.Context, startDate, endDate time.Time) (*UserActivity, error)
+
+	// GetTopUsersByMetric retrieves the top users ranked by a specific
+	// activity metric within a date range
+	GetTopUsersByMetric(ctx context.Context, metric string, startDate, endDate time.Time, limit int) ([]*UserActivity, error)
+
+	// ReassignUser rewrites the user reference on all activity rows
+	ReassignUser(ctx context.Context, fromUserID, toUserID uuid.UUID) (int64, error)
+
+	// DeleteByUserID hard-deletes all of a user's activity rows
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 // SystemMetricsRepository defines the interface for system metrics data operations
@@ -128,6 +145,10 @@ type APIMetricsRepository interface {
 
 	// GetSlowestEndpoints retrieves the slowest endpoints
 	GetSlowestEndpoints(ctx context.Context, startDate, endDate time.Time, limit int) ([]*APIMetrics, error)
+
+	// GetEndpointSummary aggregates an endpoint's metrics across all dates
+	// into a single rollup row
+	GetEndpointSummary(ctx context.Context, endpoint, method string) (*APIMetricsSummary, error)
 }
 
 // ErrorMetricsRepository defines the interface for error metrics data operations
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/analytics/domain"
@@ -107,6 +108,29 @@ func (r *MockEventRepository) DeleteOlderThan(ctx context.Context, date time.Tim
 	return nil
 }
 
+func (r *MockEventRepository) ReassignUser(ctx context.Context, fromUserID, toUserID uuid.UUID) (int64, error) {
+	var count int64
+	for _, event := range r.events {
+		if event.UserID != nil && *event.UserID == fromUserID {
+			newID := toUserID
+			event.UserID = &newID
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MockEventRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for id, event := range r.events {
+		if event.UserID != nil && *event.UserID == userID {
+			delete(r.events, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
 // MockUserActivityRepository implements domain.UserActivityRepository
 type MockUserActivityRepository struct {
 	activities map[uuid.UUID]*domain.UserActivity
@@ -165,6 +189,63 @@ func (r *MockUserActivityRepository) GetAggregatedByDateRange(ctx context.Contex
 	return aggregated, nil
 }
 
+func (r *MockUserActivityRepository) GetTopUsersByMetric(ctx context.Context, metric string, startDate, endDate time.Time, limit int) ([]*domain.UserActivity, error) {
+	var result []*domain.UserActivity
+	for _, activity := range r.activities {
+		if activity.Date.After(startDate) && activity.Date.Before(endDate) {
+			result = append(result, activity)
+		}
+	}
+
+	metricValue := func(activity *domain.UserActivity) int64 {
+		switch metric {
+		case "file_uploads":
+			return activity.FileUploads
+		case "file_downloads":
+			return activity.FileDownloads
+		case "file_views":
+			return activity.FileViews
+		case "file_shares":
+			return activity.FileShares
+		case "api_calls":
+			return activity.APICallsCount
+		default:
+			return activity.TotalEvents
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return metricValue(result[i]) > metricValue(result[j])
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *MockUserActivityRepository) ReassignUser(ctx context.Context, fromUserID, toUserID uuid.UUID) (int64, error) {
+	var count int64
+	for _, activity := range r.activities {
+		if activity.UserID == fromUserID {
+			activity.UserID = toUserID
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MockUserActivityRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for id, activity := range r.activities {
+		if activity.UserID == userID {
+			delete(r.activities, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
 // MockSystemMetricsRepository implements domain.SystemMetricsRepository
 type MockSystemMetricsRepository struct {
 	metrics map[uuid.UUID]*domain.SystemMetrics
@@ -351,6 +432,49 @@ func (r *MockAPIMetricsRepository) GetSlowestEndpoints(ctx context.Context, star
 	return result, nil
 }
 
+func (r *MockAPIMetricsRepository) GetEndpointSummary(ctx context.Context, endpoint, method string) (*domain.APIMetricsSummary, error) {
+	summary := &domain.APIMetricsSummary{
+		Endpoint: endpoint,
+		Method:   method,
+	}
+
+	var weightedLatency float64
+	for _, metric := range r.metrics {
+		if metric.Endpoint != endpoint || metric.Method != method {
+			continue
+		}
+
+		summary.TotalRequests += metric.RequestCount
+		summary.SuccessCount += metric.SuccessCount
+		summary.ErrorCount += metric.ErrorCount
+		weightedLatency += metric.AverageLatency * float64(metric.RequestCount)
+
+		if summary.MinLatency == 0 || (metric.MinLatency > 0 && metric.MinLatency < summary.MinLatency) {
+			summary.MinLatency = metric.MinLatency
+		}
+		if metric.MaxLatency > summary.MaxLatency {
+			summary.MaxLatency = metric.MaxLatency
+		}
+
+		date := metric.Date
+		if summary.FirstSeen == nil || date.Before(*summary.FirstSeen) {
+			first := date
+			summary.FirstSeen = &first
+		}
+		if summary.LastSeen == nil || date.After(*summary.LastSeen) {
+			last := date
+			summary.LastSeen = &last
+		}
+	}
+
+	if summary.TotalRequests > 0 {
+		summary.AverageLatency = weightedLatency / float64(summary.TotalRequests)
+		summary.SuccessRate = float64(summary.SuccessCount) / float64(summary.TotalRequests)
+	}
+
+	return summary, nil
+}
+
 // MockErrorMetricsRepository implements domain.ErrorMetricsRepository
 type MockErrorMetricsRepository struct {
 	metrics map[uuid.UUID]*domain.ErrorMetrics
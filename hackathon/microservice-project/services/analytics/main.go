@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/analytics/domain"
+	"github.com/elotusteam/microservice-project/services/analytics/infrastructure"
 	"github.com/elotusteam/microservice-project/services/analytics/usecases"
+	"github.com/elotusteam/microservice-project/shared/authrpc"
 	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
@@ -39,6 +49,12 @@ var (
 		},
 		[]string{"event_type"},
 	)
+	analyticsQuotaRejections = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "analytics_event_quota_rejections_total",
+			Help: "Events rejected because a tenant daily quota was exhausted",
+		},
+	)
 	analyticsReportsGenerated = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "analytics_reports_generated_total",
@@ -51,9 +67,158 @@ func init() {
 	prometheus.MustRegister(httpRequestsTotal)
 	prometheus.MustRegister(httpRequestDuration)
 	prometheus.MustRegister(analyticsEventsTotal)
+	prometheus.MustRegister(analyticsQuotaRejections)
 	prometheus.MustRegister(analyticsReportsGenerated)
 }
 
+// analyticsService backs the handlers that have been wired to real aggregates
+var analyticsService = usecases.NewAnalyticsService(infrastructure.NewMockRepositoryManager())
+
+func init() {
+	// Expose the global report generation limiter state
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "analytics_report_generations_active",
+			Help: "Number of report generations currently running",
+		},
+		func() float64 {
+			stats, _ := analyticsService.GetReportGenerationStats(context.Background())
+			return float64(stats.ActiveCount)
+		},
+	))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "analytics_event_ingestion_lag_seconds",
+			Help: "Delay between event creation and persistence",
+		},
+		func() float64 {
+			lag, _ := analyticsService.GetIngestionLag(context.Background())
+			return lag.Seconds()
+		},
+	))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "analytics_report_generations_queued",
+			Help: "Number of report generations waiting for a slot",
+		},
+		func() float64 {
+			stats, _ := analyticsService.GetReportGenerationStats(context.Background())
+			return float64(stats.QueueDepth)
+		},
+	))
+}
+
+// authTokenValidator validates bearer tokens against the auth service over
+// gRPC (AUTH_GRPC_ADDR), failing closed when the auth service is unreachable
+func authTokenValidator() middleware.TokenValidator {
+	target := os.Getenv("AUTH_GRPC_ADDR")
+	if target == "" {
+		target = "localhost:9081"
+	}
+	var (
+		clientOnce sync.Once
+		client     *authrpc.Client
+		dialErr    error
+	)
+
+	return func(ctx context.Context, token string) (string, string, error) {
+		clientOnce.Do(func() {
+			client, dialErr = authrpc.Dial(target)
+		})
+		if dialErr != nil {
+			return "", "", fmt.Errorf("auth service unavailable: %w", dialErr)
+		}
+
+		resp, err := client.ValidateToken(ctx, token)
+		if err != nil {
+			return "", "", err
+		}
+		if !resp.Valid {
+			return "", "", fmt.Errorf("invalid token: %s", resp.Error)
+		}
+		return resp.UserID, resp.Role, nil
+	}
+}
+
+// adminAuth guards admin handlers with validated-claims role checks; set in
+// main before routes are registered
+var adminAuth gin.HandlerFunc
+
+// orgScopeAuth authenticates org-partitioned read handlers and records the
+// caller's org from validated claims; set in main before routes are
+// registered
+var orgScopeAuth gin.HandlerFunc
+
+// Context key under which orgScopeAuth stores the caller's validated org
+const authOrgIDKey = "auth_org_id"
+
+// requireOrgScope authenticates the bearer token against the auth service
+// and stores the validated identity, including the caller's org, in the gin
+// context. Unlike the shared RequireAuth it keeps the org claim, which the
+// org-partitioned read handlers scope their queries by.
+func requireOrgScope() gin.HandlerFunc {
+	target := os.Getenv("AUTH_GRPC_ADDR")
+	if target == "" {
+		target = "localhost:9081"
+	}
+	var (
+		clientOnce sync.Once
+		client     *authrpc.Client
+		dialErr    error
+	)
+
+	return func(c *gin.Context) {
+		const prefix = "Bearer "
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, prefix) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+			return
+		}
+
+		clientOnce.Do(func() {
+			client, dialErr = authrpc.Dial(target)
+		})
+		if dialErr != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "auth service unavailable"})
+			return
+		}
+
+		resp, err := client.ValidateToken(c.Request.Context(), strings.TrimPrefix(header, prefix))
+		if err != nil || !resp.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		c.Set(middleware.AuthUserIDKey, resp.UserID)
+		c.Set(middleware.AuthUserRoleKey, resp.Role)
+		c.Set(authOrgIDKey, resp.OrgID)
+		c.Next()
+	}
+}
+
+// dashboardMaxRange bounds the dashboard date range. It can be overridden
+// with the ANALYTICS_DASHBOARD_MAX_RANGE environment variable.
+func dashboardMaxRange() time.Duration {
+	if value := os.Getenv("ANALYTICS_DASHBOARD_MAX_RANGE"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 90 * 24 * time.Hour
+}
+
+// parseDashboardTime accepts RFC3339 timestamps, plain dates or relative
+// forms via the shared time-range helper
+func parseDashboardTime(value string) (time.Time, error) {
+	return middleware.ParseTimeValue(value)
+}
+
+// parseDashboardRange validates the start_date/end_date query params via the
+// shared helper, defaulting to the last 7 days when both are omitted.
+func parseDashboardRange(c *gin.Context) (time.Time, time.Time, error) {
+	return middleware.ParseTimeRange(c, 7*24*time.Hour, dashboardMaxRange())
+}
+
 // Prometheus middleware
 func prometheusMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -68,18 +233,35 @@ func prometheusMiddleware() gin.HandlerFunc {
 }
 
 func main() {
-	// Load configuration (for future use)
-	_, err := config.LoadConfig()
+	// Load configuration
+	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Admin surfaces authenticate via the auth service; the role comes from
+	// validated claims, never from client-supplied headers
+	adminAuth = middleware.RequireRole(authTokenValidator(), "admin")
+	orgScopeAuth = requireOrgScope()
+
 	// Initialize Gin router
 	r := gin.Default()
 
 	// Add Prometheus middleware
 	r.Use(prometheusMiddleware())
 
+	// Return 405 with an Allow header for wrong-method requests
+	middleware.ConfigureMethodNotAllowed(r)
+
+	// Guard expensive endpoints against pagination-bomb requests; report and
+	// event rows carry heavy per-row work so their caps are tighter
+	paginationConfig := middleware.DefaultPaginationGuardConfig()
+	paginationConfig.RouteMaxLimits = map[string]int{
+		"/api/v1/reports": 20,
+		"/api/v1/events":  500,
+	}
+	r.Use(middleware.PaginationGuard(paginationConfig))
+
 	// Add CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -92,6 +274,13 @@ func main() {
 		c.Next()
 	})
 
+	// Start the Prometheus remote-write exporter when an endpoint is configured
+	remoteWriteConfig := usecases.LoadRemoteWriteConfig()
+	if remoteWriteConfig.Endpoint != "" {
+		exporter := usecases.NewRemoteWriteExporter(analyticsService, remoteWriteConfig)
+		go exporter.Start(context.Background())
+	}
+
 	// Metrics endpoint
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -103,16 +292,28 @@ func main() {
 	// Analytics API routes
 	api := r.Group("/api/v1")
 	{
-		// Event tracking routes
-		api.POST("/events", trackEvent)
-		api.POST("/events/batch", trackBatchEvents)
-		api.GET("/events", getEvents)
+		// Event tracking routes require an API key so arbitrary clients
+		// cannot poison analytics data
+		ingestAuth := requireIngestAPIKey(cfg)
+		api.POST("/events", ingestAuth, trackEvent)
+		api.POST("/events/batch", ingestAuth, trackBatchEvents)
+		api.GET("/events", orgScopeAuth, getEvents)
 		api.GET("/events/stats", getEventStats)
+		api.GET("/events/retention", getRetentionCohorts)
+		api.POST("/events/funnel", getFunnel)
+		api.GET("/events/co-occurrence", getCoOccurrence)
+		api.GET("/events/error-rate-by-version", getErrorRateByVersion)
+		api.GET("/events/suspicious-downloads", adminAuth, getSuspiciousDownloads)
+		api.GET("/events/quota", orgScopeAuth, getEventQuota)
 
 		// User activity routes
-		api.GET("/users/:id/activity", getUserActivity)
+		api.GET("/users/:id/activity", orgScopeAuth, getUserActivity)
 		api.GET("/users/top-active", getTopActiveUsers)
+		api.GET("/users/leaderboard", getUserLeaderboard)
 		api.PUT("/users/:id/activity", updateUserActivity)
+		api.POST("/users/:id/activity/reconcile", adminAuth, reconcileUserActivity)
+		api.POST("/users/activity/reconcile-all", adminAuth, reconcileAllUserActivity)
+		api.POST("/users/:id/anonymize", adminAuth, anonymizeUserData)
 
 		// System metrics routes
 		api.GET("/system/metrics", getSystemMetrics)
@@ -129,16 +330,21 @@ func main() {
 		api.POST("/api/track", trackAPICall)
 		api.GET("/api/top-endpoints", getTopEndpoints)
 		api.GET("/api/slowest-endpoints", getSlowestEndpoints)
+		api.GET("/api/endpoint-summary", getEndpointSummary)
+		api.GET("/api/error-budget", getErrorBudget)
+		api.GET("/api/status-breakdown", getStatusBreakdown)
 
 		// Error metrics routes
 		api.GET("/errors/metrics", getErrorMetrics)
 		api.POST("/errors/track", trackError)
 		api.GET("/errors/top", getTopErrors)
+		api.GET("/errors/top-users", adminAuth, getTopErrorUsers)
 
 		// Report routes
-		api.POST("/reports", generateReport)
+		api.POST("/reports", middleware.RequireAuth(authTokenValidator()), generateReport)
 		api.GET("/reports", getReports)
 		api.GET("/reports/:id", getReportByID)
+		api.GET("/reports/:id/stream", streamReport)
 		api.DELETE("/reports/:id", deleteReport)
 
 		// Dashboard routes
@@ -150,9 +356,75 @@ func main() {
 	// Start server
 	port := "8085"
 
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           r,
+		ReadHeaderTimeout: 30 * time.Second,
+		ReadTimeout:       60 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+
+	// Run with graceful shutdown handled by the shared helper
 	log.Printf("Analytics service starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := sharedServer.RunServer(context.Background(), server, 30*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
+
+// ingestKey identifies the service/app behind an ingest API key, and
+// optionally the org its events are stamped with
+type ingestKey struct {
+	Owner string
+	OrgID string
+}
+
+// ingestAPIKeys parses the ANALYTICS_INGEST_API_KEYS environment variable,
+// formatted as comma-separated "key:owner" or "key:owner:org" entries, into
+// a key → identity lookup table.
+func ingestAPIKeys() map[string]ingestKey {
+	keys := make(map[string]ingestKey)
+	for _, entry := range strings.Split(os.Getenv("ANALYTICS_INGEST_API_KEYS"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) >= 2 && parts[0] != "" {
+			key := ingestKey{Owner: parts[1]}
+			if len(parts) == 3 {
+				key.OrgID = parts[2]
+			}
+			keys[parts[0]] = key
+		}
+	}
+	return keys
+}
+
+// requireIngestAPIKey validates the API key on event ingestion requests and
+// records the key's owning service so events can be attributed to it.
+// Unauthenticated ingestion is rejected with 401 when keys are configured.
+func requireIngestAPIKey(cfg *config.Config) gin.HandlerFunc {
+	keys := ingestAPIKeys()
+	headerName := cfg.Security.APIKey.HeaderName
+	if headerName == "" {
+		headerName = "X-API-Key"
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Security.APIKey.Enabled && len(keys) == 0 {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(headerName)
+		identity, valid := keys[key]
+		if key == "" || !valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Valid API key required"})
+			return
+		}
+
+		c.Set("ingest_source", identity.Owner)
+		if identity.OrgID != "" {
+			c.Set("ingest_org_id", identity.OrgID)
+		}
+		c.Next()
 	}
 }
 
@@ -167,7 +439,39 @@ func trackEvent(c *gin.Context) {
 	// Increment analytics events counter
 	analyticsEventsTotal.WithLabelValues(string(req.EventType)).Inc()
 
-	// Mock implementation - in real app, would use actual service
+	// Attribute the event to the API key's owning service
+	if source, ok := c.Get("ingest_source"); ok {
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]interface{})
+		}
+		req.Metadata["ingest_source"] = source
+	}
+
+	// Org-bound keys stamp their org over whatever the body claims, so
+	// quota accounting cannot be shifted onto another org
+	if org := c.GetString("ingest_org_id"); org != "" {
+		if orgID, err := uuid.Parse(org); err == nil {
+			req.OrgID = &orgID
+		}
+	}
+
+	// The release/version dimension comes from the header unless the body
+	// already carries it
+	if req.AppVersion == "" {
+		req.AppVersion = c.GetHeader("X-App-Version")
+	}
+
+	if err := analyticsService.TrackEvent(c.Request.Context(), &req); err != nil {
+		var quotaErr *usecases.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			analyticsQuotaRejections.Inc()
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Event tracked successfully"})
 }
 
@@ -178,27 +482,103 @@ func trackBatchEvents(c *gin.Context) {
 		return
 	}
 
+	// Attribute all events in the batch to the API key's owning service,
+	// stamping org-bound keys' org over whatever the bodies claim
+	if source, ok := c.Get("ingest_source"); ok {
+		for i := range req.Events {
+			if req.Events[i].Metadata == nil {
+				req.Events[i].Metadata = make(map[string]interface{})
+			}
+			req.Events[i].Metadata["ingest_source"] = source
+		}
+	}
+	if org := c.GetString("ingest_org_id"); org != "" {
+		if orgID, err := uuid.Parse(org); err == nil {
+			for i := range req.Events {
+				req.Events[i].OrgID = &orgID
+			}
+		}
+	}
+
+	if err := analyticsService.TrackBatchEvents(c.Request.Context(), &req); err != nil {
+		var quotaErr *usecases.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			analyticsQuotaRejections.Inc()
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Batch events tracked successfully"})
 }
 
+// eventFields is the sparse-fieldset allowlist for event listings
+var eventFields = map[string]bool{
+	"id": true, "type": true, "action": true, "resource": true,
+	"user_id": true, "session_id": true, "app_version": true,
+	"timestamp": true, "created_at": true, "metadata": true,
+}
+
 func getEvents(c *gin.Context) {
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	fields, err := middleware.ParseFieldsParam(c, eventFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	limit, _ := strconv.Atoi(limitStr)
-	offset, _ := strconv.Atoi(offsetStr)
+	req := &usecases.GetEventsRequest{Limit: limit, Offset: offset}
 
-	// Mock response
-	response := usecases.GetEventsResponse{
-		Events:  []*domain.Event{},
-		Total:   0,
-		Limit:   limit,
-		Offset:  offset,
-		HasMore: false,
+	// The caller's org scope comes from validated token claims; it selects
+	// which partition the caller may read
+	if value := callerOrgID(c); value != "" {
+		orgID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid org ID"})
+			return
+		}
+		req.OrgID = &orgID
 	}
 
-	c.JSON(http.StatusOK, response)
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		req.UserID = &userID
+	}
+	if value := c.Query("event_type"); value != "" {
+		eventType := domain.EventType(value)
+		req.EventType = &eventType
+	}
+
+	response, err := analyticsService.GetEvents(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Sparse fieldsets keep large event payloads small for clients that
+	// only need a few columns
+	events, err := middleware.FilterFields(response.Events, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":   events,
+		"total":    response.Total,
+		"limit":    response.Limit,
+		"offset":   response.Offset,
+		"has_more": response.HasMore,
+	})
 }
 
 func getEventStats(c *gin.Context) {
@@ -213,19 +593,159 @@ func getEventStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"stats": stats})
 }
 
+func getRetentionCohorts(c *gin.Context) {
+	cohortStartStr := c.Query("cohort_start")
+	if cohortStartStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cohort_start is required"})
+		return
+	}
+
+	cohortStart, err := parseDashboardTime(cohortStartStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cohort_start"})
+		return
+	}
+
+	periods, err := strconv.Atoi(c.DefaultQuery("periods", "8"))
+	if err != nil || periods <= 0 || periods > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "periods must be between 1 and 52"})
+		return
+	}
+
+	response, err := analyticsService.GetRetentionCohorts(c.Request.Context(), cohortStart, periods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func getFunnel(c *gin.Context) {
+	var req usecases.GetFunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := analyticsService.GetFunnel(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func getCoOccurrence(c *gin.Context) {
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	response, err := analyticsService.GetCoOccurrence(c.Request.Context(), startDate, endDate, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func getErrorRateByVersion(c *gin.Context) {
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rates, err := analyticsService.GetErrorRateByVersion(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": rates})
+}
+
+// getSuspiciousDownloads is an admin view of bulk-scraping behavior
+func getSuspiciousDownloads(c *gin.Context) {
+	window := time.Hour
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window"})
+			return
+		}
+		window = parsed
+	}
+
+	reports, err := analyticsService.DetectSuspiciousDownloads(c.Request.Context(), window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "total": len(reports)})
+}
+
+func getEventQuota(c *gin.Context) {
+	orgID := uuid.Nil
+	if value := callerOrgID(c); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid org_id"})
+			return
+		}
+		orgID = parsed
+	}
+
+	usage, err := analyticsService.GetEventQuotaUsage(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// callerOrgID resolves the caller's org scope from validated token claims.
+// Admin callers may select another org's partition explicitly via the
+// org_id query param; everyone else is pinned to their own org.
+func callerOrgID(c *gin.Context) string {
+	if c.GetString(middleware.AuthUserRoleKey) == "admin" {
+		if value := c.Query("org_id"); value != "" {
+			return value
+		}
+	}
+	return c.GetString(authOrgIDKey)
+}
+
 // User activity handlers
 func getUserActivity(c *gin.Context) {
-	userIDStr := c.Param("id")
-	_, err := uuid.Parse(userIDStr)
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	// Mock response
-	response := usecases.GetUserActivityResponse{
-		Activities: []*domain.UserActivity{},
-		Total:      0,
+	req := &usecases.GetUserActivityRequest{UserID: userID}
+	if value := callerOrgID(c); value != "" {
+		orgID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid org ID"})
+			return
+		}
+		req.OrgID = &orgID
+	}
+
+	response, err := analyticsService.GetUserActivity(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -251,6 +771,91 @@ func updateUserActivity(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User activity updated successfully"})
 }
 
+func getUserLeaderboard(c *gin.Context) {
+	metric := c.DefaultQuery("metric", "total_events")
+
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	response, err := analyticsService.GetTopUsersByMetric(c.Request.Context(), metric, startDate, endDate, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// reconcileUserActivity is an admin repair for one user's daily counters
+func reconcileUserActivity(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		if parsed, err := time.Parse("2006-01-02", value); err == nil {
+			date = parsed
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date"})
+			return
+		}
+	}
+
+	activity, err := analyticsService.ReconcileUserActivity(c.Request.Context(), userID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// reconcileAllUserActivity is the admin bulk variant for a whole day
+func reconcileAllUserActivity(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		if parsed, err := time.Parse("2006-01-02", value); err == nil {
+			date = parsed
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date"})
+			return
+		}
+	}
+
+	reconciled, err := analyticsService.ReconcileAllUserActivity(c.Request.Context(), date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reconciled": reconciled})
+}
+
+// anonymizeUserData strips the PII linkage of a deleted user's analytics data
+func anonymizeUserData(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	result, err := analyticsService.HandleUserDeletion(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // System metrics handlers
 func getSystemMetrics(c *gin.Context) {
 	response := usecases.GetSystemMetricsResponse{
@@ -359,6 +964,70 @@ func getSlowestEndpoints(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func getEndpointSummary(c *gin.Context) {
+	endpoint := c.Query("endpoint")
+	method := c.Query("method")
+	if endpoint == "" || method == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "endpoint and method are required"})
+		return
+	}
+
+	summary, err := analyticsService.GetEndpointSummary(c.Request.Context(), endpoint, method)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func getErrorBudget(c *gin.Context) {
+	sloTarget, err := strconv.ParseFloat(c.DefaultQuery("slo_target", "0.999"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slo_target"})
+		return
+	}
+
+	window := 30 * 24 * time.Hour
+	if value := c.Query("window"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			window = parsed
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window"})
+			return
+		}
+	}
+
+	response, err := analyticsService.GetErrorBudget(c.Request.Context(), &usecases.GetErrorBudgetRequest{
+		Endpoint:  c.Query("endpoint"),
+		Method:    c.Query("method"),
+		SLOTarget: sloTarget,
+		Window:    window,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func getStatusBreakdown(c *gin.Context) {
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	breakdown, err := analyticsService.GetStatusBreakdown(c.Request.Context(), c.Query("endpoint"), c.Query("method"), startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
 // Error metrics handlers
 func getErrorMetrics(c *gin.Context) {
 	response := usecases.GetErrorMetricsResponse{
@@ -388,6 +1057,26 @@ func getTopErrors(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// getTopErrorUsers is an admin-only view of which users are hitting the most
+// errors, for proactive support outreach
+func getTopErrorUsers(c *gin.Context) {
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	response, err := analyticsService.GetTopErrorUsers(c.Request.Context(), startDate, endDate, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Report handlers
 func generateReport(c *gin.Context) {
 	var req usecases.GenerateReportRequest
@@ -396,54 +1085,161 @@ func generateReport(c *gin.Context) {
 		return
 	}
 
-	// Increment reports generated counter
-	analyticsReportsGenerated.Inc()
+	// The generating user comes from validated token claims
+	userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+		return
+	}
 
-	// Mock report generation
-	report := &domain.Report{
-		ID:          uuid.New(),
-		Type:        req.ReportType,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-		Filters:     req.Filters,
-		Status:      domain.ReportStatusCompleted,
-		GeneratedBy: uuid.New(), // In real app, get from auth context
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+	report, err := analyticsService.GenerateReport(c.Request.Context(), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	analyticsReportsGenerated.Inc()
 	c.JSON(http.StatusCreated, report)
 }
 
 func getReports(c *gin.Context) {
-	response := usecases.GetReportsResponse{
-		Reports: []*domain.Report{},
-		Total:   0,
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	req := &usecases.GetReportsRequest{Limit: limit, Offset: offset}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		req.UserID = &userID
+	}
+	if value := c.Query("report_type"); value != "" {
+		reportType := domain.ReportType(value)
+		req.ReportType = &reportType
+	}
+
+	response, err := analyticsService.GetReports(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func getReportByID(c *gin.Context) {
-	reportIDStr := c.Param("id")
-	reportID, err := uuid.Parse(reportIDStr)
+	reportID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
 		return
 	}
 
-	// Mock report
-	report := &domain.Report{
-		ID:        reportID,
-		Type:      domain.ReportTypeDaily,
-		Status:    domain.ReportStatusCompleted,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	report, err := analyticsService.GetReportByID(c.Request.Context(), reportID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
 	}
 
 	c.JSON(http.StatusOK, report)
 }
 
+// reportDownloadSlots bounds concurrent report downloads/streams so
+// report-heavy periods can't exhaust memory and IO. Configurable via
+// ANALYTICS_MAX_CONCURRENT_REPORT_DOWNLOADS.
+var reportDownloadSlots = make(chan struct{}, reportDownloadLimit())
+
+func reportDownloadLimit() int {
+	if value := os.Getenv("ANALYTICS_MAX_CONCURRENT_REPORT_DOWNLOADS"); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return 4
+}
+
+// acquireReportDownloadSlot reserves a download slot without blocking; when
+// saturated the caller should return 503 with Retry-After
+func acquireReportDownloadSlot() bool {
+	select {
+	case reportDownloadSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func releaseReportDownloadSlot() {
+	<-reportDownloadSlots
+}
+
+// reportContentType maps a report format to its response content type
+func reportContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv"
+	case "pdf":
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
+
+// streamReport streams the stored report data to the client in chunks so
+// large reports never need to be buffered in full. Uses chunked transfer
+// encoding and stops early if the client disconnects.
+func streamReport(c *gin.Context) {
+	// Bound concurrent downloads; saturated means try again shortly
+	if !acquireReportDownloadSlot() {
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many concurrent report downloads; retry shortly"})
+		return
+	}
+	defer releaseReportDownloadSlot()
+
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	report, err := analyticsService.GetReportByID(c.Request.Context(), reportID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+	if report.Status != domain.ReportStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Report is %s", report.Status)})
+		return
+	}
+
+	c.Header("Content-Type", reportContentType(report.Format))
+	c.Header("Transfer-Encoding", "chunked")
+	c.Status(http.StatusOK)
+
+	const chunkSize = 32 * 1024
+	data := report.Data
+	ctx := c.Request.Context()
+
+	for offset := 0; offset < len(data); offset += chunkSize {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		end := offset + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := c.Writer.Write(data[offset:end]); err != nil {
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
 func deleteReport(c *gin.Context) {
 	reportIDStr := c.Param("id")
 	_, err := uuid.Parse(reportIDStr)
@@ -457,22 +1253,16 @@ func deleteReport(c *gin.Context) {
 
 // Dashboard handlers
 func getDashboardData(c *gin.Context) {
-	dashboard := &usecases.DashboardData{
-		TotalUsers:   1000,
-		ActiveUsers:  150,
-		TotalFiles:   5000,
-		TotalEvents:  10000,
-		TopFiles:     []*domain.FileMetrics{},
-		TopEndpoints: []*domain.APIMetrics{},
-		RecentErrors: []*domain.ErrorMetrics{},
-		UserActivity: []*domain.UserActivity{},
-		EventDistribution: map[string]int64{
-			"file_upload":   100,
-			"file_download": 250,
-			"user_login":    50,
-			"api_call":      1000,
-			"error":         10,
-		},
+	startDate, endDate, err := parseDashboardRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dashboard, err := analyticsService.GetDashboardData(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, dashboard)
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/analytics/domain"
@@ -12,53 +15,102 @@ import (
 
 // analyticsService implements the AnalyticsService interface
 type analyticsService struct {
-	repoManager domain.RepositoryManager
+	repoManager       domain.RepositoryManager
+	reportLimiter     *reportLimiter
+	fileMetricsBuffer *fileMetricsBuffer
+	ingestionLag      ingestionLagTracker
+	eventQuota        *eventQuotaTracker
 }
 
 // NewAnalyticsService creates a new analytics service instance
 func NewAnalyticsService(repoManager domain.RepositoryManager) AnalyticsService {
-	return &analyticsService{
-		repoManager: repoManager,
+	service := &analyticsService{
+		repoManager:   repoManager,
+		reportLimiter: newReportLimiter(maxConcurrentReports()),
+		eventQuota:    newEventQuotaTracker(),
 	}
+
+	// Buffer hot-file counter increments unless disabled (interval 0)
+	if interval := fileMetricsFlushInterval(); interval > 0 {
+		service.fileMetricsBuffer = newFileMetricsBuffer(repoManager, interval)
+	}
+
+	return service
 }
 
 // Event Service Methods
 
 func (s *analyticsService) TrackEvent(ctx context.Context, req *TrackEventRequest) error {
+	// Enforce the tenant's daily event quota before persisting
+	if err := s.eventQuota.consume(req.OrgID, 1); err != nil {
+		return err
+	}
+
 	event := &domain.Event{
-		ID:        uuid.New(),
-		UserID:    &req.UserID,
-		SessionID: req.SessionID,
-		Type:      req.EventType,
-		Action:    req.Action,
-		Resource:  req.Resource,
-		Metadata:  req.Metadata,
-		Timestamp: time.Now(),
-		CreatedAt: time.Now(),
+		ID:            uuid.New(),
+		SchemaVersion: CurrentEventSchemaVersion,
+		OrgID:         req.OrgID,
+		AppVersion:    req.AppVersion,
+		UserID:        &req.UserID,
+		SessionID:     req.SessionID,
+		Type:          req.EventType,
+		Action:        req.Action,
+		Resource:      req.Resource,
+		Metadata:      req.Metadata,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
 	}
 
 	if req.Timestamp != nil {
 		event.Timestamp = *req.Timestamp
 	}
 
-	return s.repoManager.Event().Create(ctx, event)
+	if err := s.repoManager.Event().Create(ctx, event); err != nil {
+		return err
+	}
+
+	// Track how far persistence trails event creation
+	s.ingestionLag.observe(event.Timestamp)
+	return nil
 }
 
 func (s *analyticsService) TrackBatchEvents(ctx context.Context, req *TrackBatchEventsRequest) error {
+	// Enforce the tenant's daily event quota per org in the batch
+	perOrg := make(map[*uuid.UUID]int64)
+	orgKeys := make(map[uuid.UUID]*uuid.UUID)
+	for i := range req.Events {
+		key := uuid.Nil
+		if req.Events[i].OrgID != nil {
+			key = *req.Events[i].OrgID
+		}
+		if _, seen := orgKeys[key]; !seen {
+			orgKeys[key] = req.Events[i].OrgID
+		}
+		perOrg[orgKeys[key]]++
+	}
+	for orgID, count := range perOrg {
+		if err := s.eventQuota.consume(orgID, count); err != nil {
+			return err
+		}
+	}
+
 	events := make([]*domain.Event, len(req.Events))
 	for i, eventReq := range req.Events {
 		// Create local copy to avoid implicit memory aliasing
 		userID := eventReq.UserID
 		event := &domain.Event{
-			ID:        uuid.New(),
-			UserID:    &userID,
-			SessionID: eventReq.SessionID,
-			Type:      eventReq.EventType,
-			Action:    eventReq.Action,
-			Resource:  eventReq.Resource,
-			Metadata:  eventReq.Metadata,
-			Timestamp: time.Now(),
-			CreatedAt: time.Now(),
+			ID:            uuid.New(),
+			SchemaVersion: CurrentEventSchemaVersion,
+			OrgID:         eventReq.OrgID,
+			AppVersion:    eventReq.AppVersion,
+			UserID:        &userID,
+			SessionID:     eventReq.SessionID,
+			Type:          eventReq.EventType,
+			Action:        eventReq.Action,
+			Resource:      eventReq.Resource,
+			Metadata:      eventReq.Metadata,
+			Timestamp:     time.Now(),
+			CreatedAt:     time.Now(),
 		}
 
 		if eventReq.Timestamp != nil {
@@ -68,7 +120,19 @@ func (s *analyticsService) TrackBatchEvents(ctx context.Context, req *TrackBatch
 		events[i] = event
 	}
 
-	return s.repoManager.Event().CreateBatch(ctx, events)
+	if err := s.repoManager.Event().CreateBatch(ctx, events); err != nil {
+		return err
+	}
+
+	// Track the oldest event in the batch for ingestion lag
+	oldest := time.Now()
+	for _, event := range events {
+		if event.Timestamp.Before(oldest) {
+			oldest = event.Timestamp
+		}
+	}
+	s.ingestionLag.observe(oldest)
+	return nil
 }
 
 func (s *analyticsService) GetEvents(ctx context.Context, req *GetEventsRequest) (*GetEventsResponse, error) {
@@ -81,43 +145,85 @@ func (s *analyticsService) GetEvents(ctx context.Context, req *GetEventsRequest)
 		offset = 0
 	}
 
-	var events []*domain.Event
-	var err error
-
-	if req.UserID != nil && req.StartDate != nil && req.EndDate != nil {
-		events, err = s.repoManager.Event().GetByUserAndDateRange(ctx, *req.UserID, *req.StartDate, *req.EndDate, limit, offset)
-	} else if req.UserID != nil {
-		events, err = s.repoManager.Event().GetByUserID(ctx, *req.UserID, limit, offset)
-	} else if req.EventType != nil {
-		events, err = s.repoManager.Event().GetByType(ctx, *req.EventType, limit, offset)
-	} else if req.StartDate != nil && req.EndDate != nil {
-		events, err = s.repoManager.Event().GetByDateRange(ctx, *req.StartDate, *req.EndDate, limit, offset)
-	} else {
+	// fetch selects the underlying query for one page of raw events
+	fetch := func(fetchLimit, fetchOffset int) ([]*domain.Event, error) {
+		if req.UserID != nil && req.StartDate != nil && req.EndDate != nil {
+			return s.repoManager.Event().GetByUserAndDateRange(ctx, *req.UserID, *req.StartDate, *req.EndDate, fetchLimit, fetchOffset)
+		} else if req.UserID != nil {
+			return s.repoManager.Event().GetByUserID(ctx, *req.UserID, fetchLimit, fetchOffset)
+		} else if req.EventType != nil {
+			return s.repoManager.Event().GetByType(ctx, *req.EventType, fetchLimit, fetchOffset)
+		} else if req.StartDate != nil && req.EndDate != nil {
+			return s.repoManager.Event().GetByDateRange(ctx, *req.StartDate, *req.EndDate, fetchLimit, fetchOffset)
+		}
 		// Default to recent events
 		endDate := time.Now()
 		startDate := endDate.AddDate(0, 0, -7) // Last 7 days
-		events, err = s.repoManager.Event().GetByDateRange(ctx, startDate, endDate, limit, offset)
+		return s.repoManager.Event().GetByDateRange(ctx, startDate, endDate, fetchLimit, fetchOffset)
 	}
 
-	if err != nil {
-		return nil, err
+	// Page through the raw events, applying the org scope (and optional
+	// version filter) BEFORE limit/offset so pagination stays correct.
+	const pageSize = 500
+	events := make([]*domain.Event, 0, limit)
+	matched := 0
+
+	for page := 0; ; page++ {
+		batch, err := fetch(pageSize, page*pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range batch {
+			// Upgrade historical events to the current schema
+			upgradeEvent(event)
+
+			// Scope to the caller's org so cross-org reads are impossible
+			if !sameOrg(event.OrgID, req.OrgID) {
+				continue
+			}
+			// Optional release/version segmentation
+			if req.AppVersion != "" && event.AppVersion != req.AppVersion {
+				continue
+			}
+
+			if matched >= offset && len(events) < limit {
+				events = append(events, event)
+			}
+			matched++
+		}
+
+		if len(batch) < pageSize || len(events) == limit {
+			break
+		}
 	}
 
 	return &GetEventsResponse{
 		Events:  events,
-		Total:   int64(len(events)),
+		Total:   int64(matched),
 		Limit:   limit,
 		Offset:  offset,
-		HasMore: len(events) == limit,
+		HasMore: int64(offset+len(events)) < int64(matched),
 	}, nil
 }
 
+// sameOrg reports whether an event's org matches the caller's scope. Events
+// without an org are visible only to callers without an org scope.
+func sameOrg(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
 func (s *analyticsService) GetEventsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) (*GetEventsResponse, error) {
 	events, err := s.repoManager.Event().GetByUserID(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
+	events = upgradeEvents(events)
+
 	return &GetEventsResponse{
 		Events:  events,
 		Total:   int64(len(events)),
@@ -133,6 +239,8 @@ func (s *analyticsService) GetEventsByType(ctx context.Context, eventType domain
 		return nil, err
 	}
 
+	events = upgradeEvents(events)
+
 	return &GetEventsResponse{
 		Events:  events,
 		Total:   int64(len(events)),
@@ -165,6 +273,481 @@ func (s *analyticsService) GetEventStats(ctx context.Context, startDate, endDate
 	return stats, nil
 }
 
+// GetRetentionCohorts buckets users by the week of their first event on or
+// after cohortStart and computes, for each cohort, the fraction of its users
+// active in each subsequent week. Aggregation is over distinct users.
+func (s *analyticsService) GetRetentionCohorts(ctx context.Context, cohortStart time.Time, periods int) (*RetentionCohortsResponse, error) {
+	if periods <= 0 {
+		return nil, fmt.Errorf("periods must be positive")
+	}
+
+	const week = 7 * 24 * time.Hour
+	cohortStart = cohortStart.Truncate(week)
+	windowEnd := cohortStart.Add(time.Duration(periods) * week)
+
+	// Collect first-seen week and active weeks per distinct user
+	firstWeek := make(map[uuid.UUID]int)
+	activeWeeks := make(map[uuid.UUID]map[int]bool)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, cohortStart, windowEnd, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			if event.UserID == nil {
+				continue
+			}
+			weekIndex := int(event.Timestamp.Sub(cohortStart) / week)
+			if weekIndex < 0 || weekIndex >= periods {
+				continue
+			}
+
+			userID := *event.UserID
+			if current, seen := firstWeek[userID]; !seen || weekIndex < current {
+				firstWeek[userID] = weekIndex
+			}
+			if activeWeeks[userID] == nil {
+				activeWeeks[userID] = make(map[int]bool)
+			}
+			activeWeeks[userID][weekIndex] = true
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// Build the cohort matrix
+	response := &RetentionCohortsResponse{
+		CohortStart: cohortStart,
+		Periods:     periods,
+		Cohorts:     make([]RetentionCohort, 0, periods),
+	}
+
+	for cohort := 0; cohort < periods; cohort++ {
+		observedPeriods := periods - cohort
+		activeCounts := make([]int64, observedPeriods)
+		var userCount int64
+
+		for userID, first := range firstWeek {
+			if first != cohort {
+				continue
+			}
+			userCount++
+			for offset := 0; offset < observedPeriods; offset++ {
+				if activeWeeks[userID][cohort+offset] {
+					activeCounts[offset]++
+				}
+			}
+		}
+
+		retention := make([]float64, observedPeriods)
+		for offset := range retention {
+			if userCount > 0 {
+				retention[offset] = float64(activeCounts[offset]) / float64(userCount)
+			}
+		}
+
+		response.Cohorts = append(response.Cohorts, RetentionCohort{
+			CohortWeek: cohortStart.Add(time.Duration(cohort) * week),
+			UserCount:  userCount,
+			Retention:  retention,
+		})
+	}
+
+	return response, nil
+}
+
+// GetFunnel computes, for an ordered list of event types, how many distinct
+// users completed each successive step within the range. A user completes
+// step N only after completing step N-1, optionally within the configured
+// conversion window.
+func (s *analyticsService) GetFunnel(ctx context.Context, req *GetFunnelRequest) (*GetFunnelResponse, error) {
+	if len(req.Steps) == 0 {
+		return nil, fmt.Errorf("funnel requires at least one step")
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return nil, fmt.Errorf("end_date must be after start_date")
+	}
+
+	// Collect each user's ordered event timestamps per step event type
+	stepTypes := make(map[domain.EventType]int, len(req.Steps))
+	for i, step := range req.Steps {
+		stepTypes[step.EventType] = i
+	}
+
+	userStepTimes := make(map[uuid.UUID][][]time.Time)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, req.StartDate, req.EndDate, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			stepIndex, relevant := stepTypes[event.Type]
+			if !relevant || event.UserID == nil {
+				continue
+			}
+			userID := *event.UserID
+			if userStepTimes[userID] == nil {
+				userStepTimes[userID] = make([][]time.Time, len(req.Steps))
+			}
+			userStepTimes[userID][stepIndex] = append(userStepTimes[userID][stepIndex], event.Timestamp)
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// Walk each user's events through the funnel in order
+	stepCounts := make([]int64, len(req.Steps))
+	for _, stepTimes := range userStepTimes {
+		for i := range stepTimes {
+			sort.Slice(stepTimes[i], func(a, b int) bool { return stepTimes[i][a].Before(stepTimes[i][b]) })
+		}
+
+		previousTime := req.StartDate
+		for i := range req.Steps {
+			completedAt, ok := firstTimeAtOrAfter(stepTimes[i], previousTime)
+			if !ok {
+				break
+			}
+			if i > 0 && req.ConversionWindow > 0 && completedAt.Sub(previousTime) > req.ConversionWindow {
+				break
+			}
+			stepCounts[i]++
+			previousTime = completedAt
+		}
+	}
+
+	response := &GetFunnelResponse{Steps: make([]FunnelStepResult, len(req.Steps))}
+	for i, step := range req.Steps {
+		result := FunnelStepResult{Step: step, Users: stepCounts[i]}
+		if i == 0 {
+			result.ConversionRate = 1
+		} else {
+			result.DropOff = stepCounts[i-1] - stepCounts[i]
+			if stepCounts[i-1] > 0 {
+				result.ConversionRate = float64(stepCounts[i]) / float64(stepCounts[i-1])
+			}
+		}
+		response.Steps[i] = result
+	}
+
+	return response, nil
+}
+
+// firstTimeAtOrAfter returns the earliest timestamp not before the reference
+func firstTimeAtOrAfter(times []time.Time, reference time.Time) (time.Time, bool) {
+	for _, t := range times {
+		if !t.Before(reference) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetCoOccurrence aggregates events per session and counts, for each pair of
+// event types, how many sessions contained both. The top pairs reveal which
+// user actions tend to happen together.
+func (s *analyticsService) GetCoOccurrence(ctx context.Context, start, end time.Time, limit int) (*GetCoOccurrenceResponse, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	// Collect the distinct event types seen in each session
+	sessionTypes := make(map[uuid.UUID]map[domain.EventType]bool)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, start, end, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			if event.SessionID == nil {
+				continue
+			}
+			sessionID := *event.SessionID
+			if sessionTypes[sessionID] == nil {
+				sessionTypes[sessionID] = make(map[domain.EventType]bool)
+			}
+			sessionTypes[sessionID][event.Type] = true
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// Count unordered type pairs across sessions
+	pairCounts := make(map[[2]domain.EventType]int64)
+	for _, types := range sessionTypes {
+		typeList := make([]domain.EventType, 0, len(types))
+		for eventType := range types {
+			typeList = append(typeList, eventType)
+		}
+		sort.Slice(typeList, func(i, j int) bool { return typeList[i] < typeList[j] })
+
+		for i := 0; i < len(typeList); i++ {
+			for j := i + 1; j < len(typeList); j++ {
+				pairCounts[[2]domain.EventType{typeList[i], typeList[j]}]++
+			}
+		}
+	}
+
+	pairs := make([]CoOccurrencePair, 0, len(pairCounts))
+	for pair, count := range pairCounts {
+		pairs = append(pairs, CoOccurrencePair{TypeA: pair[0], TypeB: pair[1], Sessions: count})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Sessions != pairs[j].Sessions {
+			return pairs[i].Sessions > pairs[j].Sessions
+		}
+		return pairs[i].TypeA < pairs[j].TypeA
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	return &GetCoOccurrenceResponse{
+		Pairs: pairs,
+		Total: int64(len(sessionTypes)),
+	}, nil
+}
+
+// GetErrorRateByVersion computes total and error event counts per app
+// version over the range, surfacing regressions introduced by a release
+func (s *analyticsService) GetErrorRateByVersion(ctx context.Context, start, end time.Time) (map[string]*VersionErrorRate, error) {
+	rates := make(map[string]*VersionErrorRate)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, start, end, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			version := event.AppVersion
+			if version == "" {
+				version = "unknown"
+			}
+			rate, exists := rates[version]
+			if !exists {
+				rate = &VersionErrorRate{AppVersion: version}
+				rates[version] = rate
+			}
+			rate.TotalEvents++
+			if event.Type == domain.EventTypeError {
+				rate.ErrorEvents++
+			}
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	for _, rate := range rates {
+		if rate.TotalEvents > 0 {
+			rate.ErrorRate = float64(rate.ErrorEvents) / float64(rate.TotalEvents)
+		}
+	}
+
+	return rates, nil
+}
+
+// anonymizationNamespace seeds the stable pseudonymous IDs substituted for
+// deleted users, so one user's events keep aggregating together without any
+// PII linkage
+var anonymizationNamespace = uuid.MustParse("9f2c1e17-5b0a-4c57-8f1e-3d94a6c2b7de")
+
+// deletedUserPolicy returns "anonymize" (default) or "delete", configurable
+// with the ANALYTICS_DELETED_USER_POLICY environment variable
+func deletedUserPolicy() string {
+	if policy := os.Getenv("ANALYTICS_DELETED_USER_POLICY"); policy == "delete" {
+		return "delete"
+	}
+	return "anonymize"
+}
+
+// HandleUserDeletion removes the PII linkage of a deleted user's analytics
+// data. With the anonymize policy, the user ID is replaced by a stable
+// pseudonymous token so aggregates stay intact; with the delete policy, the
+// rows are removed entirely.
+func (s *analyticsService) HandleUserDeletion(ctx context.Context, userID uuid.UUID) (*UserDeletionResult, error) {
+	result := &UserDeletionResult{Policy: deletedUserPolicy()}
+
+	if result.Policy == "delete" {
+		events, err := s.repoManager.Event().DeleteByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete events: %w", err)
+		}
+		activity, err := s.repoManager.UserActivity().DeleteByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete activity: %w", err)
+		}
+		result.EventsAffected = events
+		result.ActivityAffected = activity
+		return result, nil
+	}
+
+	// Stable pseudonym: the same user always maps to the same token
+	result.PseudonymousID = uuid.NewSHA1(anonymizationNamespace, []byte(userID.String()))
+
+	events, err := s.repoManager.Event().ReassignUser(ctx, userID, result.PseudonymousID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize events: %w", err)
+	}
+	activity, err := s.repoManager.UserActivity().ReassignUser(ctx, userID, result.PseudonymousID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize activity: %w", err)
+	}
+
+	result.EventsAffected = events
+	result.ActivityAffected = activity
+	return result, nil
+}
+
+// suspiciousDownloadThreshold is the distinct-file download count per window
+// that flags a subject, configurable via ANALYTICS_DOWNLOAD_ALERT_THRESHOLD
+func suspiciousDownloadThreshold() int {
+	if value := os.Getenv("ANALYTICS_DOWNLOAD_ALERT_THRESHOLD"); value != "" {
+		if threshold, err := strconv.Atoi(value); err == nil && threshold > 0 {
+			return threshold
+		}
+	}
+	return 100
+}
+
+// DetectSuspiciousDownloads analyzes file.download events over the window
+// and flags users and IPs fetching an unusual number of distinct files,
+// which indicates bulk scraping or data exfiltration. Each flagged subject
+// is also recorded as a security_alert event for downstream notification.
+func (s *analyticsService) DetectSuspiciousDownloads(ctx context.Context, window time.Duration) ([]*SuspiciousDownloadReport, error) {
+	if window <= 0 {
+		window = time.Hour
+	}
+	threshold := suspiciousDownloadThreshold()
+
+	end := time.Now()
+	start := end.Add(-window)
+
+	type downloadStats struct {
+		distinct map[uuid.UUID]bool
+		total    int64
+	}
+	byUser := make(map[uuid.UUID]*downloadStats)
+	byIP := make(map[string]*downloadStats)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, start, end, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			if event.Type != domain.EventTypeFileDownload {
+				continue
+			}
+
+			var fileID uuid.UUID
+			if event.ResourceID != nil {
+				fileID = *event.ResourceID
+			}
+
+			if event.UserID != nil {
+				stats := byUser[*event.UserID]
+				if stats == nil {
+					stats = &downloadStats{distinct: make(map[uuid.UUID]bool)}
+					byUser[*event.UserID] = stats
+				}
+				stats.distinct[fileID] = true
+				stats.total++
+			}
+			if event.IPAddress != "" {
+				stats := byIP[event.IPAddress]
+				if stats == nil {
+					stats = &downloadStats{distinct: make(map[uuid.UUID]bool)}
+					byIP[event.IPAddress] = stats
+				}
+				stats.distinct[fileID] = true
+				stats.total++
+			}
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	var reports []*SuspiciousDownloadReport
+	for userID, stats := range byUser {
+		if len(stats.distinct) < threshold {
+			continue
+		}
+		id := userID
+		reports = append(reports, &SuspiciousDownloadReport{
+			Subject:       "user",
+			UserID:        &id,
+			DistinctFiles: len(stats.distinct),
+			Downloads:     stats.total,
+			Threshold:     threshold,
+		})
+	}
+	for ip, stats := range byIP {
+		if len(stats.distinct) < threshold {
+			continue
+		}
+		reports = append(reports, &SuspiciousDownloadReport{
+			Subject:       "ip",
+			IPAddress:     ip,
+			DistinctFiles: len(stats.distinct),
+			Downloads:     stats.total,
+			Threshold:     threshold,
+		})
+	}
+
+	// Record each flagged subject as a security alert event so the
+	// notification pipeline can inform owners and admins
+	for _, report := range reports {
+		alert := &domain.Event{
+			ID:            uuid.New(),
+			SchemaVersion: CurrentEventSchemaVersion,
+			Type:          domain.EventTypeError,
+			Action:        "security_alert",
+			Resource:      "file_downloads",
+			UserID:        report.UserID,
+			IPAddress:     report.IPAddress,
+			Metadata: map[string]interface{}{
+				"category":       "security_alert",
+				"reason":         "suspicious_download_volume",
+				"distinct_files": report.DistinctFiles,
+				"downloads":      report.Downloads,
+			},
+			Timestamp: time.Now(),
+			CreatedAt: time.Now(),
+		}
+		if err := s.repoManager.Event().Create(ctx, alert); err != nil {
+			fmt.Printf("Failed to record security alert: %v\n", err)
+		}
+	}
+
+	return reports, nil
+}
+
 // User Activity Service Methods
 
 func (s *analyticsService) GetUserActivity(ctx context.Context, req *GetUserActivityRequest) (*GetUserActivityResponse, error) {
@@ -184,9 +767,17 @@ func (s *analyticsService) GetUserActivity(ctx context.Context, req *GetUserActi
 		return nil, err
 	}
 
+	// Scope results to the caller's org
+	scoped := make([]*domain.UserActivity, 0, len(activities))
+	for _, activity := range activities {
+		if sameOrg(activity.OrgID, req.OrgID) {
+			scoped = append(scoped, activity)
+		}
+	}
+
 	return &GetUserActivityResponse{
-		Activities: activities,
-		Total:      int64(len(activities)),
+		Activities: scoped,
+		Total:      int64(len(scoped)),
 	}, nil
 }
 
@@ -207,6 +798,37 @@ func (s *analyticsService) GetTopActiveUsers(ctx context.Context, req *GetTopUse
 	}, nil
 }
 
+// leaderboardMetrics is the allowlist of metrics users may rank by
+var leaderboardMetrics = map[string]bool{
+	"total_events":   true,
+	"file_uploads":   true,
+	"file_downloads": true,
+	"file_views":     true,
+	"file_shares":    true,
+	"api_calls":      true,
+}
+
+// GetTopUsersByMetric returns a user leaderboard ranked by the chosen
+// activity metric. Metric names are validated against an allowlist.
+func (s *analyticsService) GetTopUsersByMetric(ctx context.Context, metric string, startDate, endDate time.Time, limit int) (*GetTopUsersResponse, error) {
+	if !leaderboardMetrics[metric] {
+		return nil, fmt.Errorf("unsupported leaderboard metric %q", metric)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	users, err := s.repoManager.UserActivity().GetTopUsersByMetric(ctx, metric, startDate, endDate, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTopUsersResponse{
+		Users: users,
+		Total: int64(len(users)),
+	}, nil
+}
+
 func (s *analyticsService) UpdateUserActivity(ctx context.Context, userID uuid.UUID, action string) error {
 	today := time.Now().Truncate(24 * time.Hour)
 	activity, err := s.repoManager.UserActivity().GetByUserAndDate(ctx, userID, today)
@@ -255,6 +877,104 @@ func (s *analyticsService) UpdateUserActivity(ctx context.Context, userID uuid.U
 	return s.repoManager.UserActivity().Update(ctx, activity)
 }
 
+// ReconcileUserActivity recomputes the day's activity counters for a user
+// directly from raw events and corrects the stored UserActivity row.
+// Increment-based counters drift after failures; this restores ground truth.
+func (s *analyticsService) ReconcileUserActivity(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.UserActivity, error) {
+	day := date.Truncate(24 * time.Hour)
+	dayEnd := day.Add(24 * time.Hour)
+
+	recomputed := &domain.UserActivity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Date:      day,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByUserAndDateRange(ctx, userID, day, dayEnd, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			recomputed.TotalEvents++
+			switch event.Type {
+			case domain.EventTypeFileUpload:
+				recomputed.FileUploads++
+			case domain.EventTypeFileDownload:
+				recomputed.FileDownloads++
+			case domain.EventTypeFileView:
+				recomputed.FileViews++
+			case domain.EventTypeFileShare:
+				recomputed.FileShares++
+			case domain.EventTypeAPICall:
+				recomputed.APICallsCount++
+			case domain.EventTypeError:
+				recomputed.ErrorsCount++
+			}
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// Correct the stored row, preserving its identity when one exists
+	existing, err := s.repoManager.UserActivity().GetByUserAndDate(ctx, userID, day)
+	if err == nil && existing != nil {
+		recomputed.ID = existing.ID
+		recomputed.OrgID = existing.OrgID
+		recomputed.CreatedAt = existing.CreatedAt
+		recomputed.SessionDuration = existing.SessionDuration
+		if err := s.repoManager.UserActivity().Update(ctx, recomputed); err != nil {
+			return nil, fmt.Errorf("failed to update activity row: %w", err)
+		}
+		return recomputed, nil
+	}
+
+	if err := s.repoManager.UserActivity().Create(ctx, recomputed); err != nil {
+		return nil, fmt.Errorf("failed to create activity row: %w", err)
+	}
+	return recomputed, nil
+}
+
+// ReconcileAllUserActivity reconciles every user with events on the date
+func (s *analyticsService) ReconcileAllUserActivity(ctx context.Context, date time.Time) (int, error) {
+	day := date.Truncate(24 * time.Hour)
+	dayEnd := day.Add(24 * time.Hour)
+
+	users := make(map[uuid.UUID]bool)
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, day, dayEnd, pageSize, offset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load events: %w", err)
+		}
+		for _, event := range events {
+			if event.UserID != nil {
+				users[*event.UserID] = true
+			}
+		}
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	reconciled := 0
+	for userID := range users {
+		if _, err := s.ReconcileUserActivity(ctx, userID, day); err != nil {
+			fmt.Printf("Failed to reconcile activity for user %s: %v\n", userID, err)
+			continue
+		}
+		reconciled++
+	}
+
+	return reconciled, nil
+}
+
 func (s *analyticsService) GetUserStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (*domain.UserActivity, error) {
 	return s.repoManager.UserActivity().GetAggregatedByDateRange(ctx, startDate, endDate)
 }
@@ -296,6 +1016,23 @@ func (s *analyticsService) UpdateSystemMetrics(ctx context.Context, metrics *dom
 	return s.repoManager.SystemMetrics().Create(ctx, metrics)
 }
 
+// GetEventQuotaUsage reports the tenant's current daily quota consumption
+func (s *analyticsService) GetEventQuotaUsage(ctx context.Context, orgID uuid.UUID) (*EventQuotaUsage, error) {
+	used, rejected, quota := s.eventQuota.usage(orgID)
+	return &EventQuotaUsage{
+		OrgID:    orgID,
+		Used:     used,
+		Rejected: rejected,
+		Quota:    quota,
+		Date:     time.Now().UTC().Format("2006-01-02"),
+	}, nil
+}
+
+// GetIngestionLag returns the delay between event creation and persistence
+func (s *analyticsService) GetIngestionLag(ctx context.Context) (time.Duration, error) {
+	return s.ingestionLag.lag(), nil
+}
+
 func (s *analyticsService) GetSystemHealth(ctx context.Context) (map[string]interface{}, error) {
 	latest, err := s.repoManager.SystemMetrics().GetLatest(ctx)
 	if err != nil {
@@ -315,6 +1052,15 @@ func (s *analyticsService) GetSystemHealth(ctx context.Context) (map[string]inte
 		"last_updated":  latest.Date,
 	}
 
+	// Include event ingestion lag so operators see when analytics data
+	// falls behind
+	lag := s.ingestionLag.lag()
+	health["ingestion_lag"] = lag.String()
+	if lag > IngestionLagThreshold() {
+		health["status"] = "warning"
+		health["ingestion_lag_alert"] = true
+	}
+
 	// Determine health status based on metrics
 	if latest.ErrorRate > 2 {
 		health["status"] = "warning"
@@ -366,15 +1112,26 @@ func (s *analyticsService) GetFileMetrics(ctx context.Context, req *GetFileMetri
 }
 
 func (s *analyticsService) UpdateFileMetrics(ctx context.Context, req *UpdateFileMetricsRequest) error {
+	switch req.MetricType {
+	case "view", "download", "share":
+	default:
+		return fmt.Errorf("invalid metric type: %s", req.MetricType)
+	}
+
+	// Buffer increments for hot files; they are flushed in aggregate on the
+	// configured interval
+	if s.fileMetricsBuffer != nil {
+		s.fileMetricsBuffer.add(req.FileID, req.MetricType)
+		return nil
+	}
+
 	switch req.MetricType {
 	case "view":
 		return s.repoManager.FileMetrics().IncrementViewCount(ctx, req.FileID)
 	case "download":
 		return s.repoManager.FileMetrics().IncrementDownloadCount(ctx, req.FileID)
-	case "share":
-		return s.repoManager.FileMetrics().IncrementShareCount(ctx, req.FileID)
 	default:
-		return fmt.Errorf("invalid metric type: %s", req.MetricType)
+		return s.repoManager.FileMetrics().IncrementShareCount(ctx, req.FileID)
 	}
 }
 
@@ -448,6 +1205,19 @@ func (s *analyticsService) TrackAPICall(ctx context.Context, endpoint, method st
 		metrics.ErrorCount++
 	}
 
+	// Per-class counts distinguish client errors from server errors, which
+	// the success/error split obscures
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		metrics.Count2xx++
+	case statusCode >= 300 && statusCode < 400:
+		metrics.Count3xx++
+	case statusCode >= 400 && statusCode < 500:
+		metrics.Count4xx++
+	case statusCode >= 500:
+		metrics.Count5xx++
+	}
+
 	// Update response time statistics
 	latencyMs := float64(responseTime.Milliseconds())
 	if metrics.RequestCount == 1 {
@@ -499,6 +1269,104 @@ func (s *analyticsService) GetSlowestEndpoints(ctx context.Context, startDate, e
 	}, nil
 }
 
+// GetEndpointSummary aggregates an endpoint's per-day metric rows into a
+// single all-time rollup
+func (s *analyticsService) GetEndpointSummary(ctx context.Context, endpoint, method string) (*domain.APIMetricsSummary, error) {
+	if endpoint == "" || method == "" {
+		return nil, fmt.Errorf("endpoint and method are required")
+	}
+	return s.repoManager.APIMetrics().GetEndpointSummary(ctx, endpoint, method)
+}
+
+// GetStatusBreakdown aggregates an endpoint's per-day rows into a status-code
+// class distribution over the range
+func (s *analyticsService) GetStatusBreakdown(ctx context.Context, endpoint, method string, start, end time.Time) (*StatusBreakdown, error) {
+	if endpoint == "" || method == "" {
+		return nil, fmt.Errorf("endpoint and method are required")
+	}
+
+	metrics, err := s.repoManager.APIMetrics().GetByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API metrics: %w", err)
+	}
+
+	breakdown := &StatusBreakdown{Endpoint: endpoint, Method: method}
+	for _, metric := range metrics {
+		if metric.Endpoint != endpoint || metric.Method != method {
+			continue
+		}
+		breakdown.TotalRequests += metric.RequestCount
+		breakdown.Count2xx += metric.Count2xx
+		breakdown.Count3xx += metric.Count3xx
+		breakdown.Count4xx += metric.Count4xx
+		breakdown.Count5xx += metric.Count5xx
+	}
+
+	return breakdown, nil
+}
+
+// GetErrorBudget turns raw API metrics into SLO-centric signals: how much of
+// the error budget the endpoint has burned over the window, the current burn
+// rate, and when the budget will be exhausted at that rate.
+func (s *analyticsService) GetErrorBudget(ctx context.Context, req *GetErrorBudgetRequest) (*GetErrorBudgetResponse, error) {
+	if req.SLOTarget <= 0 || req.SLOTarget >= 1 {
+		return nil, fmt.Errorf("slo_target must be between 0 and 1 exclusive")
+	}
+	window := req.Window
+	if window <= 0 {
+		window = 30 * 24 * time.Hour
+	}
+
+	end := time.Now()
+	start := end.Add(-window)
+
+	metrics, err := s.repoManager.APIMetrics().GetByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API metrics: %w", err)
+	}
+
+	var totalRequests, errorCount int64
+	for _, metric := range metrics {
+		if metric.Endpoint != req.Endpoint || metric.Method != req.Method {
+			continue
+		}
+		totalRequests += metric.RequestCount
+		errorCount += metric.ErrorCount
+	}
+
+	response := &GetErrorBudgetResponse{
+		Endpoint:    req.Endpoint,
+		Method:      req.Method,
+		SLOTarget:   req.SLOTarget,
+		SuccessRate: 1,
+	}
+	if totalRequests == 0 {
+		return response, nil
+	}
+
+	errorRate := float64(errorCount) / float64(totalRequests)
+	allowedErrorRate := 1 - req.SLOTarget
+
+	response.SuccessRate = 1 - errorRate
+	response.BurnRate = errorRate / allowedErrorRate
+
+	// Budget consumed: fraction of the window's allowed errors already spent
+	allowedErrors := allowedErrorRate * float64(totalRequests)
+	response.BudgetConsumed = float64(errorCount) / allowedErrors
+
+	// Project exhaustion assuming the current burn rate continues
+	if response.BurnRate > 0 && response.BudgetConsumed < 1 {
+		remaining := 1 - response.BudgetConsumed
+		timeToExhaustion := time.Duration(remaining / response.BurnRate * float64(window))
+		exhaustion := end.Add(timeToExhaustion)
+		response.ProjectedExhaustion = &exhaustion
+	} else if response.BudgetConsumed >= 1 {
+		response.ProjectedExhaustion = &end
+	}
+
+	return response, nil
+}
+
 // Error Metrics Service Methods
 
 func (s *analyticsService) GetErrorMetrics(ctx context.Context, req *GetErrorMetricsRequest) (*GetErrorMetricsResponse, error) {
@@ -554,9 +1422,99 @@ func (s *analyticsService) GetErrorStats(ctx context.Context, startDate, endDate
 	return stats, nil
 }
 
+// GetTopErrorUsers aggregates error events per user over the range and
+// returns the users with the most errors along with each user's predominant
+// error type, for proactive support outreach.
+func (s *analyticsService) GetTopErrorUsers(ctx context.Context, startDate, endDate time.Time, limit int) (*GetTopErrorUsersResponse, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// Count errors and error types per user from raw events
+	errorCounts := make(map[uuid.UUID]int64)
+	errorTypes := make(map[uuid.UUID]map[string]int64)
+
+	const pageSize = 1000
+	for offset := 0; ; offset += pageSize {
+		events, err := s.repoManager.Event().GetByDateRange(ctx, startDate, endDate, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load events: %w", err)
+		}
+
+		for _, event := range events {
+			if event.Type != domain.EventTypeError || event.UserID == nil {
+				continue
+			}
+
+			userID := *event.UserID
+			errorCounts[userID]++
+
+			errorType, _ := event.Metadata["error_type"].(string)
+			if errorType == "" {
+				errorType = event.Action
+			}
+			if errorTypes[userID] == nil {
+				errorTypes[userID] = make(map[string]int64)
+			}
+			errorTypes[userID][errorType]++
+		}
+
+		if len(events) < pageSize {
+			break
+		}
+	}
+
+	// Rank users by error count
+	users := make([]*UserErrorStats, 0, len(errorCounts))
+	for userID, count := range errorCounts {
+		stats := &UserErrorStats{
+			UserID:     userID,
+			ErrorCount: count,
+		}
+
+		var topCount int64
+		for errorType, typeCount := range errorTypes[userID] {
+			if typeCount > topCount {
+				topCount = typeCount
+				stats.TopErrorType = errorType
+			}
+		}
+
+		users = append(users, stats)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ErrorCount > users[j].ErrorCount
+	})
+
+	total := int64(len(users))
+	if len(users) > limit {
+		users = users[:limit]
+	}
+
+	return &GetTopErrorUsersResponse{
+		Users: users,
+		Total: total,
+	}, nil
+}
+
 // Report Service Methods
 
 func (s *analyticsService) GenerateReport(ctx context.Context, req *GenerateReportRequest, userID uuid.UUID) (*domain.Report, error) {
+	// Parse and validate the filter expression up front so invalid requests
+	// fail before any data is queried
+	filter, err := parseReportFilters(req.ReportType, req.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report filters: %w", err)
+	}
+
+	// Bound concurrent report generations globally; excess requests queue
+	// here until a slot frees up
+	if err := s.reportLimiter.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("report generation cancelled while queued: %w", err)
+	}
+	defer s.reportLimiter.release()
+
 	report := &domain.Report{
 		ID:          uuid.New(),
 		GeneratedBy: userID,
@@ -564,14 +1522,18 @@ func (s *analyticsService) GenerateReport(ctx context.Context, req *GenerateRepo
 		StartDate:   req.StartDate,
 		EndDate:     req.EndDate,
 		Filters:     req.Filters,
+		Format:      req.Format,
 		Status:      domain.ReportStatusPending,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
+	if report.Format == "" {
+		report.Format = "json"
+	}
+
 	// Generate report data based on type
 	var data interface{}
-	var err error
 
 	switch req.ReportType {
 	case domain.ReportTypeUserActivity:
@@ -597,6 +1559,12 @@ func (s *analyticsService) GenerateReport(ctx context.Context, req *GenerateRepo
 		return nil, err
 	}
 
+	// Apply the validated filters to the generated rows
+	data, err = applyReportFilters(data, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert data to JSON
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
@@ -622,6 +1590,16 @@ func (s *analyticsService) GenerateReport(ctx context.Context, req *GenerateRepo
 	return report, nil
 }
 
+// GetReportGenerationStats exposes the limiter's queue depth and active count
+func (s *analyticsService) GetReportGenerationStats(ctx context.Context) (*ReportGenerationStats, error) {
+	queued, active, limit := s.reportLimiter.stats()
+	return &ReportGenerationStats{
+		ActiveCount: active,
+		QueueDepth:  queued,
+		Limit:       limit,
+	}, nil
+}
+
 func (s *analyticsService) GetReports(ctx context.Context, req *GetReportsRequest) (*GetReportsResponse, error) {
 	limit := req.Limit
 	if limit <= 0 {
@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyEventQuota returns the per-tenant daily event quota; 0 disables
+// enforcement. Override with ANALYTICS_DAILY_EVENT_QUOTA.
+func DailyEventQuota() int64 {
+	if value := os.Getenv("ANALYTICS_DAILY_EVENT_QUOTA"); value != "" {
+		if quota, err := strconv.ParseInt(value, 10, 64); err == nil && quota >= 0 {
+			return quota
+		}
+	}
+	return 0
+}
+
+// QuotaExceededError is returned when a tenant's daily event quota is
+// exhausted, so callers can distinguish it from transient failures
+type QuotaExceededError struct {
+	OrgID uuid.UUID
+	Quota int64
+}
+
+// Error implements the error interface
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("daily event quota of %d exhausted for org %s", e.Quota, e.OrgID)
+}
+
+// eventQuotaTracker enforces the per-tenant daily event quota with
+// in-memory day-bucketed counters. Events without an org share one bucket.
+type eventQuotaTracker struct {
+	mu       sync.Mutex
+	day      string
+	counts   map[uuid.UUID]int64
+	rejected map[uuid.UUID]int64
+}
+
+func newEventQuotaTracker() *eventQuotaTracker {
+	return &eventQuotaTracker{
+		counts:   make(map[uuid.UUID]int64),
+		rejected: make(map[uuid.UUID]int64),
+	}
+}
+
+// rollDay resets the counters when the UTC day changes; callers must hold mu
+func (t *eventQuotaTracker) rollDay() {
+	today := time.Now().UTC().Format("2006-01-02")
+	if t.day != today {
+		t.day = today
+		t.counts = make(map[uuid.UUID]int64)
+		t.rejected = make(map[uuid.UUID]int64)
+	}
+}
+
+// consume reserves count events for the tenant, returning a
+// QuotaExceededError when the day's quota is exhausted
+func (t *eventQuotaTracker) consume(orgID *uuid.UUID, count int64) error {
+	quota := DailyEventQuota()
+	if quota <= 0 {
+		return nil
+	}
+
+	key := uuid.Nil
+	if orgID != nil {
+		key = *orgID
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollDay()
+
+	if t.counts[key]+count > quota {
+		t.rejected[key] += count
+		return &QuotaExceededError{OrgID: key, Quota: quota}
+	}
+	t.counts[key] += count
+	return nil
+}
+
+// usage reports the tenant's consumption for the current day
+func (t *eventQuotaTracker) usage(orgID uuid.UUID) (used, rejected, quota int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollDay()
+	return t.counts[orgID], t.rejected[orgID], DailyEventQuota()
+}
+
+// EventQuotaUsage reports a tenant's daily quota consumption
+type EventQuotaUsage struct {
+	OrgID    uuid.UUID `json:"org_id"`
+	Used     int64     `json:"used"`
+	Rejected int64     `json:"rejected"`
+	Quota    int64     `json:"quota"` // 0 means unlimited
+	Date     string    `json:"date"`
+}
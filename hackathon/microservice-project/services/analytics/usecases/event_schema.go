@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"github.com/elotusteam/microservice-project/services/analytics/domain"
+	"github.com/google/uuid"
+)
+
+// CurrentEventSchemaVersion is the schema version new events are written
+// with. Older events are upgraded on read so consumers always see the
+// current shape regardless of when an event was recorded.
+const CurrentEventSchemaVersion = 2
+
+// upgradeEvent migrates an event to the current schema version in place.
+// Each case upgrades one version step so the chain stays easy to extend as
+// the event definition evolves.
+func upgradeEvent(event *domain.Event) *domain.Event {
+	if event == nil {
+		return nil
+	}
+
+	for event.SchemaVersion < CurrentEventSchemaVersion {
+		switch event.SchemaVersion {
+		case 0, 1:
+			// v1 events (and pre-versioning events, recorded as 0) kept the
+			// resource identifier and client details in the metadata map
+			if event.Metadata != nil {
+				if raw, ok := event.Metadata["resource_id"].(string); ok && event.ResourceID == nil {
+					if id, err := uuid.Parse(raw); err == nil {
+						event.ResourceID = &id
+						delete(event.Metadata, "resource_id")
+					}
+				}
+				if ip, ok := event.Metadata["ip"].(string); ok && event.IPAddress == "" {
+					event.IPAddress = ip
+					delete(event.Metadata, "ip")
+				}
+				if ua, ok := event.Metadata["ua"].(string); ok && event.UserAgent == "" {
+					event.UserAgent = ua
+					delete(event.Metadata, "ua")
+				}
+			}
+			event.SchemaVersion = 2
+		default:
+			// Unknown intermediate version; stamp current and stop
+			event.SchemaVersion = CurrentEventSchemaVersion
+		}
+	}
+
+	return event
+}
+
+// upgradeEvents migrates a batch of events to the current schema version
+func upgradeEvents(events []*domain.Event) []*domain.Event {
+	for _, event := range events {
+		upgradeEvent(event)
+	}
+	return events
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/analytics/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultFileMetricsFlushInterval is how often buffered file-metric
+// increments are flushed to the database. Override with the
+// ANALYTICS_FILE_METRICS_FLUSH_INTERVAL environment variable; a value of 0
+// disables buffering and writes every increment through.
+const DefaultFileMetricsFlushInterval = 10 * time.Second
+
+func fileMetricsFlushInterval() time.Duration {
+	if value := os.Getenv("ANALYTICS_FILE_METRICS_FLUSH_INTERVAL"); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval >= 0 {
+			return interval
+		}
+	}
+	return DefaultFileMetricsFlushInterval
+}
+
+// fileMetricsBuffer accumulates per-file view/download/share increments in
+// memory and flushes aggregated deltas periodically, turning a DB write per
+// access on hot files into one write per file per interval. A small loss
+// window on crash is the accepted trade-off.
+type fileMetricsBuffer struct {
+	repoManager domain.RepositoryManager
+	interval    time.Duration
+
+	mu     sync.Mutex
+	deltas map[uuid.UUID]map[string]int64
+
+	startOnce sync.Once
+	stopCh    chan struct{}
+}
+
+func newFileMetricsBuffer(repoManager domain.RepositoryManager, interval time.Duration) *fileMetricsBuffer {
+	return &fileMetricsBuffer{
+		repoManager: repoManager,
+		interval:    interval,
+		deltas:      make(map[uuid.UUID]map[string]int64),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// add records one increment and lazily starts the flush loop
+func (b *fileMetricsBuffer) add(fileID uuid.UUID, metricType string) {
+	b.startOnce.Do(func() {
+		go b.run()
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.deltas[fileID] == nil {
+		b.deltas[fileID] = make(map[string]int64)
+	}
+	b.deltas[fileID][metricType]++
+}
+
+// run flushes on the configured interval until stopped
+func (b *fileMetricsBuffer) run() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flush(context.Background())
+		case <-b.stopCh:
+			b.flush(context.Background())
+			return
+		}
+	}
+}
+
+// flush writes all buffered deltas to the database
+func (b *fileMetricsBuffer) flush(ctx context.Context) {
+	b.mu.Lock()
+	pending := b.deltas
+	b.deltas = make(map[uuid.UUID]map[string]int64)
+	b.mu.Unlock()
+
+	for fileID, metrics := range pending {
+		for metricType, count := range metrics {
+			for i := int64(0); i < count; i++ {
+				var err error
+				switch metricType {
+				case "view":
+					err = b.repoManager.FileMetrics().IncrementViewCount(ctx, fileID)
+				case "download":
+					err = b.repoManager.FileMetrics().IncrementDownloadCount(ctx, fileID)
+				case "share":
+					err = b.repoManager.FileMetrics().IncrementShareCount(ctx, fileID)
+				}
+				if err != nil {
+					fmt.Printf("Failed to flush %s metric for file %s: %v\n", metricType, fileID, err)
+					break
+				}
+			}
+		}
+	}
+}
+
+// stop halts the flush loop after a final flush (shutdown hook)
+func (b *fileMetricsBuffer) stop() {
+	b.startOnce.Do(func() {}) // ensure run() is never started afterwards
+	select {
+	case <-b.stopCh:
+	default:
+		close(b.stopCh)
+	}
+}
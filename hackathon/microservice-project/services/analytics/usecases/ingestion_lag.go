@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultIngestionLagThreshold is the lag above which the health detail
+// reports analytics as falling behind. Override with
+// ANALYTICS_INGESTION_LAG_THRESHOLD.
+const DefaultIngestionLagThreshold = 5 * time.Minute
+
+// IngestionLagThreshold returns the configured alerting threshold
+func IngestionLagThreshold() time.Duration {
+	if value := os.Getenv("ANALYTICS_INGESTION_LAG_THRESHOLD"); value != "" {
+		if threshold, err := time.ParseDuration(value); err == nil && threshold > 0 {
+			return threshold
+		}
+	}
+	return DefaultIngestionLagThreshold
+}
+
+// ingestionLagTracker records the delay between event creation and
+// persistence so operators can tell when analytics data is falling behind
+type ingestionLagTracker struct {
+	mu         sync.Mutex
+	currentLag time.Duration
+	observedAt time.Time
+}
+
+// observe records the lag of a just-persisted event
+func (t *ingestionLagTracker) observe(eventTimestamp time.Time) {
+	lag := time.Since(eventTimestamp)
+	if lag < 0 {
+		lag = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.currentLag = lag
+	t.observedAt = time.Now()
+}
+
+// lag returns the most recently observed ingestion lag
+func (t *ingestionLagTracker) lag() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.currentLag
+}
@@ -10,13 +10,15 @@ import (
 
 // Event tracking requests and responses
 type TrackEventRequest struct {
-	UserID    uuid.UUID              `json:"user_id" binding:"required"`
-	SessionID *uuid.UUID             `json:"session_id,omitempty"`
-	EventType domain.EventType       `json:"event_type" binding:"required"`
-	Action    string                 `json:"action" binding:"required"`
-	Resource  string                 `json:"resource,omitempty"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	Timestamp *time.Time             `json:"timestamp,omitempty"`
+	UserID     uuid.UUID              `json:"user_id" binding:"required"`
+	OrgID      *uuid.UUID             `json:"org_id,omitempty"`
+	SessionID  *uuid.UUID             `json:"session_id,omitempty"`
+	EventType  domain.EventType       `json:"event_type" binding:"required"`
+	Action     string                 `json:"action" binding:"required"`
+	Resource   string                 `json:"resource,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	AppVersion string                 `json:"app_version,omitempty"`
+	Timestamp  *time.Time             `json:"timestamp,omitempty"`
 }
 
 type TrackBatchEventsRequest struct {
@@ -24,12 +26,14 @@ type TrackBatchEventsRequest struct {
 }
 
 type GetEventsRequest struct {
-	UserID    *uuid.UUID        `json:"user_id,omitempty"`
-	EventType *domain.EventType `json:"event_type,omitempty"`
-	StartDate *time.Time        `json:"start_date,omitempty"`
-	EndDate   *time.Time        `json:"end_date,omitempty"`
-	Limit     int               `json:"limit,omitempty"`
-	Offset    int               `json:"offset,omitempty"`
+	OrgID      *uuid.UUID        `json:"org_id,omitempty"`
+	AppVersion string            `json:"app_version,omitempty"`
+	UserID     *uuid.UUID        `json:"user_id,omitempty"`
+	EventType  *domain.EventType `json:"event_type,omitempty"`
+	StartDate  *time.Time        `json:"start_date,omitempty"`
+	EndDate    *time.Time        `json:"end_date,omitempty"`
+	Limit      int               `json:"limit,omitempty"`
+	Offset     int               `json:"offset,omitempty"`
 }
 
 type GetEventsResponse struct {
@@ -40,9 +44,93 @@ type GetEventsResponse struct {
 	HasMore bool            `json:"has_more"`
 }
 
+// RetentionCohort represents one weekly cohort and its retention curve.
+// Retention[0] is always 1.0 (the cohort week itself); Retention[p] is the
+// fraction of the cohort active p weeks later.
+type RetentionCohort struct {
+	CohortWeek time.Time `json:"cohort_week"`
+	UserCount  int64     `json:"user_count"`
+	Retention  []float64 `json:"retention"`
+}
+
+type RetentionCohortsResponse struct {
+	CohortStart time.Time         `json:"cohort_start"`
+	Periods     int               `json:"periods"`
+	Cohorts     []RetentionCohort `json:"cohorts"`
+}
+
+// EventStep is one step of a funnel, identified by event type
+type EventStep struct {
+	EventType domain.EventType `json:"event_type" binding:"required"`
+	Name      string           `json:"name,omitempty"`
+}
+
+type GetFunnelRequest struct {
+	Steps     []EventStep `json:"steps" binding:"required"`
+	StartDate time.Time   `json:"start_date" binding:"required"`
+	EndDate   time.Time   `json:"end_date" binding:"required"`
+
+	// ConversionWindow optionally bounds the time allowed between successive
+	// steps; zero means any time within the range counts
+	ConversionWindow time.Duration `json:"conversion_window,omitempty"`
+}
+
+// FunnelStepResult reports completion and drop-off for one funnel step
+type FunnelStepResult struct {
+	Step           EventStep `json:"step"`
+	Users          int64     `json:"users"`
+	ConversionRate float64   `json:"conversion_rate"` // fraction of previous step's users
+	DropOff        int64     `json:"drop_off"`        // users lost from the previous step
+}
+
+type GetFunnelResponse struct {
+	Steps []FunnelStepResult `json:"steps"`
+}
+
+// CoOccurrencePair is a pair of event types observed in the same session,
+// with how many sessions contained both
+type CoOccurrencePair struct {
+	TypeA    domain.EventType `json:"type_a"`
+	TypeB    domain.EventType `json:"type_b"`
+	Sessions int64            `json:"sessions"`
+}
+
+type GetCoOccurrenceResponse struct {
+	Pairs []CoOccurrencePair `json:"pairs"`
+	Total int64              `json:"total"` // sessions considered
+}
+
+// VersionErrorRate is the error rate of one app version over a range
+type VersionErrorRate struct {
+	AppVersion  string  `json:"app_version"`
+	TotalEvents int64   `json:"total_events"`
+	ErrorEvents int64   `json:"error_events"`
+	ErrorRate   float64 `json:"error_rate"`
+}
+
+// SuspiciousDownloadReport flags one user or IP whose download behavior over
+// the window looked like bulk scraping
+type SuspiciousDownloadReport struct {
+	Subject       string     `json:"subject"` // "user" or "ip"
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	IPAddress     string     `json:"ip_address,omitempty"`
+	DistinctFiles int        `json:"distinct_files"`
+	Downloads     int64      `json:"downloads"`
+	Threshold     int        `json:"threshold"`
+}
+
+// UserDeletionResult reports what happened to a deleted user's analytics data
+type UserDeletionResult struct {
+	Policy           string    `json:"policy"` // "anonymize" or "delete"
+	PseudonymousID   uuid.UUID `json:"pseudonymous_id,omitempty"`
+	EventsAffected   int64     `json:"events_affected"`
+	ActivityAffected int64     `json:"activity_affected"`
+}
+
 // User activity requests and responses
 type GetUserActivityRequest struct {
 	UserID    uuid.UUID  `json:"user_id" binding:"required"`
+	OrgID     *uuid.UUID `json:"org_id,omitempty"`
 	StartDate *time.Time `json:"start_date,omitempty"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
 }
@@ -108,6 +196,36 @@ type GetAPIMetricsResponse struct {
 	Total   int64                `json:"total"`
 }
 
+// StatusBreakdown is an endpoint's request distribution by status-code class
+type StatusBreakdown struct {
+	Endpoint      string `json:"endpoint"`
+	Method        string `json:"method"`
+	TotalRequests int64  `json:"total_requests"`
+	Count2xx      int64  `json:"count_2xx"`
+	Count3xx      int64  `json:"count_3xx"`
+	Count4xx      int64  `json:"count_4xx"`
+	Count5xx      int64  `json:"count_5xx"`
+}
+
+// GetErrorBudgetRequest asks for error budget status of one endpoint
+type GetErrorBudgetRequest struct {
+	Endpoint  string        `json:"endpoint" binding:"required"`
+	Method    string        `json:"method" binding:"required"`
+	SLOTarget float64       `json:"slo_target" binding:"required"` // e.g. 0.999
+	Window    time.Duration `json:"window,omitempty"`              // defaults to 30 days
+}
+
+// GetErrorBudgetResponse reports error budget consumption for the window
+type GetErrorBudgetResponse struct {
+	Endpoint            string     `json:"endpoint"`
+	Method              string     `json:"method"`
+	SLOTarget           float64    `json:"slo_target"`
+	SuccessRate         float64    `json:"success_rate"`
+	BudgetConsumed      float64    `json:"budget_consumed"` // fraction of allowed errors used
+	BurnRate            float64    `json:"burn_rate"`       // error rate relative to SLO allowance
+	ProjectedExhaustion *time.Time `json:"projected_exhaustion,omitempty"`
+}
+
 // Error metrics requests and responses
 type GetErrorMetricsRequest struct {
 	ErrorType string     `json:"error_type,omitempty"`
@@ -123,6 +241,18 @@ type GetErrorMetricsResponse struct {
 	Total   int64                  `json:"total"`
 }
 
+// UserErrorStats summarizes the errors one user experienced in a range
+type UserErrorStats struct {
+	UserID       uuid.UUID `json:"user_id"`
+	ErrorCount   int64     `json:"error_count"`
+	TopErrorType string    `json:"top_error_type"`
+}
+
+type GetTopErrorUsersResponse struct {
+	Users []*UserErrorStats `json:"users"`
+	Total int64             `json:"total"`
+}
+
 type TrackErrorRequest struct {
 	ErrorType    string `json:"error_type" binding:"required"`
 	ErrorMessage string `json:"error_message" binding:"required"`
@@ -186,6 +316,36 @@ type EventService interface {
 
 	// GetEventStats retrieves event statistics
 	GetEventStats(ctx context.Context, startDate, endDate time.Time) (map[string]int64, error)
+
+	// GetRetentionCohorts buckets users by first-event week and computes the
+	// fraction active in each subsequent week
+	GetRetentionCohorts(ctx context.Context, cohortStart time.Time, periods int) (*RetentionCohortsResponse, error)
+
+	// GetFunnel computes how many distinct users completed each successive
+	// step of an ordered event-type funnel within the range
+	GetFunnel(ctx context.Context, req *GetFunnelRequest) (*GetFunnelResponse, error)
+
+	// GetCoOccurrence counts event-type pairs occurring in the same session
+	GetCoOccurrence(ctx context.Context, start, end time.Time, limit int) (*GetCoOccurrenceResponse, error)
+
+	// GetErrorRateByVersion segments error rates by app version to catch
+	// regressions introduced by a release
+	GetErrorRateByVersion(ctx context.Context, start, end time.Time) (map[string]*VersionErrorRate, error)
+
+	// HandleUserDeletion anonymizes or hard-deletes a deleted user's events
+	// and activity per the configured policy
+	HandleUserDeletion(ctx context.Context, userID uuid.UUID) (*UserDeletionResult, error)
+
+	// DetectSuspiciousDownloads flags users and IPs downloading an unusual
+	// volume of distinct files within the window
+	DetectSuspiciousDownloads(ctx context.Context, window time.Duration) ([]*SuspiciousDownloadReport, error)
+
+	// GetIngestionLag reports the delay between event creation and
+	// persistence, for monitoring analytics freshness
+	GetIngestionLag(ctx context.Context) (time.Duration, error)
+
+	// GetEventQuotaUsage reports a tenant's daily event quota consumption
+	GetEventQuotaUsage(ctx context.Context, orgID uuid.UUID) (*EventQuotaUsage, error)
 }
 
 // UserActivityService defines the interface for user activity operations
@@ -201,6 +361,17 @@ type UserActivityService interface {
 
 	// GetUserStats retrieves user statistics
 	GetUserStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (*domain.UserActivity, error)
+
+	// GetTopUsersByMetric retrieves a leaderboard ranked by a specific metric
+	GetTopUsersByMetric(ctx context.Context, metric string, startDate, endDate time.Time, limit int) (*GetTopUsersResponse, error)
+
+	// ReconcileUserActivity recomputes a day's counters from raw events and
+	// corrects the UserActivity row
+	ReconcileUserActivity(ctx context.Context, userID uuid.UUID, date time.Time) (*domain.UserActivity, error)
+
+	// ReconcileAllUserActivity reconciles every user active on the date and
+	// returns how many rows were corrected
+	ReconcileAllUserActivity(ctx context.Context, date time.Time) (int, error)
 }
 
 // SystemMetricsService defines the interface for system metrics operations
@@ -246,6 +417,16 @@ type APIMetricsService interface {
 
 	// GetSlowestEndpoints retrieves the slowest endpoints
 	GetSlowestEndpoints(ctx context.Context, startDate, endDate time.Time, limit int) (*GetAPIMetricsResponse, error)
+
+	// GetEndpointSummary returns an endpoint's all-time rollup
+	GetEndpointSummary(ctx context.Context, endpoint, method string) (*domain.APIMetricsSummary, error)
+
+	// GetErrorBudget computes SLO error budget consumption and burn rate
+	GetErrorBudget(ctx context.Context, req *GetErrorBudgetRequest) (*GetErrorBudgetResponse, error)
+
+	// GetStatusBreakdown returns an endpoint's request distribution by
+	// status-code class over a range
+	GetStatusBreakdown(ctx context.Context, endpoint, method string, start, end time.Time) (*StatusBreakdown, error)
 }
 
 // ErrorMetricsService defines the interface for error metrics operations
@@ -261,6 +442,9 @@ type ErrorMetricsService interface {
 
 	// GetErrorStats retrieves error statistics
 	GetErrorStats(ctx context.Context, startDate, endDate time.Time) (map[string]int64, error)
+
+	// GetTopErrorUsers retrieves the users hitting the most errors in a range
+	GetTopErrorUsers(ctx context.Context, startDate, endDate time.Time, limit int) (*GetTopErrorUsersResponse, error)
 }
 
 // ReportService defines the interface for report operations
@@ -279,6 +463,9 @@ type ReportService interface {
 
 	// ScheduleReport schedules a recurring report
 	ScheduleReport(ctx context.Context, req *GenerateReportRequest, userID uuid.UUID, schedule string) error
+
+	// GetReportGenerationStats returns the global generation limiter state
+	GetReportGenerationStats(ctx context.Context) (*ReportGenerationStats, error)
 }
 
 // DashboardService defines the interface for dashboard operations
@@ -0,0 +1,261 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RemoteWriteConfig holds the Prometheus remote-write exporter configuration
+type RemoteWriteConfig struct {
+	Endpoint   string        // remote-write receiver URL; empty disables the exporter
+	Interval   time.Duration // how often aggregates are collected and pushed
+	Timeout    time.Duration // per-request timeout
+	MaxRetries int           // send retries with exponential backoff
+}
+
+// LoadRemoteWriteConfig reads the exporter configuration from the environment
+func LoadRemoteWriteConfig() RemoteWriteConfig {
+	cfg := RemoteWriteConfig{
+		Endpoint:   os.Getenv("ANALYTICS_REMOTE_WRITE_ENDPOINT"),
+		Interval:   time.Minute,
+		Timeout:    10 * time.Second,
+		MaxRetries: 3,
+	}
+	if value := os.Getenv("ANALYTICS_REMOTE_WRITE_INTERVAL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			cfg.Interval = d
+		}
+	}
+	if value := os.Getenv("ANALYTICS_REMOTE_WRITE_MAX_RETRIES"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			cfg.MaxRetries = n
+		}
+	}
+	return cfg
+}
+
+// RemoteWriteExporter periodically pushes key analytics aggregates (active
+// users, event rates, error rates, top-endpoint latencies) to a Prometheus
+// remote-write endpoint so they show up in existing monitoring stacks.
+type RemoteWriteExporter struct {
+	service AnalyticsService
+	config  RemoteWriteConfig
+	client  *http.Client
+}
+
+// NewRemoteWriteExporter creates a new remote-write exporter
+func NewRemoteWriteExporter(service AnalyticsService, config RemoteWriteConfig) *RemoteWriteExporter {
+	return &RemoteWriteExporter{
+		service: service,
+		config:  config,
+		client:  &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Start runs the export loop until the context is cancelled
+func (e *RemoteWriteExporter) Start(ctx context.Context) {
+	if e.config.Endpoint == "" {
+		return
+	}
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.exportOnce(ctx); err != nil {
+				fmt.Printf("Remote-write export failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// timeSeries is one metric with labels and a single current sample
+type timeSeries struct {
+	name      string
+	labels    map[string]string
+	value     float64
+	timestamp time.Time
+}
+
+// exportOnce collects the current aggregates and pushes them as one batch
+func (e *RemoteWriteExporter) exportOnce(ctx context.Context) error {
+	series := e.collect(ctx)
+	if len(series) == 0 {
+		return nil
+	}
+
+	payload := snappyEncode(encodeWriteRequest(series))
+
+	var lastErr error
+	for attempt := 0; attempt <= e.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		if lastErr = e.send(ctx, payload); lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("remote write failed after %d retries: %w", e.config.MaxRetries, lastErr)
+}
+
+// collect gathers the aggregates to export
+func (e *RemoteWriteExporter) collect(ctx context.Context) []timeSeries {
+	now := time.Now()
+	var series []timeSeries
+
+	if metrics, err := e.service.GetLatestSystemMetrics(ctx); err == nil && metrics != nil {
+		series = append(series,
+			timeSeries{name: "analytics_active_users", value: float64(metrics.ActiveUsers), timestamp: now},
+			timeSeries{name: "analytics_total_events", value: float64(metrics.TotalEvents), timestamp: now},
+			timeSeries{name: "analytics_error_rate", value: metrics.ErrorRate, timestamp: now},
+		)
+	}
+
+	startDate := now.Add(-24 * time.Hour)
+	if stats, err := e.service.GetEventStats(ctx, startDate, now); err == nil {
+		for eventType, count := range stats {
+			series = append(series, timeSeries{
+				name:      "analytics_events_total",
+				labels:    map[string]string{"event_type": eventType},
+				value:     float64(count),
+				timestamp: now,
+			})
+		}
+	}
+
+	if endpoints, err := e.service.GetTopEndpoints(ctx, startDate, now, 10); err == nil && endpoints != nil {
+		for _, metric := range endpoints.Metrics {
+			series = append(series, timeSeries{
+				name:      "analytics_endpoint_avg_latency_ms",
+				labels:    map[string]string{"endpoint": metric.Endpoint, "method": metric.Method},
+				value:     metric.AverageLatency,
+				timestamp: now,
+			})
+		}
+	}
+
+	return series
+}
+
+// send performs one remote-write POST
+func (e *RemoteWriteExporter) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("remote write endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Minimal protobuf encoding of the remote-write WriteRequest message. Only
+// the fields the exporter produces are implemented, which avoids pulling in
+// the full protobuf toolchain for this one payload.
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1 }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2 }
+//	Label        { string name = 1; string value = 2 }
+//	Sample       { double value = 1; int64 timestamp = 2 }
+func encodeWriteRequest(series []timeSeries) []byte {
+	var out []byte
+	for _, ts := range series {
+		encoded := encodeTimeSeries(ts)
+		out = append(out, 0x0a) // field 1, wire type 2
+		out = appendUvarint(out, uint64(len(encoded)))
+		out = append(out, encoded...)
+	}
+	return out
+}
+
+func encodeTimeSeries(ts timeSeries) []byte {
+	var out []byte
+
+	// The metric name is the reserved __name__ label and must sort first
+	out = appendLabel(out, "__name__", ts.name)
+	for name, value := range ts.labels {
+		out = appendLabel(out, name, value)
+	}
+
+	// Sample: double value (field 1, fixed64) + ms timestamp (field 2, varint)
+	var sample []byte
+	sample = append(sample, 0x09)
+	sample = binary.LittleEndian.AppendUint64(sample, math.Float64bits(ts.value))
+	sample = append(sample, 0x10)
+	sample = appendUvarint(sample, uint64(ts.timestamp.UnixMilli()))
+
+	out = append(out, 0x12) // field 2, wire type 2
+	out = appendUvarint(out, uint64(len(sample)))
+	out = append(out, sample...)
+
+	return out
+}
+
+func appendLabel(out []byte, name, value string) []byte {
+	var label []byte
+	label = append(label, 0x0a)
+	label = appendUvarint(label, uint64(len(name)))
+	label = append(label, name...)
+	label = append(label, 0x12)
+	label = appendUvarint(label, uint64(len(value)))
+	label = append(label, value...)
+
+	out = append(out, 0x0a) // field 1, wire type 2
+	out = appendUvarint(out, uint64(len(label)))
+	return append(out, label...)
+}
+
+func appendUvarint(out []byte, v uint64) []byte {
+	for v >= 0x80 {
+		out = append(out, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(out, byte(v))
+}
+
+// snappyEncode produces a valid snappy block using only literal chunks. It
+// performs no compression, which the format permits, and keeps the exporter
+// free of external compression dependencies.
+func snappyEncode(src []byte) []byte {
+	out := appendUvarint(nil, uint64(len(src)))
+
+	for len(src) > 0 {
+		chunk := src
+		if len(chunk) > 60 {
+			chunk = chunk[:60]
+		}
+		out = append(out, byte(len(chunk)-1)<<2) // literal tag
+		out = append(out, chunk...)
+		src = src[len(chunk):]
+	}
+
+	return out
+}
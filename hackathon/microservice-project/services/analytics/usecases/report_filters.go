@@ -0,0 +1,321 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/elotusteam/microservice-project/services/analytics/domain"
+)
+
+// DefaultReportFilterMaxDepth bounds how deeply report filter expressions may
+// be nested. It can be overridden with the ANALYTICS_REPORT_FILTER_MAX_DEPTH
+// environment variable.
+const DefaultReportFilterMaxDepth = 3
+
+// reportFilterMaxDepth returns the configured maximum filter nesting depth
+func reportFilterMaxDepth() int {
+	if value := os.Getenv("ANALYTICS_REPORT_FILTER_MAX_DEPTH"); value != "" {
+		if depth, err := strconv.Atoi(value); err == nil && depth > 0 {
+			return depth
+		}
+	}
+	return DefaultReportFilterMaxDepth
+}
+
+// ReportFilter represents a single node of the report filter DSL. A node is
+// either a field comparison (Field/Operator/Value) or a boolean combination
+// of child filters (And/Or).
+type ReportFilter struct {
+	Field    string         `json:"field,omitempty"`
+	Operator string         `json:"op,omitempty"`
+	Value    interface{}    `json:"value,omitempty"`
+	And      []ReportFilter `json:"and,omitempty"`
+	Or       []ReportFilter `json:"or,omitempty"`
+}
+
+// Supported filter operators
+const (
+	FilterOpEquals      = "eq"
+	FilterOpNotEquals   = "neq"
+	FilterOpGreaterThan = "gt"
+	FilterOpGreaterEq   = "gte"
+	FilterOpLessThan    = "lt"
+	FilterOpLessEq      = "lte"
+	FilterOpContains    = "contains"
+)
+
+var supportedFilterOperators = map[string]bool{
+	FilterOpEquals:      true,
+	FilterOpNotEquals:   true,
+	FilterOpGreaterThan: true,
+	FilterOpGreaterEq:   true,
+	FilterOpLessThan:    true,
+	FilterOpLessEq:      true,
+	FilterOpContains:    true,
+}
+
+// reportFilterFields lists the fields each report type may be filtered on.
+// Field names match the JSON tags of the report row types.
+var reportFilterFields = map[domain.ReportType]map[string]bool{
+	domain.ReportTypeUserActivity: {
+		"user_id":      true,
+		"total_events": true,
+	},
+	domain.ReportTypeSystemMetrics: {
+		"total_users":  true,
+		"active_users": true,
+		"error_rate":   true,
+	},
+	domain.ReportTypeFileMetrics: {
+		"file_type":      true,
+		"file_name":      true,
+		"owner_id":       true,
+		"view_count":     true,
+		"download_count": true,
+		"share_count":    true,
+	},
+	domain.ReportTypeAPIMetrics: {
+		"endpoint":      true,
+		"method":        true,
+		"request_count": true,
+		"error_count":   true,
+	},
+	domain.ReportTypeErrorMetrics: {
+		"error_type": true,
+		"service":    true,
+		"endpoint":   true,
+		"count":      true,
+	},
+}
+
+// parseReportFilters converts the request's filters map into a validated
+// filter tree. A nil result means no filtering was requested.
+func parseReportFilters(reportType domain.ReportType, filters map[string]interface{}) (*ReportFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	root, err := parseFilterNode(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDepth := reportFilterMaxDepth()
+	if depth := filterDepth(root); depth > maxDepth {
+		return nil, fmt.Errorf("filter expression exceeds maximum depth of %d", maxDepth)
+	}
+
+	if err := validateFilter(reportType, root); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// parseFilterNode parses one level of the filters map. Plain entries become
+// field comparisons (scalar values imply "eq"); "and"/"or" entries recurse.
+func parseFilterNode(node map[string]interface{}) (*ReportFilter, error) {
+	filter := &ReportFilter{}
+
+	for key, value := range node {
+		switch key {
+		case "and", "or":
+			children, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%q must be a list of filter objects", key)
+			}
+			for _, child := range children {
+				childMap, ok := child.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%q entries must be filter objects", key)
+				}
+				parsed, err := parseFilterNode(childMap)
+				if err != nil {
+					return nil, err
+				}
+				if key == "and" {
+					filter.And = append(filter.And, *parsed)
+				} else {
+					filter.Or = append(filter.Or, *parsed)
+				}
+			}
+		default:
+			comparison := ReportFilter{Field: key, Operator: FilterOpEquals, Value: value}
+			if valueMap, ok := value.(map[string]interface{}); ok {
+				op, _ := valueMap["op"].(string)
+				if op == "" {
+					return nil, fmt.Errorf("filter on field %q is missing an operator", key)
+				}
+				comparison.Operator = op
+				comparison.Value = valueMap["value"]
+			}
+			filter.And = append(filter.And, comparison)
+		}
+	}
+
+	// Collapse a single comparison so depth reflects actual nesting
+	if len(filter.And) == 1 && len(filter.Or) == 0 {
+		return &filter.And[0], nil
+	}
+
+	return filter, nil
+}
+
+// filterDepth returns the nesting depth of a filter tree
+func filterDepth(filter *ReportFilter) int {
+	depth := 0
+	for i := range filter.And {
+		if d := filterDepth(&filter.And[i]); d > depth {
+			depth = d
+		}
+	}
+	for i := range filter.Or {
+		if d := filterDepth(&filter.Or[i]); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
+
+// validateFilter rejects unsupported fields and operators for the report type
+func validateFilter(reportType domain.ReportType, filter *ReportFilter) error {
+	if filter.Field != "" {
+		allowed := reportFilterFields[reportType]
+		if !allowed[filter.Field] {
+			return fmt.Errorf("field %q is not filterable for %s reports", filter.Field, reportType)
+		}
+		if !supportedFilterOperators[filter.Operator] {
+			return fmt.Errorf("unsupported filter operator %q", filter.Operator)
+		}
+	}
+	for i := range filter.And {
+		if err := validateFilter(reportType, &filter.And[i]); err != nil {
+			return err
+		}
+	}
+	for i := range filter.Or {
+		if err := validateFilter(reportType, &filter.Or[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyReportFilters filters the generated report rows. Data that is not a
+// JSON array (e.g. aggregated single-row reports) is returned unchanged.
+func applyReportFilters(data interface{}, filter *ReportFilter) (interface{}, error) {
+	if filter == nil {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report data: %w", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(encoded, &rows); err != nil {
+		// Not a list of rows; nothing to filter
+		return data, nil
+	}
+
+	filtered := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if matchesFilter(row, filter) {
+			filtered = append(filtered, row)
+		}
+	}
+
+	return filtered, nil
+}
+
+// matchesFilter evaluates a filter tree against a report row
+func matchesFilter(row map[string]interface{}, filter *ReportFilter) bool {
+	if filter.Field != "" {
+		if !matchesComparison(row[filter.Field], filter.Operator, filter.Value) {
+			return false
+		}
+	}
+	for i := range filter.And {
+		if !matchesFilter(row, &filter.And[i]) {
+			return false
+		}
+	}
+	if len(filter.Or) > 0 {
+		anyMatch := false
+		for i := range filter.Or {
+			if matchesFilter(row, &filter.Or[i]) {
+				anyMatch = true
+				break
+			}
+		}
+		if !anyMatch {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesComparison evaluates a single field comparison
+func matchesComparison(fieldValue interface{}, operator string, filterValue interface{}) bool {
+	switch operator {
+	case FilterOpEquals:
+		return compareValues(fieldValue, filterValue) == 0
+	case FilterOpNotEquals:
+		return compareValues(fieldValue, filterValue) != 0
+	case FilterOpGreaterThan:
+		return compareValues(fieldValue, filterValue) > 0
+	case FilterOpGreaterEq:
+		return compareValues(fieldValue, filterValue) >= 0
+	case FilterOpLessThan:
+		return compareValues(fieldValue, filterValue) < 0
+	case FilterOpLessEq:
+		return compareValues(fieldValue, filterValue) <= 0
+	case FilterOpContains:
+		return strings.Contains(
+			strings.ToLower(fmt.Sprintf("%v", fieldValue)),
+			strings.ToLower(fmt.Sprintf("%v", filterValue)),
+		)
+	default:
+		return false
+	}
+}
+
+// compareValues compares two values numerically when possible, otherwise as
+// strings. Returns -1, 0 or 1.
+func compareValues(a, b interface{}) int {
+	aNum, aOK := toFloat(a)
+	bNum, bOK := toFloat(b)
+	if aOK && bOK {
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
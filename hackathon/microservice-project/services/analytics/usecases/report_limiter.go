@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultMaxConcurrentReports bounds how many report generations may run at
+// once across all users. It can be overridden with the
+// ANALYTICS_MAX_CONCURRENT_REPORTS environment variable.
+const DefaultMaxConcurrentReports = 4
+
+// maxConcurrentReports returns the configured global report generation limit
+func maxConcurrentReports() int {
+	if value := os.Getenv("ANALYTICS_MAX_CONCURRENT_REPORTS"); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxConcurrentReports
+}
+
+// reportLimiter is a global semaphore for report generation. Requests over
+// the limit queue (block) until a slot frees up or their context is
+// cancelled. Queue depth and active count are tracked for metrics.
+type reportLimiter struct {
+	slots  chan struct{}
+	queued int64
+	active int64
+}
+
+// newReportLimiter creates a limiter with the given number of slots
+func newReportLimiter(limit int) *reportLimiter {
+	return &reportLimiter{
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// acquire blocks until a generation slot is available or the context is done
+func (l *reportLimiter) acquire(ctx context.Context) error {
+	atomic.AddInt64(&l.queued, 1)
+	defer atomic.AddInt64(&l.queued, -1)
+
+	select {
+	case l.slots <- struct{}{}:
+		atomic.AddInt64(&l.active, 1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a generation slot
+func (l *reportLimiter) release() {
+	atomic.AddInt64(&l.active, -1)
+	<-l.slots
+}
+
+// stats returns the current queue depth, active count and limit
+func (l *reportLimiter) stats() (queued, active, limit int64) {
+	return atomic.LoadInt64(&l.queued), atomic.LoadInt64(&l.active), int64(cap(l.slots))
+}
+
+// ReportGenerationStats reports the state of the global generation limiter
+type ReportGenerationStats struct {
+	ActiveCount int64 `json:"active_count"`
+	QueueDepth  int64 `json:"queue_depth"`
+	Limit       int64 `json:"limit"`
+}
@@ -11,6 +11,7 @@ type AuthUser struct {
 	Username string            `json:"username"`
 	Email    string            `json:"email"`
 	Role     domain.UserRole   `json:"role"`
+	OrgID    string            `json:"org_id,omitempty"`
 	Status   domain.UserStatus `json:"status"`
 }
 
@@ -18,6 +19,13 @@ type AuthUser struct {
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+
+	// ClientSecret optionally binds the issued refresh token to this client
+	ClientSecret string `json:"client_secret,omitempty"`
+
+	// CaptchaToken is required after repeated failed attempts when CAPTCHA
+	// is enabled
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 // RegisterRequest represents a registration request
@@ -27,6 +35,9 @@ type RegisterRequest struct {
 	Password  string `json:"password" validate:"required,min=8"`
 	FirstName string `json:"first_name" validate:"required,min=1,max=100"`
 	LastName  string `json:"last_name" validate:"required,min=1,max=100"`
+
+	// CaptchaToken is required when CAPTCHA is enabled
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 // ChangePasswordRequest represents a password change request
@@ -49,6 +60,10 @@ type ConfirmResetPasswordRequest struct {
 // RefreshTokenRequest represents a token refresh request
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+
+	// ClientSecret must match the secret supplied at login when
+	// refresh-token binding is enabled
+	ClientSecret string `json:"client_secret,omitempty"`
 }
 
 // TokenPair represents access and refresh tokens
@@ -60,10 +75,14 @@ type TokenPair struct {
 	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
 
-// AuthResponse represents authentication response
+// AuthResponse represents authentication response. When MFA is required the
+// tokens are withheld and the client must exchange the challenge token plus
+// a valid OTP at the MFA verification endpoint.
 type AuthResponse struct {
-	User   *AuthUser  `json:"user"`
-	Tokens *TokenPair `json:"tokens"`
+	User           *AuthUser  `json:"user"`
+	Tokens         *TokenPair `json:"tokens,omitempty"`
+	MFARequired    bool       `json:"mfa_required,omitempty"`
+	ChallengeToken string     `json:"challenge_token,omitempty"`
 }
 
 // JWTClaims represents JWT token claims
@@ -74,6 +93,7 @@ type JWTClaims struct {
 	Role      string `json:"role"`
 	TokenID   string `json:"token_id"`
 	TokenType string `json:"token_type"`
+	Scope     string `json:"scope,omitempty"` // space-separated scopes on service tokens
 
 	// Standard JWT claims
 	Issuer    string       `json:"iss,omitempty"`
@@ -154,6 +174,14 @@ const (
 	AuthErrTooManyAttempts       = "TOO_MANY_ATTEMPTS"
 	AuthErrPasswordResetRequired = "PASSWORD_RESET_REQUIRED"
 	AuthErrEmailNotVerified      = "EMAIL_NOT_VERIFIED"
+	// AuthErrTokenBindingMismatch indicates a refresh token presented from a
+	// client whose fingerprint doesn't match the one the token was bound to
+	AuthErrTokenBindingMismatch = "TOKEN_BINDING_MISMATCH"
+	AuthErrMFARequired          = "MFA_REQUIRED"
+	AuthErrInsufficientPerms    = "INSUFFICIENT_PERMISSIONS"
+	AuthErrMFANotEnrolled       = "MFA_NOT_ENROLLED"
+	AuthErrInvalidMFACode       = "INVALID_MFA_CODE"
+	AuthErrCaptchaRequired      = "CAPTCHA_REQUIRED"
 )
 
 // NewAuthError creates a new authentication error
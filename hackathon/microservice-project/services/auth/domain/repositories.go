@@ -94,6 +94,10 @@ type SessionRepository interface {
 	// GetActiveSessions gets active sessions for a user
 	GetActiveSessions(ctx context.Context, userID string) ([]*domain.Session, error)
 
+	// GetAllActiveSessions gets active sessions across all users created
+	// since the given time
+	GetAllActiveSessions(ctx context.Context, since time.Time) ([]*domain.Session, error)
+
 	// GetActiveSessionCount gets count of active sessions
 	GetActiveSessionCount(ctx context.Context) (int64, error)
 
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/elotusteam/microservice-project/services/auth/usecases"
+	"github.com/elotusteam/microservice-project/shared/authrpc"
+	"google.golang.org/grpc"
+)
+
+// authRPCServer adapts the AuthService use cases to the shared gRPC contract
+type authRPCServer struct {
+	service usecases.AuthService
+}
+
+// ValidateToken validates an access token for another service
+func (s *authRPCServer) ValidateToken(ctx context.Context, req *authrpc.ValidateTokenRequest) (*authrpc.ValidateTokenResponse, error) {
+	user, err := s.service.ValidateToken(ctx, req.Token)
+	if err != nil {
+		// Invalid tokens are a normal outcome, not an RPC failure
+		return &authrpc.ValidateTokenResponse{Valid: false, Error: err.Error()}, nil
+	}
+
+	return &authrpc.ValidateTokenResponse{
+		Valid:    true,
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     string(user.Role),
+		OrgID:    user.OrgID,
+	}, nil
+}
+
+// GetUserProfile returns a user's profile for another service
+func (s *authRPCServer) GetUserProfile(ctx context.Context, req *authrpc.GetUserProfileRequest) (*authrpc.GetUserProfileResponse, error) {
+	user, err := s.service.GetUserProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authrpc.GetUserProfileResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     string(user.Role),
+		Status:   string(user.Status),
+	}, nil
+}
+
+// startGRPCServer serves the auth gRPC interface on the given port
+func startGRPCServer(service usecases.AuthService, port int) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		log.Printf("Failed to listen for gRPC on port %d: %v", port, err)
+		return
+	}
+
+	server := grpc.NewServer()
+	authrpc.RegisterAuthServer(server, &authRPCServer{service: service})
+
+	log.Printf("Auth gRPC server starting on port %d", port)
+	if err := server.Serve(listener); err != nil {
+		log.Printf("Auth gRPC server stopped: %v", err)
+	}
+}
@@ -0,0 +1,906 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/services/auth/usecases"
+	"github.com/elotusteam/microservice-project/shared/data"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// authHandlers binds the HTTP layer to the AuthService use cases
+type authHandlers struct {
+	service      usecases.AuthService
+	tokenService usecases.TokenService
+}
+
+// newAuthHandlers creates the auth HTTP handler set
+func newAuthHandlers(service usecases.AuthService, tokenService usecases.TokenService) *authHandlers {
+	return &authHandlers{service: service, tokenService: tokenService}
+}
+
+// statusForAuthError maps domain auth error codes to HTTP statuses
+func statusForAuthError(err error) int {
+	var authErr *domain.AuthError
+	if !errors.As(err, &authErr) {
+		return http.StatusInternalServerError
+	}
+
+	switch authErr.Code {
+	case sharedDomain.ErrorCodeValidation:
+		return http.StatusBadRequest
+	case domain.AuthErrInvalidCredentials, domain.AuthErrInvalidToken,
+		domain.AuthErrExpiredToken, domain.AuthErrRevokedToken,
+		domain.AuthErrTokenBindingMismatch:
+		return http.StatusUnauthorized
+	case domain.AuthErrAccountLocked, domain.AuthErrAccountInactive:
+		return http.StatusForbidden
+	case domain.AuthErrUserNotFound:
+		return http.StatusNotFound
+	case domain.AuthErrUserAlreadyExists, domain.AuthErrEmailAlreadyExists,
+		domain.AuthErrUsernameAlreadyExists:
+		return http.StatusConflict
+	case domain.AuthErrTooManyAttempts:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// respondAuthError writes a structured auth error response
+func respondAuthError(c *gin.Context, err error) {
+	var authErr *domain.AuthError
+	if errors.As(err, &authErr) {
+		c.JSON(statusForAuthError(err), gin.H{"error": authErr.Message, "code": authErr.Code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
+
+// login authenticates a user and returns the token pair
+func (h *authHandlers) login(c *gin.Context) {
+	var req domain.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.Login(c.Request.Context(), &req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		authAttemptsTotal.WithLabelValues("failure").Inc()
+		respondAuthError(c, err)
+		return
+	}
+
+	authAttemptsTotal.WithLabelValues("success").Inc()
+
+	// In cookie mode the refresh token travels in an HTTP-only cookie
+	// instead of the response body
+	if refreshCookieEnabled() && response.Tokens != nil {
+		setRefreshTokenCookie(c, response.Tokens.RefreshToken, time.Until(response.Tokens.RefreshExpiresAt))
+		response.Tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// register creates a new account
+func (h *authHandlers) register(c *gin.Context) {
+	var req domain.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.Register(c.Request.Context(), &req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// logout revokes the presented access token (and the refresh token when the
+// client supplies it). Revocation is keyed by the tokens' JTIs, which is
+// what validation checks against.
+func (h *authHandlers) logout(c *gin.Context) {
+	token := extractBearerToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+		return
+	}
+
+	tokenID, err := h.tokenService.ExtractTokenID(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), tokenID); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	// Revoke the refresh token too so the pair dies together
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
+		if claims, err := h.tokenService.ParseToken(c.Request.Context(), req.RefreshToken); err == nil {
+			if err := h.service.RevokeToken(c.Request.Context(), claims.TokenID, claims.UserID, "user_logout"); err != nil {
+				respondAuthError(c, err)
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// refresh rotates the token pair using the refresh token from the cookie
+// (cookie mode) or the JSON body
+func (h *authHandlers) refresh(c *gin.Context) {
+	refreshToken, clientSecret, _, err := refreshTokenFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.RefreshToken(c.Request.Context(), &domain.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		ClientSecret: clientSecret,
+	}, c.Request.UserAgent())
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if refreshCookieEnabled() {
+		setRefreshTokenCookie(c, tokens.RefreshToken, time.Until(tokens.RefreshExpiresAt))
+		tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// validate checks an access token and returns the authenticated user
+func (h *authHandlers) validate(c *gin.Context) {
+	token := extractBearerToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+		return
+	}
+
+	user, err := h.service.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// sessionGeoDistribution is the admin view of where sessions originate
+func (h *authHandlers) sessionGeoDistribution(c *gin.Context) {
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since date"})
+			return
+		}
+		since = parsed
+	}
+
+	distribution, err := h.service.GetSessionGeoDistribution(c.Request.Context(), since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"countries": distribution})
+}
+
+// extractBearerToken pulls the token out of the Authorization header
+func extractBearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && header[:len(prefix)] == prefix {
+		return header[len(prefix):]
+	}
+	return ""
+}
+
+// mfaEnroll starts TOTP enrollment for the authenticated user
+func (h *authHandlers) mfaEnroll(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	response, err := h.service.MFA().Enroll(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// mfaVerifyEnrollment activates MFA after the authenticator is confirmed
+func (h *authHandlers) mfaVerifyEnrollment(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.MFA().VerifyEnrollment(c.Request.Context(), user.ID, req.Code); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "MFA enabled"})
+}
+
+// mfaVerify exchanges a login challenge token plus OTP for the token pair
+func (h *authHandlers) mfaVerify(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.CompleteMFALogin(c.Request.Context(), req.ChallengeToken, req.Code, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if refreshCookieEnabled() && response.Tokens != nil {
+		setRefreshTokenCookie(c, response.Tokens.RefreshToken, time.Until(response.Tokens.RefreshExpiresAt))
+		response.Tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// mfaDisable removes the authenticated user's MFA enrollment
+func (h *authHandlers) mfaDisable(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := h.service.MFA().Disable(c.Request.Context(), user.ID); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "MFA disabled"})
+}
+
+// requireAdmin is route middleware that authenticates the bearer token and
+// requires the validated claims to carry the admin role. Unlike headers,
+// the role comes from the signed token, so it cannot be spoofed.
+func (h *authHandlers) requireAdmin(c *gin.Context) {
+	token := extractBearerToken(c)
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+		return
+	}
+
+	user, err := h.service.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		c.AbortWithStatusJSON(statusForAuthError(err), gin.H{"error": "Invalid token"})
+		return
+	}
+	if user.Role != sharedDomain.UserRoleAdmin {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	c.Set(middleware.AuthUserIDKey, user.ID)
+	c.Next()
+}
+
+// authenticatedUser resolves the caller from the bearer token, writing the
+// error response itself when authentication fails
+func (h *authHandlers) authenticatedUser(c *gin.Context) *domain.AuthUser {
+	token := extractBearerToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+		return nil
+	}
+
+	user, err := h.service.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		respondAuthError(c, err)
+		return nil
+	}
+	return user
+}
+
+// webauthnBeginRegistration starts a passkey registration ceremony
+func (h *authHandlers) webauthnBeginRegistration(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	challenge, err := h.service.WebAuthn().BeginRegistration(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, challenge)
+}
+
+// webauthnFinishRegistration stores the new passkey credential
+func (h *authHandlers) webauthnFinishRegistration(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	var req usecases.WebAuthnRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	credential, err := h.service.WebAuthn().FinishRegistration(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, credential)
+}
+
+// webauthnBeginLogin starts a passkey assertion ceremony
+func (h *authHandlers) webauthnBeginLogin(c *gin.Context) {
+	challenge, err := h.service.WebAuthn().BeginLogin(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, challenge)
+}
+
+// webauthnFinishLogin verifies the assertion and issues tokens
+func (h *authHandlers) webauthnFinishLogin(c *gin.Context) {
+	var req usecases.WebAuthnAssertionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.CompletePasskeyLogin(c.Request.Context(), &req, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if refreshCookieEnabled() && response.Tokens != nil {
+		setRefreshTokenCookie(c, response.Tokens.RefreshToken, time.Until(response.Tokens.RefreshExpiresAt))
+		response.Tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// webauthnListCredentials lists the user's passkeys
+func (h *authHandlers) webauthnListCredentials(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	credentials, err := h.service.WebAuthn().ListCredentials(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"credentials": credentials})
+}
+
+// webauthnDeleteCredential removes one of the user's passkeys
+func (h *authHandlers) webauthnDeleteCredential(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := h.service.WebAuthn().DeleteCredential(c.Request.Context(), user.ID, c.Param("id")); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Credential removed"})
+}
+
+// oauthBegin redirects the client into the provider's authorization flow
+func (h *authHandlers) oauthBegin(c *gin.Context) {
+	authURL, err := h.service.OAuth().BeginFlow(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// oauthCallback finishes the flow and issues the standard token pair
+func (h *authHandlers) oauthCallback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	response, err := h.service.CompleteOAuthLogin(c.Request.Context(), c.Param("provider"), code, state, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if refreshCookieEnabled() && response.Tokens != nil {
+		setRefreshTokenCookie(c, response.Tokens.RefreshToken, time.Until(response.Tokens.RefreshExpiresAt))
+		response.Tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lockoutInfo is the admin view of a user's lockout state
+func (h *authHandlers) lockoutInfo(c *gin.Context) {
+	locked, err := h.service.IsUserLockedOut(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user_id": c.Param("user_id"), "locked": locked})
+}
+
+// clearLockout is the admin action to unlock a user
+func (h *authHandlers) clearLockout(c *gin.Context) {
+	if err := h.service.UnlockUser(c.Request.Context(), c.Param("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Lockout cleared"})
+}
+
+// adminListUsers lists or searches users, filtered by role/status/query
+func (h *authHandlers) adminListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	pagination := &data.Pagination{Page: page, PageSize: pageSize}
+
+	result, err := h.service.ListUsers(c.Request.Context(), c.Query("role"), c.Query("status"), c.Query("q"), pagination)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// adminUpdateUserRole changes a user's role
+func (h *authHandlers) adminUpdateUserRole(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateUserRole(c.Request.Context(), c.Param("user_id"), sharedDomain.UserRole(req.Role)); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated"})
+}
+
+// adminSuspendUser suspends an account
+func (h *authHandlers) adminSuspendUser(c *gin.Context) {
+	if err := h.service.DeactivateUser(c.Request.Context(), c.Param("user_id")); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended"})
+}
+
+// adminActivateUser reactivates an account
+func (h *authHandlers) adminActivateUser(c *gin.Context) {
+	if err := h.service.ActivateUser(c.Request.Context(), c.Param("user_id")); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User activated"})
+}
+
+// adminForceLogout revokes all of a user's sessions and tokens
+func (h *authHandlers) adminForceLogout(c *gin.Context) {
+	if err := h.service.LogoutAll(c.Request.Context(), c.Param("user_id")); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked"})
+}
+
+// listSessions lists the caller's active sessions (device, IP, last used)
+func (h *authHandlers) listSessions(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	sessions, err := h.service.GetUserSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions, "total": len(sessions)})
+}
+
+// revokeSession revokes one of the caller's own sessions
+func (h *authHandlers) revokeSession(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	sessionID := c.Param("id")
+
+	// Only the session's owner may revoke it
+	sessions, err := h.service.GetUserSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	owned := false
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), sessionID); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// revokeOtherSessions revokes every session except the one making the call
+func (h *authHandlers) revokeOtherSessions(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	// The current session is identified by the presented token's JTI,
+	// which is what sessions are keyed by
+	currentTokenID, err := h.tokenService.ExtractTokenID(c.Request.Context(), extractBearerToken(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	sessions, err := h.service.GetUserSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.TokenID == currentTokenID {
+			continue
+		}
+		if err := h.service.RevokeSession(c.Request.Context(), session.ID); err != nil {
+			continue
+		}
+		revoked++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// introspect implements RFC 7662 token introspection: external gateways can
+// check token state. Inactive tokens return {"active": false} rather than
+// an error, per the RFC.
+func (h *authHandlers) introspect(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token parameter is required"})
+		return
+	}
+
+	user, err := h.service.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"active": false})
+		return
+	}
+
+	response := gin.H{
+		"active":   true,
+		"sub":      user.ID,
+		"username": user.Username,
+	}
+	if claims, err := h.tokenService.ParseToken(c.Request.Context(), token); err == nil {
+		response["token_type"] = claims.TokenType
+		response["jti"] = claims.TokenID
+		response["iss"] = claims.Issuer
+		if claims.ExpiresAt != nil {
+			response["exp"] = claims.ExpiresAt.Unix()
+		}
+		if claims.IssuedAt != nil {
+			response["iat"] = claims.IssuedAt.Unix()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// revoke implements RFC 7009 token revocation. Per the RFC the endpoint
+// responds 200 even for tokens that are already invalid.
+func (h *authHandlers) revoke(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token parameter is required"})
+		return
+	}
+
+	claims, err := h.tokenService.ParseToken(c.Request.Context(), token)
+	if err != nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if err := h.service.RevokeToken(c.Request.Context(), claims.TokenID, claims.UserID, "revocation_endpoint"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Revocation failed"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// deleteAccount lets the authenticated user delete their own account,
+// starting the GDPR erasure workflow
+func (h *authHandlers) deleteAccount(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := h.service.DeleteAccount(c.Request.Context(), user.ID); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted; data will be erased after the retention window"})
+}
+
+// adminPurgeErasures runs the retention-window purge
+func (h *authHandlers) adminPurgeErasures(c *gin.Context) {
+	purged, err := h.service.PurgeDueErasures(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"purged": purged})
+}
+
+// parseAuditFilters reads the shared audit query params
+func parseAuditFilters(c *gin.Context) (userID, action string, start, end time.Time) {
+	userID = c.Query("user_id")
+	action = c.Query("action")
+	if value := c.Query("start"); value != "" {
+		start, _ = time.Parse("2006-01-02", value)
+	}
+	if value := c.Query("end"); value != "" {
+		end, _ = time.Parse("2006-01-02", value)
+	}
+	return userID, action, start, end
+}
+
+// adminSecurityEvents is the paginated audit query endpoint
+func (h *authHandlers) adminSecurityEvents(c *gin.Context) {
+	userID, action, start, end := parseAuditFilters(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	pagination := &data.Pagination{Page: page, PageSize: pageSize}
+
+	result, err := h.service.QuerySecurityEvents(c.Request.Context(), userID, action, start, end, pagination)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// adminExportSecurityEvents streams the matching audit entries as NDJSON or
+// CSV (?format=csv), paging through the repository so nothing is buffered
+func (h *authHandlers) adminExportSecurityEvents(c *gin.Context) {
+	userID, action, start, end := parseAuditFilters(c)
+	format := c.DefaultQuery("format", "ndjson")
+
+	var csvWriter *csv.Writer
+	switch format {
+	case "ndjson":
+		c.Header("Content-Type", "application/x-ndjson")
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		csvWriter = csv.NewWriter(c.Writer)
+		_ = csvWriter.Write([]string{"id", "user_id", "action", "resource_type", "ip_address", "timestamp", "status"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson or csv"})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=security-events."+format)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	const pageSize = 500
+	for page := 1; ; page++ {
+		result, err := h.service.QuerySecurityEvents(c.Request.Context(), userID, action, start, end,
+			&data.Pagination{Page: page, PageSize: pageSize})
+		if err != nil {
+			return
+		}
+
+		entries, ok := result.Data.([]*sharedDomain.ActivityLog)
+		if !ok || len(entries) == 0 {
+			break
+		}
+
+		for _, entry := range entries {
+			if csvWriter != nil {
+				logUserID := ""
+				if entry.UserID != nil {
+					logUserID = *entry.UserID
+				}
+				_ = csvWriter.Write([]string{
+					entry.ID, logUserID, entry.Action, entry.ResourceType,
+					entry.IPAddress, entry.Timestamp.Format(time.RFC3339), string(entry.Status),
+				})
+			} else if err := encoder.Encode(entry); err != nil {
+				return
+			}
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		c.Writer.Flush()
+
+		if len(entries) < pageSize {
+			break
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+	}
+}
+
+// magicLinkRequest starts a passwordless login by emailing a one-time link
+func (h *authHandlers) magicLinkRequest(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.RequestMagicLink(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send magic link"})
+		return
+	}
+
+	// Identical response whether or not the address exists
+	c.JSON(http.StatusOK, gin.H{"message": "If the address is registered, a login link is on its way"})
+}
+
+// magicLinkVerify exchanges the one-time link for the normal token pair
+func (h *authHandlers) magicLinkVerify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	response, err := h.service.VerifyMagicLink(c.Request.Context(), token, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if refreshCookieEnabled() && response.Tokens != nil {
+		setRefreshTokenCookie(c, response.Tokens.RefreshToken, time.Until(response.Tokens.RefreshExpiresAt))
+		response.Tokens.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// listDevices lists the caller's observed devices
+func (h *authHandlers) listDevices(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	devices, err := h.service.Devices().ListDevices(c.Request.Context(), user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"devices": devices})
+}
+
+// trustDevice names and trusts one of the caller's devices, letting it skip
+// MFA for the configured window
+func (h *authHandlers) trustDevice(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.service.Devices().TrustDevice(c.Request.Context(), user.ID, c.Param("id"), req.Name); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device trusted"})
+}
+
+// revokeDeviceTrust removes trust from one of the caller's devices
+func (h *authHandlers) revokeDeviceTrust(c *gin.Context) {
+	user := h.authenticatedUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := h.service.Devices().RevokeTrust(c.Request.Context(), user.ID, c.Param("id")); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device trust revoked"})
+}
@@ -0,0 +1,959 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	authDomain "github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/elotusteam/microservice-project/shared/data"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+// PostgreSQLRepositoryManager implements authDomain.RepositoryManager with
+// real SQL, mirroring the file service's PostgreSQL repositories. Cache
+// operations stay in-process (the mock cache repository) until a Redis
+// backend is wired.
+type PostgreSQLRepositoryManager struct {
+	db    *sql.DB
+	cache authDomain.AuthCacheRepository
+}
+
+// NewPostgreSQLRepositoryManager opens the auth database and returns the
+// repository manager. Schema migrations live under migrations/sql.
+func NewPostgreSQLRepositoryManager(cfg *config.DatabaseConfig) (authDomain.RepositoryManager, error) {
+	db, err := sql.Open("postgres", cfg.GetConnectionString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open auth database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConnections)
+	db.SetMaxIdleConns(cfg.MaxIdleConnections)
+	db.SetConnMaxLifetime(cfg.ConnectionLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectionTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping auth database: %w", err)
+	}
+
+	// Cache entries are process-local; a shared cache backend can replace
+	// this without touching callers
+	mock := NewMockRepositoryManager()
+
+	return &PostgreSQLRepositoryManager{
+		db:    db,
+		cache: mock.GetCacheRepository(),
+	}, nil
+}
+
+// GetUserRepository returns the user repository
+func (m *PostgreSQLRepositoryManager) GetUserRepository() authDomain.UserRepository {
+	return &PostgreSQLUserRepository{db: m.db}
+}
+
+// GetSessionRepository returns the session repository
+func (m *PostgreSQLRepositoryManager) GetSessionRepository() authDomain.SessionRepository {
+	return &PostgreSQLSessionRepository{db: m.db}
+}
+
+// GetRevokedTokenRepository returns the revoked token repository
+func (m *PostgreSQLRepositoryManager) GetRevokedTokenRepository() authDomain.RevokedTokenRepository {
+	return &PostgreSQLRevokedTokenRepository{db: m.db}
+}
+
+// GetPasswordResetTokenRepository returns the password reset token repository
+func (m *PostgreSQLRepositoryManager) GetPasswordResetTokenRepository() authDomain.PasswordResetTokenRepository {
+	return &PostgreSQLPasswordResetTokenRepository{db: m.db}
+}
+
+// GetLoginAttemptRepository returns the login attempt repository
+func (m *PostgreSQLRepositoryManager) GetLoginAttemptRepository() authDomain.LoginAttemptRepository {
+	return &PostgreSQLLoginAttemptRepository{db: m.db}
+}
+
+// GetActivityLogRepository returns the activity log repository
+func (m *PostgreSQLRepositoryManager) GetActivityLogRepository() authDomain.ActivityLogRepository {
+	return &PostgreSQLActivityLogRepository{db: m.db}
+}
+
+// GetCacheRepository returns the cache repository
+func (m *PostgreSQLRepositoryManager) GetCacheRepository() authDomain.AuthCacheRepository {
+	return m.cache
+}
+
+// BeginTransaction starts a new transaction
+func (m *PostgreSQLRepositoryManager) BeginTransaction(ctx context.Context) (data.Transaction, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &pgTransaction{tx: tx, ctx: ctx}, nil
+}
+
+// WithTransaction executes fn within a transaction, rolling back on error
+func (m *PostgreSQLRepositoryManager) WithTransaction(ctx context.Context, fn func(tx data.Transaction) error) error {
+	tx, err := m.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Close closes all repository connections
+func (m *PostgreSQLRepositoryManager) Close() error {
+	return m.db.Close()
+}
+
+// Health checks the health of all repositories
+func (m *PostgreSQLRepositoryManager) Health(ctx context.Context) error {
+	return m.db.PingContext(ctx)
+}
+
+// pgTransaction adapts sql.Tx to data.Transaction
+type pgTransaction struct {
+	tx  *sql.Tx
+	ctx context.Context
+}
+
+func (t *pgTransaction) Commit() error   { return t.tx.Commit() }
+func (t *pgTransaction) Rollback() error { return t.tx.Rollback() }
+
+// Context returns the context the transaction was started with so request
+// cancellation propagates to queries issued inside it
+func (t *pgTransaction) Context() context.Context {
+	if t.ctx != nil {
+		return t.ctx
+	}
+	return context.Background()
+}
+
+// paginate wraps rows into the shared paginated result shape
+func paginate(items interface{}, total int64, pagination *data.Pagination) *data.PaginatedResult {
+	pageSize := pagination.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return &data.PaginatedResult{
+		Data:       items,
+		Total:      total,
+		Page:       pagination.Page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    pagination.Page < totalPages,
+		HasPrev:    pagination.Page > 1,
+	}
+}
+
+func paginationBounds(pagination *data.Pagination) (limit, offset int) {
+	limit = pagination.PageSize
+	if limit <= 0 {
+		limit = 20
+	}
+	offset = (pagination.Page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// PostgreSQLUserRepository implements authDomain.UserRepository
+type PostgreSQLUserRepository struct {
+	db *sql.DB
+}
+
+const userColumns = `id, username, email, password_hash, first_name, last_name, role, status, created_at, updated_at, last_login_at, metadata`
+
+func (r *PostgreSQLUserRepository) Health(ctx context.Context) error { return r.db.PingContext(ctx) }
+func (r *PostgreSQLUserRepository) Close() error                     { return nil }
+
+func (r *PostgreSQLUserRepository) Create(ctx context.Context, user *sharedDomain.User) error {
+	metadataJSON, err := json.Marshal(user.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO users (id, username, email, password_hash, first_name, last_name, role, status, created_at, updated_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		user.ID, user.Username, user.Email, user.PasswordHash, user.FirstName,
+		user.LastName, user.Role, user.Status, user.CreatedAt, user.UpdatedAt, metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) GetByID(ctx context.Context, id string) (*sharedDomain.User, error) {
+	return r.scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+func (r *PostgreSQLUserRepository) GetByUsername(ctx context.Context, username string) (*sharedDomain.User, error) {
+	return r.scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
+
+func (r *PostgreSQLUserRepository) GetByEmail(ctx context.Context, email string) (*sharedDomain.User, error) {
+	return r.scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email))
+}
+
+func (r *PostgreSQLUserRepository) Update(ctx context.Context, user *sharedDomain.User) error {
+	metadataJSON, err := json.Marshal(user.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		UPDATE users
+		SET username = $2, email = $3, password_hash = $4, first_name = $5, last_name = $6,
+		    role = $7, status = $8, updated_at = $9, metadata = $10
+		WHERE id = $1
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		user.ID, user.Username, user.Email, user.PasswordHash, user.FirstName,
+		user.LastName, user.Role, user.Status, time.Now(), metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET password_hash = $2, updated_at = $3 WHERE id = $1`, userID, passwordHash, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) UpdateLastLogin(ctx context.Context, userID string, loginTime time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET last_login_at = $2 WHERE id = $1`, userID, loginTime)
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET status = $2, updated_at = $3 WHERE id = $1`, id, sharedDomain.UserStatusDeleted, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`, username).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgreSQLUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`, email).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgreSQLUserRepository) List(ctx context.Context, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, ``, nil, pagination)
+}
+
+func (r *PostgreSQLUserRepository) Search(ctx context.Context, criteria map[string]interface{}, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	where := ``
+	var args []interface{}
+	if username, ok := criteria["username"].(string); ok {
+		args = append(args, "%"+username+"%")
+		where = fmt.Sprintf("WHERE username ILIKE $%d", len(args))
+	}
+	return r.listWhere(ctx, where, args, pagination)
+}
+
+func (r *PostgreSQLUserRepository) GetActiveUserCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE status = $1`, sharedDomain.UserStatusActive).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLUserRepository) GetUsersByRole(ctx context.Context, role sharedDomain.UserRole, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE role = $1`, []interface{}{role}, pagination)
+}
+
+func (r *PostgreSQLUserRepository) GetUsersByStatus(ctx context.Context, status sharedDomain.UserStatus, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE status = $1`, []interface{}{status}, pagination)
+}
+
+// listWhere runs a filtered, paginated user listing
+func (r *PostgreSQLUserRepository) listWhere(ctx context.Context, where string, args []interface{}, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users `+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	limit, offset := paginationBounds(pagination)
+	query := fmt.Sprintf(`SELECT %s FROM users %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		userColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*sharedDomain.User
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return paginate(users, total, pagination), nil
+}
+
+// scanTarget matches both sql.Row and sql.Rows
+type scanTarget interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *PostgreSQLUserRepository) scanUser(row scanTarget) (*sharedDomain.User, error) {
+	var user sharedDomain.User
+	var lastLogin sql.NullTime
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.FirstName,
+		&user.LastName, &user.Role, &user.Status, &user.CreatedAt, &user.UpdatedAt,
+		&lastLogin, &metadataJSON,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	if lastLogin.Valid {
+		user.LastLoginAt = &lastLogin.Time
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &user.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &user, nil
+}
+
+// PostgreSQLSessionRepository implements authDomain.SessionRepository
+type PostgreSQLSessionRepository struct {
+	db *sql.DB
+}
+
+const sessionColumns = `id, user_id, token_id, ip_address, user_agent, fingerprint, country, created_at, expires_at, last_used_at, status`
+
+func (r *PostgreSQLSessionRepository) Health(ctx context.Context) error { return r.db.PingContext(ctx) }
+func (r *PostgreSQLSessionRepository) Close() error                     { return nil }
+
+func (r *PostgreSQLSessionRepository) Create(ctx context.Context, session *sharedDomain.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, token_id, ip_address, user_agent, fingerprint, country, created_at, expires_at, last_used_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.UserID, session.TokenID, session.IPAddress, session.UserAgent,
+		session.Fingerprint, session.Country, session.CreatedAt, session.ExpiresAt, session.LastUsedAt, session.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLSessionRepository) GetByID(ctx context.Context, id string) (*sharedDomain.Session, error) {
+	return r.scanSession(r.db.QueryRowContext(ctx, `SELECT `+sessionColumns+` FROM sessions WHERE id = $1`, id))
+}
+
+func (r *PostgreSQLSessionRepository) GetByTokenID(ctx context.Context, tokenID string) (*sharedDomain.Session, error) {
+	return r.scanSession(r.db.QueryRowContext(ctx, `SELECT `+sessionColumns+` FROM sessions WHERE token_id = $1`, tokenID))
+}
+
+func (r *PostgreSQLSessionRepository) GetByUserID(ctx context.Context, userID string) ([]*sharedDomain.Session, error) {
+	return r.querySessions(ctx, `SELECT `+sessionColumns+` FROM sessions WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+}
+
+func (r *PostgreSQLSessionRepository) Update(ctx context.Context, session *sharedDomain.Session) error {
+	query := `
+		UPDATE sessions
+		SET expires_at = $2, last_used_at = $3, status = $4, fingerprint = $5, country = $6
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.ExpiresAt, session.LastUsedAt, session.Status, session.Fingerprint, session.Country,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLSessionRepository) UpdateLastUsed(ctx context.Context, sessionID string, lastUsed time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE sessions SET last_used_at = $2 WHERE id = $1`, sessionID, lastUsed)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) DeleteByTokenID(ctx context.Context, tokenID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_id = $1`, tokenID)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) DeleteExpired(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < $1`, time.Now())
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) GetActiveSessions(ctx context.Context, userID string) ([]*sharedDomain.Session, error) {
+	return r.querySessions(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE user_id = $1 AND status = $2 AND expires_at > $3 ORDER BY created_at DESC`,
+		userID, sharedDomain.SessionStatusActive, time.Now())
+}
+
+func (r *PostgreSQLSessionRepository) GetAllActiveSessions(ctx context.Context, since time.Time) ([]*sharedDomain.Session, error) {
+	return r.querySessions(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE status = $1 AND expires_at > $2 AND created_at > $3`,
+		sharedDomain.SessionStatusActive, time.Now(), since)
+}
+
+func (r *PostgreSQLSessionRepository) GetActiveSessionCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sessions WHERE status = $1 AND expires_at > $2`,
+		sharedDomain.SessionStatusActive, time.Now()).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLSessionRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE sessions SET status = $2 WHERE id = $1`, sessionID, sharedDomain.SessionStatusRevoked)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) RevokeAllUserSessions(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE sessions SET status = $2 WHERE user_id = $1`, userID, sharedDomain.SessionStatusRevoked)
+	return err
+}
+
+func (r *PostgreSQLSessionRepository) querySessions(ctx context.Context, query string, args ...interface{}) ([]*sharedDomain.Session, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*sharedDomain.Session
+	for rows.Next() {
+		session, err := r.scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (r *PostgreSQLSessionRepository) scanSession(row scanTarget) (*sharedDomain.Session, error) {
+	var session sharedDomain.Session
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.TokenID, &session.IPAddress, &session.UserAgent,
+		&session.Fingerprint, &session.Country, &session.CreatedAt, &session.ExpiresAt,
+		&session.LastUsedAt, &session.Status,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, fmt.Errorf("failed to scan session: %w", err)
+	}
+	return &session, nil
+}
+
+// PostgreSQLRevokedTokenRepository implements authDomain.RevokedTokenRepository
+type PostgreSQLRevokedTokenRepository struct {
+	db *sql.DB
+}
+
+func (r *PostgreSQLRevokedTokenRepository) Health(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+func (r *PostgreSQLRevokedTokenRepository) Close() error { return nil }
+
+func (r *PostgreSQLRevokedTokenRepository) Create(ctx context.Context, token *sharedDomain.RevokedToken) error {
+	query := `
+		INSERT INTO revoked_tokens (id, token_id, user_id, reason, revoked_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.TokenID, token.UserID, token.Reason, token.RevokedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create revoked token: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLRevokedTokenRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE token_id = $1)`, tokenID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgreSQLRevokedTokenRepository) GetByTokenID(ctx context.Context, tokenID string) (*sharedDomain.RevokedToken, error) {
+	var token sharedDomain.RevokedToken
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, token_id, user_id, reason, revoked_at, expires_at FROM revoked_tokens WHERE token_id = $1`, tokenID,
+	).Scan(&token.ID, &token.TokenID, &token.UserID, &token.Reason, &token.RevokedAt, &token.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revoked token not found")
+		}
+		return nil, fmt.Errorf("failed to scan revoked token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *PostgreSQLRevokedTokenRepository) GetByUserID(ctx context.Context, userID string) ([]*sharedDomain.RevokedToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, token_id, user_id, reason, revoked_at, expires_at FROM revoked_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revoked tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*sharedDomain.RevokedToken
+	for rows.Next() {
+		var token sharedDomain.RevokedToken
+		if err := rows.Scan(&token.ID, &token.TokenID, &token.UserID, &token.Reason, &token.RevokedAt, &token.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revoked token: %w", err)
+		}
+		tokens = append(tokens, &token)
+	}
+	return tokens, nil
+}
+
+func (r *PostgreSQLRevokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < $1`, time.Now())
+	return err
+}
+
+func (r *PostgreSQLRevokedTokenRepository) RevokeToken(ctx context.Context, tokenID, userID, reason string, expiresAt time.Time) error {
+	token := &sharedDomain.RevokedToken{
+		ID:        fmt.Sprintf("revoked_%s", tokenID),
+		TokenID:   tokenID,
+		UserID:    userID,
+		Reason:    reason,
+		RevokedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	return r.Create(ctx, token)
+}
+
+func (r *PostgreSQLRevokedTokenRepository) RevokeAllUserTokens(ctx context.Context, userID, reason string) error {
+	// Revoking all outstanding tokens requires the session table as the
+	// source of issued token IDs
+	query := `
+		INSERT INTO revoked_tokens (id, token_id, user_id, reason, revoked_at, expires_at)
+		SELECT 'revoked_' || token_id, token_id, user_id, $2, $3, expires_at
+		FROM sessions WHERE user_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, reason, time.Now())
+	return err
+}
+
+func (r *PostgreSQLRevokedTokenRepository) GetRevokedTokenCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM revoked_tokens`).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLRevokedTokenRepository) CleanupExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PostgreSQLPasswordResetTokenRepository implements authDomain.PasswordResetTokenRepository
+type PostgreSQLPasswordResetTokenRepository struct {
+	db *sql.DB
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) Health(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+func (r *PostgreSQLPasswordResetTokenRepository) Close() error { return nil }
+
+func (r *PostgreSQLPasswordResetTokenRepository) Create(ctx context.Context, token *authDomain.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token, used, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.Token, token.Used, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) GetByToken(ctx context.Context, token string) (*authDomain.PasswordResetToken, error) {
+	var reset authDomain.PasswordResetToken
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, user_id, token, used, created_at, expires_at FROM password_reset_tokens WHERE token = $1`, token,
+	).Scan(&reset.ID, &reset.UserID, &reset.Token, &reset.Used, &reset.CreatedAt, &reset.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("password reset token not found")
+		}
+		return nil, fmt.Errorf("failed to scan password reset token: %w", err)
+	}
+	return &reset, nil
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) GetByUserID(ctx context.Context, userID string) ([]*authDomain.PasswordResetToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, token, used, created_at, expires_at FROM password_reset_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query password reset tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*authDomain.PasswordResetToken
+	for rows.Next() {
+		var reset authDomain.PasswordResetToken
+		if err := rows.Scan(&reset.ID, &reset.UserID, &reset.Token, &reset.Used, &reset.CreatedAt, &reset.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan password reset token: %w", err)
+		}
+		tokens = append(tokens, &reset)
+	}
+	return tokens, nil
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) MarkAsUsed(ctx context.Context, tokenID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE password_reset_tokens SET used = TRUE WHERE id = $1`, tokenID)
+	return err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE id = $1`, id)
+	return err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) DeleteExpired(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < $1`, time.Now())
+	return err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) IsValidToken(ctx context.Context, token string) (bool, error) {
+	var valid bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM password_reset_tokens WHERE token = $1 AND NOT used AND expires_at > $2)`,
+		token, time.Now()).Scan(&valid)
+	return valid, err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) GetActiveTokensCount(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM password_reset_tokens WHERE user_id = $1 AND NOT used AND expires_at > $2`,
+		userID, time.Now()).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLPasswordResetTokenRepository) CleanupExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PostgreSQLLoginAttemptRepository implements authDomain.LoginAttemptRepository
+type PostgreSQLLoginAttemptRepository struct {
+	db *sql.DB
+}
+
+const loginAttemptColumns = `id, identifier, success, ip_address, user_agent, timestamp`
+
+func (r *PostgreSQLLoginAttemptRepository) Health(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+func (r *PostgreSQLLoginAttemptRepository) Close() error { return nil }
+
+func (r *PostgreSQLLoginAttemptRepository) Create(ctx context.Context, attempt *authDomain.LoginAttempt) error {
+	query := `
+		INSERT INTO login_attempts (id, identifier, success, ip_address, user_agent, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		attempt.ID, attempt.Identifier, attempt.Success, attempt.IPAddress, attempt.UserAgent, attempt.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to create login attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLLoginAttemptRepository) GetRecentAttempts(ctx context.Context, identifier string, since time.Time) ([]*authDomain.LoginAttempt, error) {
+	return r.queryAttempts(ctx,
+		`SELECT `+loginAttemptColumns+` FROM login_attempts WHERE identifier = $1 AND timestamp > $2 ORDER BY timestamp DESC`,
+		identifier, since)
+}
+
+func (r *PostgreSQLLoginAttemptRepository) GetFailedAttempts(ctx context.Context, identifier string, since time.Time) ([]*authDomain.LoginAttempt, error) {
+	return r.queryAttempts(ctx,
+		`SELECT `+loginAttemptColumns+` FROM login_attempts WHERE identifier = $1 AND NOT success AND timestamp > $2 ORDER BY timestamp DESC`,
+		identifier, since)
+}
+
+func (r *PostgreSQLLoginAttemptRepository) GetSuccessfulAttempts(ctx context.Context, identifier string, since time.Time) ([]*authDomain.LoginAttempt, error) {
+	return r.queryAttempts(ctx,
+		`SELECT `+loginAttemptColumns+` FROM login_attempts WHERE identifier = $1 AND success AND timestamp > $2 ORDER BY timestamp DESC`,
+		identifier, since)
+}
+
+func (r *PostgreSQLLoginAttemptRepository) CountFailedAttempts(ctx context.Context, identifier string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM login_attempts WHERE identifier = $1 AND NOT success AND timestamp > $2`,
+		identifier, since).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLLoginAttemptRepository) CountAttemptsByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM login_attempts WHERE ip_address = $1 AND timestamp > $2`,
+		ipAddress, since).Scan(&count)
+	return count, err
+}
+
+func (r *PostgreSQLLoginAttemptRepository) DeleteOldAttempts(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE timestamp < $1`, before)
+	return err
+}
+
+func (r *PostgreSQLLoginAttemptRepository) GetAttemptsByTimeRange(ctx context.Context, start, end time.Time, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM login_attempts WHERE timestamp BETWEEN $1 AND $2`, start, end).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+
+	limit, offset := paginationBounds(pagination)
+	attempts, err := r.queryAttempts(ctx,
+		`SELECT `+loginAttemptColumns+` FROM login_attempts WHERE timestamp BETWEEN $1 AND $2 ORDER BY timestamp DESC LIMIT $3 OFFSET $4`,
+		start, end, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(attempts, total, pagination), nil
+}
+
+func (r *PostgreSQLLoginAttemptRepository) GetSuspiciousActivity(ctx context.Context, threshold int, since time.Time) ([]*authDomain.LoginAttempt, error) {
+	// Identifiers with at least threshold failed attempts in the window
+	query := `
+		SELECT ` + loginAttemptColumns + `
+		FROM login_attempts
+		WHERE NOT success AND timestamp > $1
+		  AND identifier IN (
+			SELECT identifier FROM login_attempts
+			WHERE NOT success AND timestamp > $1
+			GROUP BY identifier HAVING COUNT(*) >= $2
+		  )
+		ORDER BY timestamp DESC
+	`
+	return r.queryAttempts(ctx, query, since, threshold)
+}
+
+func (r *PostgreSQLLoginAttemptRepository) CleanupOldAttempts(ctx context.Context, retentionPeriod time.Duration) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE timestamp < $1`, time.Now().Add(-retentionPeriod))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgreSQLLoginAttemptRepository) queryAttempts(ctx context.Context, query string, args ...interface{}) ([]*authDomain.LoginAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []*authDomain.LoginAttempt
+	for rows.Next() {
+		var attempt authDomain.LoginAttempt
+		if err := rows.Scan(&attempt.ID, &attempt.Identifier, &attempt.Success, &attempt.IPAddress, &attempt.UserAgent, &attempt.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt: %w", err)
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, nil
+}
+
+// PostgreSQLActivityLogRepository implements authDomain.ActivityLogRepository
+type PostgreSQLActivityLogRepository struct {
+	db *sql.DB
+}
+
+const activityLogColumns = `id, user_id, action, resource_type, resource_id, details, ip_address, user_agent, timestamp, status`
+
+func (r *PostgreSQLActivityLogRepository) Health(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+func (r *PostgreSQLActivityLogRepository) Close() error { return nil }
+
+func (r *PostgreSQLActivityLogRepository) Create(ctx context.Context, entry *sharedDomain.ActivityLog) error {
+	detailsJSON, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal details: %w", err)
+	}
+
+	query := `
+		INSERT INTO activity_logs (id, user_id, action, resource_type, resource_id, details, ip_address, user_agent, timestamp, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		entry.ID, entry.UserID, entry.Action, entry.ResourceType, entry.ResourceID,
+		detailsJSON, entry.IPAddress, entry.UserAgent, entry.Timestamp, entry.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create activity log: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgreSQLActivityLogRepository) GetByUserID(ctx context.Context, userID string, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE user_id = $1`, []interface{}{userID}, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) GetByAction(ctx context.Context, action string, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE action = $1`, []interface{}{action}, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) GetByTimeRange(ctx context.Context, start, end time.Time, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE timestamp BETWEEN $1 AND $2`, []interface{}{start, end}, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) GetByResourceType(ctx context.Context, resourceType string, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE resource_type = $1`, []interface{}{resourceType}, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) Search(ctx context.Context, criteria map[string]interface{}, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	where := ``
+	var args []interface{}
+	if action, ok := criteria["action"].(string); ok {
+		args = append(args, action)
+		where = fmt.Sprintf("WHERE action = $%d", len(args))
+	}
+	return r.listWhere(ctx, where, args, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) DeleteOldLogs(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM activity_logs WHERE timestamp < $1`, before)
+	return err
+}
+
+func (r *PostgreSQLActivityLogRepository) GetSecurityEvents(ctx context.Context, since time.Time, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	return r.listWhere(ctx, `WHERE resource_type = $1 AND timestamp > $2`, []interface{}{"security", since}, pagination)
+}
+
+func (r *PostgreSQLActivityLogRepository) GetUserActivity(ctx context.Context, userID string, since time.Time) (map[string]int64, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT action, COUNT(*) FROM activity_logs WHERE user_id = $1 AND timestamp > $2 GROUP BY action`,
+		userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user activity: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var action string
+		var count int64
+		if err := rows.Scan(&action, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan activity count: %w", err)
+		}
+		counts[action] = count
+	}
+	return counts, nil
+}
+
+func (r *PostgreSQLActivityLogRepository) CleanupOldLogs(ctx context.Context, retentionPeriod time.Duration) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM activity_logs WHERE timestamp < $1`, time.Now().Add(-retentionPeriod))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// listWhere runs a filtered, paginated activity log listing
+func (r *PostgreSQLActivityLogRepository) listWhere(ctx context.Context, where string, args []interface{}, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM activity_logs `+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count activity logs: %w", err)
+	}
+
+	limit, offset := paginationBounds(pagination)
+	query := fmt.Sprintf(`SELECT %s FROM activity_logs %s ORDER BY timestamp DESC LIMIT $%d OFFSET $%d`,
+		activityLogColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activity logs: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*sharedDomain.ActivityLog
+	for rows.Next() {
+		var entry sharedDomain.ActivityLog
+		var detailsJSON []byte
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Action, &entry.ResourceType, &entry.ResourceID,
+			&detailsJSON, &entry.IPAddress, &entry.UserAgent, &entry.Timestamp, &entry.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan activity log: %w", err)
+		}
+		if len(detailsJSON) > 0 {
+			if err := json.Unmarshal(detailsJSON, &entry.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal details: %w", err)
+			}
+		}
+		entries = append(entries, &entry)
+	}
+
+	return paginate(entries, total, pagination), nil
+}
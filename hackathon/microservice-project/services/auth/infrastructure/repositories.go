@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -72,9 +73,11 @@ func (m *MockRepositoryManager) GetActivityLogRepository() authDomain.ActivityLo
 	return &MockActivityLogRepository{manager: m}
 }
 
-// GetCacheRepository returns the cache repository
+// GetCacheRepository returns the cache repository. Cache keys are namespaced
+// with the AUTH_CACHE_KEY_PREFIX environment variable (e.g. "auth:production")
+// to avoid collisions when services share a cache instance.
 func (m *MockRepositoryManager) GetCacheRepository() authDomain.AuthCacheRepository {
-	return &MockCacheRepository{manager: m}
+	return &MockCacheRepository{manager: m, keyPrefix: os.Getenv("AUTH_CACHE_KEY_PREFIX")}
 }
 
 // BeginTransaction starts a new transaction
@@ -493,6 +496,19 @@ func (r *MockSessionRepository) GetActiveSessionsForUser(ctx context.Context, us
 	return count, nil
 }
 
+func (r *MockSessionRepository) GetAllActiveSessions(ctx context.Context, since time.Time) ([]*sharedDomain.Session, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	now := time.Now()
+	var sessions []*sharedDomain.Session
+	for _, session := range r.manager.sessions {
+		if session.ExpiresAt.After(now) && session.CreatedAt.After(since) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
 func (r *MockSessionRepository) GetActiveSessions(ctx context.Context, userID string) ([]*sharedDomain.Session, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
@@ -599,13 +615,21 @@ func (r *MockRevokedTokenRepository) IsRevoked(ctx context.Context, tokenID stri
 func (r *MockRevokedTokenRepository) RevokeAllUserTokens(ctx context.Context, userID, reason string) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	// In a real implementation, this would revoke all tokens for a user
-	// For mock, we'll just add a placeholder entry
-	r.manager.revokedTokens["all_"+userID] = &sharedDomain.RevokedToken{
-		TokenID:   "all_" + userID,
-		UserID:    userID,
-		Reason:    reason,
-		RevokedAt: time.Now(),
+	// Like the SQL implementation, the session table is the source of
+	// issued token IDs: revoke every token ID a live session references so
+	// IsRevoked actually matches them
+	now := time.Now()
+	for _, session := range r.manager.sessions {
+		if session.UserID != userID || session.TokenID == "" {
+			continue
+		}
+		r.manager.revokedTokens[session.TokenID] = &sharedDomain.RevokedToken{
+			TokenID:   session.TokenID,
+			UserID:    userID,
+			Reason:    reason,
+			RevokedAt: now,
+			ExpiresAt: session.ExpiresAt,
+		}
 	}
 	return nil
 }
@@ -1209,13 +1233,23 @@ func (r *MockActivityLogRepository) DeleteOldLogs(ctx context.Context, before ti
 
 // MockCacheRepository implements domain.CacheRepository
 type MockCacheRepository struct {
-	manager *MockRepositoryManager
+	manager   *MockRepositoryManager
+	keyPrefix string
+}
+
+// key prepends the configured service namespace to a cache key so that
+// multiple services/environments can safely share one cache backend
+func (r *MockCacheRepository) key(k string) string {
+	if r.keyPrefix == "" {
+		return k
+	}
+	return r.keyPrefix + ":" + k
 }
 
 func (r *MockCacheRepository) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache[key] = CacheItem{
+	r.manager.cache[r.key(key)] = CacheItem{
 		Value:     value,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1225,12 +1259,12 @@ func (r *MockCacheRepository) Set(ctx context.Context, key string, value []byte,
 func (r *MockCacheRepository) Get(ctx context.Context, key string) ([]byte, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache[key]
+	item, exists := r.manager.cache[r.key(key)]
 	if !exists {
 		return nil, fmt.Errorf("key not found")
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, key)
+		delete(r.manager.cache, r.key(key))
 		return nil, fmt.Errorf("key expired")
 	}
 	if bytes, ok := item.Value.([]byte); ok {
@@ -1242,14 +1276,14 @@ func (r *MockCacheRepository) Get(ctx context.Context, key string) ([]byte, erro
 func (r *MockCacheRepository) Delete(ctx context.Context, key string) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	delete(r.manager.cache, key)
+	delete(r.manager.cache, r.key(key))
 	return nil
 }
 
 func (r *MockCacheRepository) SetUserSession(ctx context.Context, sessionID string, user *authDomain.AuthUser, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["session:"+sessionID] = CacheItem{
+	r.manager.cache[r.key("session:"+sessionID)] = CacheItem{
 		Value:     user,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1259,12 +1293,12 @@ func (r *MockCacheRepository) SetUserSession(ctx context.Context, sessionID stri
 func (r *MockCacheRepository) GetUserSession(ctx context.Context, sessionID string) (*authDomain.AuthUser, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["session:"+sessionID]
+	item, exists := r.manager.cache[r.key("session:"+sessionID)]
 	if !exists {
 		return nil, fmt.Errorf("session not found")
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "session:"+sessionID)
+		delete(r.manager.cache, r.key("session:"+sessionID))
 		return nil, fmt.Errorf("session expired")
 	}
 	user, ok := item.Value.(*authDomain.AuthUser)
@@ -1281,7 +1315,7 @@ func (r *MockCacheRepository) DeleteUserSession(ctx context.Context, sessionID s
 func (r *MockCacheRepository) SetLoginAttempts(ctx context.Context, identifier string, count int, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["login_attempts:"+identifier] = CacheItem{
+	r.manager.cache[r.key("login_attempts:"+identifier)] = CacheItem{
 		Value:     count,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1291,12 +1325,12 @@ func (r *MockCacheRepository) SetLoginAttempts(ctx context.Context, identifier s
 func (r *MockCacheRepository) GetLoginAttempts(ctx context.Context, identifier string) (int, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["login_attempts:"+identifier]
+	item, exists := r.manager.cache[r.key("login_attempts:"+identifier)]
 	if !exists {
 		return 0, nil
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "login_attempts:"+identifier)
+		delete(r.manager.cache, r.key("login_attempts:"+identifier))
 		return 0, nil
 	}
 	count, ok := item.Value.(int)
@@ -1326,9 +1360,9 @@ func (r *MockCacheRepository) Health(ctx context.Context) error {
 func (r *MockCacheRepository) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	if item, exists := r.manager.cache[key]; exists {
+	if item, exists := r.manager.cache[r.key(key)]; exists {
 		item.ExpiresAt = time.Now().Add(expiration)
-		r.manager.cache[key] = item
+		r.manager.cache[r.key(key)] = item
 	}
 	return nil
 }
@@ -1353,10 +1387,10 @@ func (r *MockCacheRepository) FlushAll(ctx context.Context) error {
 func (r *MockCacheRepository) SetNX(ctx context.Context, key string, value []byte, expiration time.Duration) (bool, error) {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	if _, exists := r.manager.cache[key]; exists {
+	if _, exists := r.manager.cache[r.key(key)]; exists {
 		return false, nil
 	}
-	r.manager.cache[key] = CacheItem{
+	r.manager.cache[r.key(key)] = CacheItem{
 		Value:     value,
 		ExpiresAt: time.Now().Add(expiration),
 	}
@@ -1370,14 +1404,14 @@ func (r *MockCacheRepository) Close() error {
 func (r *MockCacheRepository) Increment(ctx context.Context, key string) (int64, error) {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	value, exists := r.manager.cache[key]
+	value, exists := r.manager.cache[r.key(key)]
 	if !exists {
-		r.manager.cache[key] = CacheItem{Value: int64(1), ExpiresAt: time.Now().Add(time.Hour)}
+		r.manager.cache[r.key(key)] = CacheItem{Value: int64(1), ExpiresAt: time.Now().Add(time.Hour)}
 		return 1, nil
 	}
 	if intVal, ok := value.Value.(int64); ok {
 		newVal := intVal + 1
-		r.manager.cache[key] = CacheItem{Value: newVal, ExpiresAt: value.ExpiresAt}
+		r.manager.cache[r.key(key)] = CacheItem{Value: newVal, ExpiresAt: value.ExpiresAt}
 		return newVal, nil
 	}
 	return 0, fmt.Errorf("value is not an integer")
@@ -1386,14 +1420,14 @@ func (r *MockCacheRepository) Increment(ctx context.Context, key string) (int64,
 func (r *MockCacheRepository) Decrement(ctx context.Context, key string) (int64, error) {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	value, exists := r.manager.cache[key]
+	value, exists := r.manager.cache[r.key(key)]
 	if !exists {
-		r.manager.cache[key] = CacheItem{Value: int64(-1), ExpiresAt: time.Now().Add(time.Hour)}
+		r.manager.cache[r.key(key)] = CacheItem{Value: int64(-1), ExpiresAt: time.Now().Add(time.Hour)}
 		return -1, nil
 	}
 	if intVal, ok := value.Value.(int64); ok {
 		newVal := intVal - 1
-		r.manager.cache[key] = CacheItem{Value: newVal, ExpiresAt: value.ExpiresAt}
+		r.manager.cache[r.key(key)] = CacheItem{Value: newVal, ExpiresAt: value.ExpiresAt}
 		return newVal, nil
 	}
 	return 0, fmt.Errorf("value is not an integer")
@@ -1402,7 +1436,7 @@ func (r *MockCacheRepository) Decrement(ctx context.Context, key string) (int64,
 func (r *MockCacheRepository) SetPasswordResetToken(ctx context.Context, token string, userID string, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["password_reset:"+token] = CacheItem{
+	r.manager.cache[r.key("password_reset:"+token)] = CacheItem{
 		Value:     userID,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1412,12 +1446,12 @@ func (r *MockCacheRepository) SetPasswordResetToken(ctx context.Context, token s
 func (r *MockCacheRepository) GetPasswordResetToken(ctx context.Context, token string) (string, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["password_reset:"+token]
+	item, exists := r.manager.cache[r.key("password_reset:"+token)]
 	if !exists {
 		return "", fmt.Errorf("token not found")
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "password_reset:"+token)
+		delete(r.manager.cache, r.key("password_reset:"+token))
 		return "", fmt.Errorf("token expired")
 	}
 	userID, ok := item.Value.(string)
@@ -1434,7 +1468,7 @@ func (r *MockCacheRepository) DeletePasswordResetToken(ctx context.Context, toke
 func (r *MockCacheRepository) SetRevokedToken(ctx context.Context, tokenID string, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["revoked:"+tokenID] = CacheItem{
+	r.manager.cache[r.key("revoked:"+tokenID)] = CacheItem{
 		Value:     true,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1444,12 +1478,12 @@ func (r *MockCacheRepository) SetRevokedToken(ctx context.Context, tokenID strin
 func (r *MockCacheRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["revoked:"+tokenID]
+	item, exists := r.manager.cache[r.key("revoked:"+tokenID)]
 	if !exists {
 		return false, nil
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "revoked:"+tokenID)
+		delete(r.manager.cache, r.key("revoked:"+tokenID))
 		return false, nil
 	}
 	return true, nil
@@ -1458,7 +1492,7 @@ func (r *MockCacheRepository) IsTokenRevoked(ctx context.Context, tokenID string
 func (r *MockCacheRepository) SetUserLockout(ctx context.Context, userID string, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["lockout:"+userID] = CacheItem{
+	r.manager.cache[r.key("lockout:"+userID)] = CacheItem{
 		Value:     true,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1468,12 +1502,12 @@ func (r *MockCacheRepository) SetUserLockout(ctx context.Context, userID string,
 func (r *MockCacheRepository) IsUserLockedOut(ctx context.Context, userID string) (bool, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["lockout:"+userID]
+	item, exists := r.manager.cache[r.key("lockout:"+userID)]
 	if !exists {
 		return false, nil
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "lockout:"+userID)
+		delete(r.manager.cache, r.key("lockout:"+userID))
 		return false, nil
 	}
 	return true, nil
@@ -1486,7 +1520,7 @@ func (r *MockCacheRepository) RemoveUserLockout(ctx context.Context, userID stri
 func (r *MockCacheRepository) SetRateLimitCounter(ctx context.Context, key string, count int, ttl time.Duration) error {
 	r.manager.mu.Lock()
 	defer r.manager.mu.Unlock()
-	r.manager.cache["rate:"+key] = CacheItem{
+	r.manager.cache[r.key("rate:"+key)] = CacheItem{
 		Value:     count,
 		ExpiresAt: time.Now().Add(ttl),
 	}
@@ -1496,12 +1530,12 @@ func (r *MockCacheRepository) SetRateLimitCounter(ctx context.Context, key strin
 func (r *MockCacheRepository) GetRateLimitCounter(ctx context.Context, key string) (int, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache["rate:"+key]
+	item, exists := r.manager.cache[r.key("rate:"+key)]
 	if !exists {
 		return 0, nil
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, "rate:"+key)
+		delete(r.manager.cache, r.key("rate:"+key))
 		return 0, nil
 	}
 	count, ok := item.Value.(int)
@@ -1523,12 +1557,12 @@ func (r *MockCacheRepository) IncrementRateLimitCounter(ctx context.Context, key
 func (r *MockCacheRepository) Exists(ctx context.Context, key string) (bool, error) {
 	r.manager.mu.RLock()
 	defer r.manager.mu.RUnlock()
-	item, exists := r.manager.cache[key]
+	item, exists := r.manager.cache[r.key(key)]
 	if !exists {
 		return false, nil
 	}
 	if time.Now().After(item.ExpiresAt) {
-		delete(r.manager.cache, key)
+		delete(r.manager.cache, r.key(key))
 		return false, nil
 	}
 	return true, nil
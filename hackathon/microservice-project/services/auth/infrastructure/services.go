@@ -0,0 +1,415 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	authDomain "github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/services/auth/usecases"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+	"github.com/elotusteam/microservice-project/shared/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LogNotificationService implements usecases.NotificationService by logging
+// instead of delivering. It stands in until real email/SMS/push providers
+// are wired and keeps the auth flows fully functional in development.
+type LogNotificationService struct{}
+
+// NewLogNotificationService creates a logging notification service
+func NewLogNotificationService() usecases.NotificationService {
+	return &LogNotificationService{}
+}
+
+func (s *LogNotificationService) SendWelcomeEmail(ctx context.Context, email, firstName string) error {
+	log.Printf("notification: welcome email to %s (%s)", email, firstName)
+	return nil
+}
+
+func (s *LogNotificationService) SendPasswordResetEmail(ctx context.Context, email, resetToken string) error {
+	log.Printf("notification: password reset email to %s", email)
+	return nil
+}
+
+func (s *LogNotificationService) SendPasswordChangedEmail(ctx context.Context, email, firstName string) error {
+	log.Printf("notification: password changed email to %s", email)
+	return nil
+}
+
+func (s *LogNotificationService) SendAccountLockedEmail(ctx context.Context, email, firstName string) error {
+	log.Printf("notification: account locked email to %s", email)
+	return nil
+}
+
+func (s *LogNotificationService) SendLoginAlertEmail(ctx context.Context, email, firstName, ipAddress, userAgent string) error {
+	log.Printf("notification: login alert email to %s from %s", email, ipAddress)
+	return nil
+}
+
+func (s *LogNotificationService) SendMagicLinkEmail(ctx context.Context, email, magicLink string) error {
+	log.Printf("notification: magic link email to %s", email)
+	return nil
+}
+
+func (s *LogNotificationService) SendSMS(ctx context.Context, phoneNumber, message string) error {
+	log.Printf("notification: SMS to %s", phoneNumber)
+	return nil
+}
+
+func (s *LogNotificationService) SendLoginAlertSMS(ctx context.Context, phoneNumber, message string) error {
+	log.Printf("notification: login alert SMS to %s", phoneNumber)
+	return nil
+}
+
+func (s *LogNotificationService) SendPushNotification(ctx context.Context, userID, title, message string, data map[string]interface{}) error {
+	log.Printf("notification: push to user %s: %s", userID, title)
+	return nil
+}
+
+func (s *LogNotificationService) CreateNotification(ctx context.Context, userID, title, message, notificationType string, data map[string]interface{}) error {
+	log.Printf("notification: in-app for user %s: %s", userID, title)
+	return nil
+}
+
+func (s *LogNotificationService) MarkAsRead(ctx context.Context, notificationID string) error {
+	return nil
+}
+
+func (s *LogNotificationService) GetUserNotifications(ctx context.Context, userID string, limit, offset int) ([]*sharedDomain.Notification, error) {
+	return nil, nil
+}
+
+func (s *LogNotificationService) GetUserPreferences(ctx context.Context, userID string) (*usecases.NotificationPreferences, error) {
+	return &usecases.NotificationPreferences{UserID: userID, EmailEnabled: true}, nil
+}
+
+func (s *LogNotificationService) UpdateUserPreferences(ctx context.Context, userID string, preferences *usecases.NotificationPreferences) error {
+	return nil
+}
+
+// CacheRateLimitService implements usecases.RateLimitService on top of the
+// auth cache repository's rate-limit counters
+type CacheRateLimitService struct {
+	repoManager authDomain.RepositoryManager
+}
+
+// NewCacheRateLimitService creates a cache-backed rate limit service
+func NewCacheRateLimitService(repoManager authDomain.RepositoryManager) usecases.RateLimitService {
+	return &CacheRateLimitService{repoManager: repoManager}
+}
+
+func (s *CacheRateLimitService) CheckRateLimit(ctx context.Context, identifier string, limit int, window time.Duration) (bool, error) {
+	count, err := s.repoManager.GetCacheRepository().IncrementRateLimitCounter(ctx, identifier, window)
+	if err != nil {
+		return false, err
+	}
+	return count <= limit, nil
+}
+
+func (s *CacheRateLimitService) IncrementCounter(ctx context.Context, identifier string, window time.Duration) error {
+	_, err := s.repoManager.GetCacheRepository().IncrementRateLimitCounter(ctx, identifier, window)
+	return err
+}
+
+func (s *CacheRateLimitService) GetCounter(ctx context.Context, identifier string) (int, error) {
+	return s.repoManager.GetCacheRepository().GetRateLimitCounter(ctx, identifier)
+}
+
+func (s *CacheRateLimitService) ResetCounter(ctx context.Context, identifier string) error {
+	return s.repoManager.GetCacheRepository().SetRateLimitCounter(ctx, identifier, 0, time.Minute)
+}
+
+func (s *CacheRateLimitService) CheckSlidingWindowRateLimit(ctx context.Context, identifier string, limit int, window time.Duration) (bool, error) {
+	// The fixed-window counter is a reasonable approximation here
+	return s.CheckRateLimit(ctx, identifier, limit, window)
+}
+
+func (s *CacheRateLimitService) CheckTokenBucketRateLimit(ctx context.Context, identifier string, capacity, refillRate int, window time.Duration) (bool, error) {
+	return s.CheckRateLimit(ctx, identifier, capacity, window)
+}
+
+func (s *CacheRateLimitService) GetRateLimitInfo(ctx context.Context, identifier string) (*usecases.RateLimitInfo, error) {
+	count, err := s.GetCounter(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return &usecases.RateLimitInfo{
+		Identifier: identifier,
+		Remaining:  count,
+	}, nil
+}
+
+// RepositorySecurityService implements usecases.SecurityService using the
+// auth repositories (cache for lockouts, login-attempt table for tracking).
+// Password history lives in-process until a dedicated table is added.
+type RepositorySecurityService struct {
+	repoManager authDomain.RepositoryManager
+
+	historyMu       sync.Mutex
+	passwordHistory map[string][]string
+
+	// Known login context per user for anomaly detection
+	anomalyMu      sync.Mutex
+	knownDevices   map[string]map[string]bool
+	knownCountries map[string]map[string]bool
+}
+
+// NewRepositorySecurityService creates a repository-backed security service
+func NewRepositorySecurityService(repoManager authDomain.RepositoryManager) usecases.SecurityService {
+	return &RepositorySecurityService{
+		repoManager:     repoManager,
+		passwordHistory: make(map[string][]string),
+		knownDevices:    make(map[string]map[string]bool),
+		knownCountries:  make(map[string]map[string]bool),
+	}
+}
+
+func (s *RepositorySecurityService) IsUserLockedOut(ctx context.Context, userID string) (bool, error) {
+	return s.repoManager.GetCacheRepository().IsUserLockedOut(ctx, userID)
+}
+
+func (s *RepositorySecurityService) LockUser(ctx context.Context, userID string, duration time.Duration, reason string) error {
+	log.Printf("security: locking user %s for %s: %s", userID, duration, reason)
+	return s.repoManager.GetCacheRepository().SetUserLockout(ctx, userID, duration)
+}
+
+func (s *RepositorySecurityService) UnlockUser(ctx context.Context, userID string) error {
+	return s.repoManager.GetCacheRepository().RemoveUserLockout(ctx, userID)
+}
+
+func (s *RepositorySecurityService) GetLockoutInfo(ctx context.Context, userID string) (*usecases.LockoutInfo, error) {
+	locked, err := s.IsUserLockedOut(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &usecases.LockoutInfo{UserID: userID, Locked: locked}, nil
+}
+
+func (s *RepositorySecurityService) RecordLoginAttempt(ctx context.Context, identifier string, success bool, ipAddress, userAgent string) error {
+	attempt := &authDomain.LoginAttempt{
+		ID:         utils.GenerateID(),
+		Identifier: identifier,
+		Success:    success,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Timestamp:  time.Now(),
+	}
+	return s.repoManager.GetLoginAttemptRepository().Create(ctx, attempt)
+}
+
+func (s *RepositorySecurityService) GetLoginAttempts(ctx context.Context, identifier string, since time.Time) ([]*authDomain.LoginAttempt, error) {
+	return s.repoManager.GetLoginAttemptRepository().GetRecentAttempts(ctx, identifier, since)
+}
+
+func (s *RepositorySecurityService) ClearLoginAttempts(ctx context.Context, identifier string) error {
+	return s.repoManager.GetCacheRepository().ResetLoginAttempts(ctx, identifier)
+}
+
+// DetectSuspiciousActivity scores a login against the user's known devices
+// and countries. A new device or country raises the risk; combined with a
+// burst of recent failures, the login is flagged as anomalous. The score is
+// recorded in the activity log for audit.
+func (s *RepositorySecurityService) DetectSuspiciousActivity(ctx context.Context, userID, identifier, ipAddress, userAgent string) (bool, error) {
+	riskScore := 0
+	var reasons []string
+
+	deviceHash := hashDevice(userAgent)
+	country := countryForIP(ipAddress)
+
+	s.anomalyMu.Lock()
+	if s.knownDevices[userID] == nil {
+		s.knownDevices[userID] = make(map[string]bool)
+	}
+	if s.knownCountries[userID] == nil {
+		s.knownCountries[userID] = make(map[string]bool)
+	}
+
+	firstLogin := len(s.knownDevices[userID]) == 0
+	if !firstLogin && !s.knownDevices[userID][deviceHash] {
+		riskScore += 40
+		reasons = append(reasons, "new device")
+	}
+	if !firstLogin && !s.knownCountries[userID][country] {
+		riskScore += 40
+		reasons = append(reasons, "new country: "+country)
+	}
+
+	// Learn the context either way so repeats are not re-flagged
+	s.knownDevices[userID][deviceHash] = true
+	s.knownCountries[userID][country] = true
+	s.anomalyMu.Unlock()
+
+	// A burst of recent failed attempts compounds the risk. Attempts are
+	// recorded under the login identifier, not the user ID.
+	failed, err := s.repoManager.GetLoginAttemptRepository().CountFailedAttempts(ctx, identifier, time.Now().Add(-15*time.Minute))
+	if err == nil && failed >= 3 {
+		riskScore += 30
+		reasons = append(reasons, "recent failed attempts")
+	}
+
+	suspicious := riskScore >= 40
+
+	// Record the risk assessment for audit
+	entry := &sharedDomain.ActivityLog{
+		ID:           utils.GenerateID(),
+		UserID:       &userID,
+		Action:       "login.risk_assessment",
+		ResourceType: "security",
+		Details: map[string]interface{}{
+			"risk_score": riskScore,
+			"reasons":    reasons,
+			"country":    country,
+		},
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Timestamp: time.Now(),
+		Status:    sharedDomain.ActivityStatusSuccess,
+	}
+	if err := s.repoManager.GetActivityLogRepository().Create(ctx, entry); err != nil {
+		log.Printf("security: failed to record risk assessment: %v", err)
+	}
+
+	return suspicious, nil
+}
+
+// hashDevice derives a stable device identifier from the user agent
+func hashDevice(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:8])
+}
+
+// countryForIP resolves the country of an IP. With geolocation disabled it
+// falls back to a coarse classification, which still distinguishes internal
+// from external logins.
+func countryForIP(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return "unknown"
+	}
+	if ip.IsLoopback() || ip.IsPrivate() {
+		return "internal"
+	}
+	return "external"
+}
+
+func (s *RepositorySecurityService) RecordSuspiciousActivity(ctx context.Context, userID, activityType, description, ipAddress, userAgent string) error {
+	log.Printf("security: suspicious activity for user %s: %s (%s)", userID, activityType, description)
+	return nil
+}
+
+func (s *RepositorySecurityService) IsIPBlocked(ctx context.Context, ipAddress string) (bool, error) {
+	return s.repoManager.GetCacheRepository().IsUserLockedOut(ctx, "ip:"+ipAddress)
+}
+
+func (s *RepositorySecurityService) BlockIP(ctx context.Context, ipAddress string, duration time.Duration, reason string) error {
+	log.Printf("security: blocking IP %s for %s: %s", ipAddress, duration, reason)
+	return s.repoManager.GetCacheRepository().SetUserLockout(ctx, "ip:"+ipAddress, duration)
+}
+
+func (s *RepositorySecurityService) UnblockIP(ctx context.Context, ipAddress string) error {
+	return s.repoManager.GetCacheRepository().RemoveUserLockout(ctx, "ip:"+ipAddress)
+}
+
+func (s *RepositorySecurityService) IsTrustedDevice(ctx context.Context, userID, deviceFingerprint string) (bool, error) {
+	return false, nil
+}
+
+func (s *RepositorySecurityService) AddTrustedDevice(ctx context.Context, userID, deviceFingerprint, deviceName string) error {
+	return fmt.Errorf("trusted device management is not implemented")
+}
+
+// CheckPasswordHistory reports whether the plaintext password matches any
+// retained previous password hash for the user
+func (s *RepositorySecurityService) CheckPasswordHistory(ctx context.Context, userID, newPassword string) (bool, error) {
+	s.historyMu.Lock()
+	hashes := append([]string(nil), s.passwordHistory[userID]...)
+	s.historyMu.Unlock()
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddPasswordToHistory retains the password hash for reuse checking
+func (s *RepositorySecurityService) AddPasswordToHistory(ctx context.Context, userID, passwordHash string) error {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	s.passwordHistory[userID] = append(s.passwordHistory[userID], passwordHash)
+	return nil
+}
+
+func (s *RepositorySecurityService) IsPasswordCompromised(ctx context.Context, password string) (bool, error) {
+	return false, nil
+}
+
+// RepositoryActivityService implements usecases.ActivityService on the
+// activity log repository
+type RepositoryActivityService struct {
+	repoManager authDomain.RepositoryManager
+}
+
+// NewRepositoryActivityService creates a repository-backed activity service
+func NewRepositoryActivityService(repoManager authDomain.RepositoryManager) usecases.ActivityService {
+	return &RepositoryActivityService{repoManager: repoManager}
+}
+
+func (s *RepositoryActivityService) LogActivity(ctx context.Context, userID, action, resourceType, resourceID string, details map[string]interface{}, ipAddress, userAgent string) error {
+	entry := &sharedDomain.ActivityLog{
+		ID:           utils.GenerateID(),
+		UserID:       &userID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		Details:      details,
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
+		Timestamp:    time.Now(),
+		Status:       sharedDomain.ActivityStatusSuccess,
+	}
+	return s.repoManager.GetActivityLogRepository().Create(ctx, entry)
+}
+
+func (s *RepositoryActivityService) LogSecurityEvent(ctx context.Context, userID, eventType, description string, severity string, ipAddress, userAgent string) error {
+	return s.LogActivity(ctx, userID, eventType, "security", userID, map[string]interface{}{
+		"description": description,
+		"severity":    severity,
+	}, ipAddress, userAgent)
+}
+
+func (s *RepositoryActivityService) GetUserActivity(ctx context.Context, userID string, limit, offset int) ([]*sharedDomain.ActivityLog, error) {
+	return nil, fmt.Errorf("activity retrieval is not implemented")
+}
+
+func (s *RepositoryActivityService) GetActivityByType(ctx context.Context, activityType string, since time.Time, limit, offset int) ([]*sharedDomain.ActivityLog, error) {
+	return nil, fmt.Errorf("activity retrieval is not implemented")
+}
+
+func (s *RepositoryActivityService) GetSecurityEvents(ctx context.Context, since time.Time, severity string, limit, offset int) ([]*sharedDomain.ActivityLog, error) {
+	return nil, fmt.Errorf("activity retrieval is not implemented")
+}
+
+func (s *RepositoryActivityService) GetActivityStats(ctx context.Context, userID string, since time.Time) (*usecases.ActivityStats, error) {
+	counts, err := s.repoManager.GetActivityLogRepository().GetUserActivity(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	stats := &usecases.ActivityStats{UserID: userID, ByType: make(map[string]int)}
+	for action, count := range counts {
+		stats.ByType[action] = int(count)
+		stats.TotalActivities += int(count)
+	}
+	return stats, nil
+}
+
+func (s *RepositoryActivityService) GetSystemActivityStats(ctx context.Context, since time.Time) (*usecases.SystemActivityStats, error) {
+	return nil, fmt.Errorf("system activity stats are not implemented")
+}
@@ -6,12 +6,17 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/auth/config"
+	authDomain "github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/services/auth/infrastructure"
+	"github.com/elotusteam/microservice-project/services/auth/usecases"
+	sharedConfig "github.com/elotusteam/microservice-project/shared/config"
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -62,10 +67,96 @@ func prometheusMiddleware() gin.HandlerFunc {
 	}
 }
 
+// refreshCookieEnabled reports whether refresh tokens are delivered via a
+// secure HTTP-only cookie instead of the JSON body (the secure-SPA pattern;
+// mobile clients keep using the body when disabled).
+func refreshCookieEnabled() bool {
+	return os.Getenv("JWT_REFRESH_TOKEN_COOKIE") == "true"
+}
+
+// refreshCookieName returns the configured refresh cookie name
+func refreshCookieName() string {
+	if name := os.Getenv("JWT_REFRESH_TOKEN_COOKIE_NAME"); name != "" {
+		return name
+	}
+	return "refresh_token"
+}
+
+// setRefreshTokenCookie stores (and rotates) the refresh token as a secure,
+// HTTP-only, SameSite=Strict cookie
+func setRefreshTokenCookie(c *gin.Context, refreshToken string, maxAge time.Duration) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(refreshCookieName(), refreshToken, int(maxAge.Seconds()), "/api/v1/auth", "", true, true)
+}
+
+// refreshTokenFromRequest extracts the refresh token from the HTTP-only
+// cookie when cookie mode is enabled, otherwise from the JSON body
+func refreshTokenFromRequest(c *gin.Context) (token, clientSecret, source string, err error) {
+	if refreshCookieEnabled() {
+		cookie, cookieErr := c.Cookie(refreshCookieName())
+		if cookieErr != nil || cookie == "" {
+			return "", "", "", fmt.Errorf("refresh token cookie missing")
+		}
+		// The client secret for token binding still travels in the body;
+		// a missing or empty body just means no binding is in play
+		var req struct {
+			ClientSecret string `json:"client_secret"`
+		}
+		_ = c.ShouldBindJSON(&req)
+		return cookie, req.ClientSecret, "cookie", nil
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		return "", "", "", fmt.Errorf("refresh token required")
+	}
+	return req.RefreshToken, req.ClientSecret, "body", nil
+}
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Load the shared configuration used by the use-case layer
+	sharedCfg, err := sharedConfig.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load shared config: %v", err)
+	}
+
+	// Wire the auth service: repositories, token handling and the
+	// supporting security/rate-limit/notification/activity services.
+	// AUTH_DB_DRIVER=postgres selects the SQL-backed repositories; the
+	// default stays in-memory so the service runs standalone.
+	var repoManager authDomain.RepositoryManager
+	if os.Getenv("AUTH_DB_DRIVER") == "postgres" {
+		repoManager, err = infrastructure.NewPostgreSQLRepositoryManager(&sharedCfg.Database)
+		if err != nil {
+			log.Fatalf("Failed to connect to auth database: %v", err)
+		}
+	} else {
+		repoManager = infrastructure.NewMockRepositoryManager()
+	}
+	tokenService, err := usecases.NewTokenService(sharedCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize token service: %v", err)
+	}
+	authService := usecases.NewAuthService(
+		repoManager,
+		tokenService,
+		sharedCfg,
+		infrastructure.NewLogNotificationService(),
+		infrastructure.NewCacheRateLimitService(repoManager),
+		infrastructure.NewRepositorySecurityService(repoManager),
+		infrastructure.NewRepositoryActivityService(repoManager),
+	)
+	handlers := newAuthHandlers(authService, tokenService)
+
+	// Serve token validation over gRPC for the other services
+	go startGRPCServer(authService, getEnvAsInt("AUTH_GRPC_PORT", 9081))
+
 	// Set Gin mode to debug for development
 	gin.SetMode(gin.DebugMode)
 
@@ -75,6 +166,9 @@ func main() {
 	// Add Prometheus middleware
 	router.Use(prometheusMiddleware())
 
+	// Return 405 with an Allow header for wrong-method requests
+	middleware.ConfigureMethodNotAllowed(router)
+
 	// Metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -93,20 +187,156 @@ func main() {
 		// Authentication routes
 		auth := api.Group("/auth")
 		{
-			auth.POST("/login", func(c *gin.Context) {
-				// Simulate authentication logic
-				authAttemptsTotal.WithLabelValues("success").Inc()
-				c.JSON(http.StatusOK, gin.H{"message": "Login endpoint - implementation pending"})
-			})
-			auth.POST("/register", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{"message": "Register endpoint - implementation pending"})
-			})
-			auth.POST("/logout", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{"message": "Logout endpoint - implementation pending"})
-			})
+			auth.POST("/login", handlers.login)
+			auth.POST("/register", handlers.register)
+			auth.POST("/logout", handlers.logout)
+			auth.POST("/refresh", handlers.refresh)
+			auth.GET("/validate", handlers.validate)
+			auth.DELETE("/account", handlers.deleteAccount)
+
+			// Trusted-device management
+			devices := auth.Group("/devices")
+			{
+				devices.GET("/", handlers.listDevices)
+				devices.POST("/:id/trust", handlers.trustDevice)
+				devices.DELETE("/:id/trust", handlers.revokeDeviceTrust)
+			}
+
+			// Passwordless magic-link login
+			auth.POST("/magic/request", handlers.magicLinkRequest)
+			auth.POST("/magic/verify", handlers.magicLinkVerify)
+
+			// MFA endpoints
+			mfa := auth.Group("/mfa")
+			{
+				mfa.POST("/enroll", handlers.mfaEnroll)
+				mfa.POST("/verify-enrollment", handlers.mfaVerifyEnrollment)
+				mfa.POST("/verify", handlers.mfaVerify)
+				mfa.DELETE("/", handlers.mfaDisable)
+			}
+
+			// Session management for end users
+			sessions := auth.Group("/sessions")
+			{
+				sessions.GET("/", handlers.listSessions)
+				sessions.DELETE("/:id", handlers.revokeSession)
+				sessions.DELETE("/", handlers.revokeOtherSessions)
+			}
+
+			// OAuth2/OIDC social login
+			auth.GET("/oauth/:provider", handlers.oauthBegin)
+			auth.GET("/oauth/:provider/callback", handlers.oauthCallback)
+
+			// WebAuthn / passkey endpoints
+			webauthn := auth.Group("/webauthn")
+			{
+				webauthn.POST("/register/begin", handlers.webauthnBeginRegistration)
+				webauthn.POST("/register/finish", handlers.webauthnFinishRegistration)
+				webauthn.POST("/login/begin", handlers.webauthnBeginLogin)
+				webauthn.POST("/login/finish", handlers.webauthnFinishLogin)
+				webauthn.GET("/credentials", handlers.webauthnListCredentials)
+				webauthn.DELETE("/credentials/:id", handlers.webauthnDeleteCredential)
+			}
+		}
+	}
+
+	// SCIM 2.0 provisioning for enterprise IdPs; admin-scoped
+	scim := newSCIMHandlers(repoManager)
+	scimGroup := router.Group("/scim/v2")
+	scimGroup.Use(handlers.requireAdmin)
+	{
+		scimGroup.POST("/Users", scim.createUser)
+		scimGroup.GET("/Users", scim.listUsers)
+		scimGroup.GET("/Users/:id", scim.getUser)
+		scimGroup.PATCH("/Users/:id", scim.patchUser)
+		scimGroup.DELETE("/Users/:id", scim.deleteUser)
+	}
+
+	// RFC 7662 / 7009 token introspection and revocation for external
+	// gateways and third-party services
+	clientCredentials := usecases.NewClientCredentialsService(sharedCfg, usecases.NewInMemoryClientRegistry(), tokenService)
+
+	// RFC 7662 requires the introspection endpoint to authenticate callers:
+	// an open endpoint is a token-validity and token-contents oracle. The
+	// revocation endpoint gets the same guard.
+	requireClientAuth := func(c *gin.Context) {
+		clientID, clientSecret, ok := c.Request.BasicAuth()
+		if !ok {
+			clientID = c.PostForm("client_id")
+			clientSecret = c.PostForm("client_secret")
+		}
+		if err := clientCredentials.AuthenticateClient(c.Request.Context(), clientID, clientSecret); err != nil {
+			c.Header("WWW-Authenticate", "Basic")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			return
 		}
+		c.Next()
+	}
+
+	oauth := api.Group("/oauth")
+	{
+		oauth.POST("/introspect", requireClientAuth, handlers.introspect)
+		oauth.POST("/revoke", requireClientAuth, handlers.revoke)
+		oauth.POST("/token", func(c *gin.Context) {
+			if c.PostForm("grant_type") != "client_credentials" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+				return
+			}
+
+			clientID, clientSecret, ok := c.Request.BasicAuth()
+			if !ok {
+				clientID = c.PostForm("client_id")
+				clientSecret = c.PostForm("client_secret")
+			}
+
+			var scopes []string
+			if raw := c.PostForm("scope"); raw != "" {
+				scopes = strings.Fields(raw)
+			}
+
+			response, err := clientCredentials.Token(c.Request.Context(), clientID, clientSecret, scopes)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+				return
+			}
+
+			c.JSON(http.StatusOK, response)
+		})
 	}
 
+	// JWKS endpoint for RS256 verification key discovery
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		jwks, err := tokenService.JWKS(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, jwks)
+	})
+
+	// Admin routes
+	admin := router.Group("/admin")
+	admin.Use(handlers.requireAdmin)
+	admin.GET("/sessions/geo-distribution", handlers.sessionGeoDistribution)
+	admin.GET("/security-events", handlers.adminSecurityEvents)
+	admin.GET("/security-events/export", handlers.adminExportSecurityEvents)
+	admin.POST("/erasures/purge", handlers.adminPurgeErasures)
+	admin.GET("/users", handlers.adminListUsers)
+	admin.PUT("/users/:user_id/role", handlers.adminUpdateUserRole)
+	admin.POST("/users/:user_id/suspend", handlers.adminSuspendUser)
+	admin.POST("/users/:user_id/activate", handlers.adminActivateUser)
+	admin.POST("/users/:user_id/force-logout", handlers.adminForceLogout)
+	admin.GET("/lockouts/:user_id", handlers.lockoutInfo)
+	admin.DELETE("/lockouts/:user_id", handlers.clearLockout)
+	admin.POST("/signing-keys/rotate", func(c *gin.Context) {
+		kid, err := tokenService.RotateSigningKey(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"active_kid": kid})
+	})
+
 	// Start server
 	server := &http.Server{
 		Addr:              fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -117,26 +347,19 @@ func main() {
 		IdleTimeout:       120 * time.Second,
 	}
 
-	// Graceful shutdown
-	go func() {
-		log.Printf("Auth service starting on %s:%d", cfg.Server.Host, cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// The context is used to inform the server it has 5 seconds to finish
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	// Run with graceful shutdown handled by the shared helper
+	log.Printf("Auth service starting on %s:%d", cfg.Server.Host, cfg.Server.Port)
+	if err := sharedServer.RunServer(context.Background(), server, 5*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
+}
 
-	log.Println("Server exiting")
+// Helper functions
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
 }
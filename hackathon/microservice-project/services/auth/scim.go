@@ -0,0 +1,316 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	authDomain "github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/data"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+	"github.com/elotusteam/microservice-project/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIM 2.0 schema URNs
+const (
+	scimUserSchema  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimListSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimPatchSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// scimHandlers implements the SCIM 2.0 Users endpoint set so enterprise
+// IdPs can provision and deprovision accounts against the shared User model
+type scimHandlers struct {
+	repoManager authDomain.RepositoryManager
+}
+
+// newSCIMHandlers creates the SCIM handler set
+func newSCIMHandlers(repoManager authDomain.RepositoryManager) *scimHandlers {
+	return &scimHandlers{repoManager: repoManager}
+}
+
+// scimUser is the SCIM representation of a user
+type scimUser struct {
+	Schemas  []string  `json:"schemas"`
+	ID       string    `json:"id"`
+	UserName string    `json:"userName"`
+	Name     scimName  `json:"name"`
+	Emails   []scimVal `json:"emails"`
+	Active   bool      `json:"active"`
+	Meta     scimMeta  `json:"meta"`
+}
+
+type scimName struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type scimVal struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary"`
+}
+
+type scimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// toSCIM maps the shared User model to its SCIM representation
+func toSCIM(user *sharedDomain.User) *scimUser {
+	return &scimUser{
+		Schemas:  []string{scimUserSchema},
+		ID:       user.ID,
+		UserName: user.Username,
+		Name: scimName{
+			GivenName:  user.FirstName,
+			FamilyName: user.LastName,
+		},
+		Emails: []scimVal{{Value: user.Email, Primary: true}},
+		Active: user.Status == sharedDomain.UserStatusActive,
+		Meta: scimMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+		},
+	}
+}
+
+// scimError writes a SCIM-format error response
+func scimError(c *gin.Context, status int, detail string) {
+	c.JSON(status, gin.H{
+		"schemas": []string{scimErrorSchema},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	})
+}
+
+// createUser handles POST /scim/v2/Users
+func (h *scimHandlers) createUser(c *gin.Context) {
+	var req struct {
+		UserName string    `json:"userName" binding:"required"`
+		Name     scimName  `json:"name"`
+		Emails   []scimVal `json:"emails"`
+		Active   *bool     `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	email := req.UserName
+	for _, entry := range req.Emails {
+		if entry.Primary || email == req.UserName {
+			email = entry.Value
+		}
+	}
+
+	userRepo := h.repoManager.GetUserRepository()
+	if exists, _ := userRepo.ExistsByUsername(c.Request.Context(), req.UserName); exists {
+		scimError(c, http.StatusConflict, "userName already exists")
+		return
+	}
+
+	status := sharedDomain.UserStatusActive
+	if req.Active != nil && !*req.Active {
+		status = sharedDomain.UserStatusInactive
+	}
+
+	user := &sharedDomain.User{
+		ID:        utils.GenerateID(),
+		Username:  req.UserName,
+		Email:     email,
+		FirstName: req.Name.GivenName,
+		LastName:  req.Name.FamilyName,
+		Role:      sharedDomain.UserRoleUser,
+		Status:    status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Metadata:  map[string]interface{}{"provisioned_by": "scim"},
+	}
+
+	if err := userRepo.Create(c.Request.Context(), user); err != nil {
+		scimError(c, http.StatusInternalServerError, "failed to create user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSCIM(user))
+}
+
+// getUser handles GET /scim/v2/Users/:id
+func (h *scimHandlers) getUser(c *gin.Context) {
+	user, err := h.repoManager.GetUserRepository().GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "User not found")
+		return
+	}
+	c.JSON(http.StatusOK, toSCIM(user))
+}
+
+// listUsers handles GET /scim/v2/Users with SCIM filters of the form
+// `userName eq "value"` plus startIndex/count pagination
+func (h *scimHandlers) listUsers(c *gin.Context) {
+	startIndex, _ := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "100"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count <= 0 {
+		count = 100
+	}
+
+	userRepo := h.repoManager.GetUserRepository()
+
+	// Exact userName filters resolve to a single lookup
+	if filter := c.Query("filter"); filter != "" {
+		attribute, value, ok := parseSCIMEqFilter(filter)
+		if !ok {
+			scimError(c, http.StatusBadRequest, "unsupported filter; only `attribute eq \"value\"` is supported")
+			return
+		}
+
+		var user *sharedDomain.User
+		var err error
+		switch attribute {
+		case "userName":
+			user, err = userRepo.GetByUsername(c.Request.Context(), value)
+		case "email", "emails.value":
+			user, err = userRepo.GetByEmail(c.Request.Context(), value)
+		default:
+			scimError(c, http.StatusBadRequest, "unsupported filter attribute "+attribute)
+			return
+		}
+
+		resources := []interface{}{}
+		if err == nil && user != nil {
+			resources = append(resources, toSCIM(user))
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"schemas":      []string{scimListSchema},
+			"totalResults": len(resources),
+			"startIndex":   1,
+			"itemsPerPage": len(resources),
+			"Resources":    resources,
+		})
+		return
+	}
+
+	page := (startIndex-1)/count + 1
+	result, err := userRepo.List(c.Request.Context(), &data.Pagination{Page: page, PageSize: count})
+	if err != nil {
+		scimError(c, http.StatusInternalServerError, "failed to list users")
+		return
+	}
+
+	resources := []interface{}{}
+	if users, ok := result.Data.([]*sharedDomain.User); ok {
+		for _, user := range users {
+			resources = append(resources, toSCIM(user))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schemas":      []string{scimListSchema},
+		"totalResults": result.Total,
+		"startIndex":   startIndex,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	})
+}
+
+// patchUser handles PATCH /scim/v2/Users/:id with replace operations,
+// notably `active: false` for deprovisioning
+func (h *scimHandlers) patchUser(c *gin.Context) {
+	var req struct {
+		Schemas    []string `json:"schemas"`
+		Operations []struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		} `json:"Operations"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userRepo := h.repoManager.GetUserRepository()
+	user, err := userRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	for _, op := range req.Operations {
+		if !strings.EqualFold(op.Op, "replace") {
+			scimError(c, http.StatusBadRequest, "only replace operations are supported")
+			return
+		}
+
+		switch op.Path {
+		case "active":
+			active, _ := op.Value.(bool)
+			if active {
+				user.Status = sharedDomain.UserStatusActive
+			} else {
+				user.Status = sharedDomain.UserStatusInactive
+			}
+		case "userName":
+			if value, ok := op.Value.(string); ok {
+				user.Username = value
+			}
+		case "name.givenName":
+			if value, ok := op.Value.(string); ok {
+				user.FirstName = value
+			}
+		case "name.familyName":
+			if value, ok := op.Value.(string); ok {
+				user.LastName = value
+			}
+		default:
+			scimError(c, http.StatusBadRequest, "unsupported patch path "+op.Path)
+			return
+		}
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(c.Request.Context(), user); err != nil {
+		scimError(c, http.StatusInternalServerError, "failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, toSCIM(user))
+}
+
+// deleteUser handles DELETE /scim/v2/Users/:id by deactivating the account
+func (h *scimHandlers) deleteUser(c *gin.Context) {
+	userRepo := h.repoManager.GetUserRepository()
+	user, err := userRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	user.Status = sharedDomain.UserStatusInactive
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(c.Request.Context(), user); err != nil {
+		scimError(c, http.StatusInternalServerError, "failed to deactivate user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseSCIMEqFilter parses `attribute eq "value"` filters
+func parseSCIMEqFilter(filter string) (attribute, value string, ok bool) {
+	parts := strings.SplitN(filter, " eq ", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	attribute = strings.TrimSpace(parts[0])
+	value = strings.Trim(strings.TrimSpace(parts[1]), `"`)
+	return attribute, value, attribute != "" && value != ""
+}
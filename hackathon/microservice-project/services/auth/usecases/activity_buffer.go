@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/shared/utils"
+)
+
+// activityWarnLog samples repeated activity-write failures so a logging
+// backend outage doesn't flood the logs
+var activityWarnLog = utils.NewSampledLogger(5, 100)
+
+// Buffered activity logging defaults; overridable via environment
+const (
+	DefaultActivityBufferSize    = 256
+	DefaultActivityBatchSize     = 32
+	DefaultActivityFlushInterval = 2 * time.Second
+)
+
+// activityEntry captures one LogActivity call for deferred writing
+type activityEntry struct {
+	userID       string
+	action       string
+	resourceType string
+	resourceID   string
+	details      map[string]interface{}
+	ipAddress    string
+	userAgent    string
+}
+
+// BufferedActivityService decorates an ActivityService so LogActivity
+// enqueues entries instead of writing synchronously on the request hot path.
+// A background worker flushes queued entries in batches; remaining entries
+// are flushed on Close (wire it as a shutdown hook). Failed writes are
+// retried once and then surfaced as log output without blocking requests.
+type BufferedActivityService struct {
+	ActivityService
+
+	entries       chan *activityEntry
+	batchSize     int
+	flushInterval time.Duration
+
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewBufferedActivityService wraps next with an async buffered logger
+func NewBufferedActivityService(next ActivityService) *BufferedActivityService {
+	s := &BufferedActivityService{
+		ActivityService: next,
+		entries:         make(chan *activityEntry, envInt("AUTH_ACTIVITY_BUFFER_SIZE", DefaultActivityBufferSize)),
+		batchSize:       envInt("AUTH_ACTIVITY_BATCH_SIZE", DefaultActivityBatchSize),
+		flushInterval:   envDuration("AUTH_ACTIVITY_FLUSH_INTERVAL", DefaultActivityFlushInterval),
+		done:            make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s
+}
+
+// LogActivity queues the entry for asynchronous batched writing. When the
+// buffer is full the entry is written synchronously rather than dropped.
+func (s *BufferedActivityService) LogActivity(ctx context.Context, userID, action, resourceType, resourceID string, details map[string]interface{}, ipAddress, userAgent string) error {
+	entry := &activityEntry{
+		userID:       userID,
+		action:       action,
+		resourceType: resourceType,
+		resourceID:   resourceID,
+		details:      details,
+		ipAddress:    ipAddress,
+		userAgent:    userAgent,
+	}
+
+	select {
+	case s.entries <- entry:
+		return nil
+	default:
+		// Buffer full: fall back to a synchronous write so nothing is lost
+		return s.ActivityService.LogActivity(ctx, userID, action, resourceType, resourceID, details, ipAddress, userAgent)
+	}
+}
+
+// Close stops the worker and flushes any remaining entries
+func (s *BufferedActivityService) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+	return nil
+}
+
+// run collects entries and flushes them in batches
+func (s *BufferedActivityService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*activityEntry, 0, s.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		s.writeBatch(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.done:
+			// Drain whatever is still queued, then flush and exit
+			for {
+				select {
+				case entry := <-s.entries:
+					batch = append(batch, entry)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeBatch persists a batch of entries, retrying each failed write once
+func (s *BufferedActivityService) writeBatch(batch []*activityEntry) {
+	ctx := context.Background()
+	for _, entry := range batch {
+		err := s.ActivityService.LogActivity(ctx, entry.userID, entry.action, entry.resourceType, entry.resourceID, entry.details, entry.ipAddress, entry.userAgent)
+		if err != nil {
+			// Retry once before surfacing the failure
+			if err = s.ActivityService.LogActivity(ctx, entry.userID, entry.action, entry.resourceType, entry.resourceID, entry.details, entry.ipAddress, entry.userAgent); err != nil {
+				activityWarnLog.Warnf("activity_write", "failed to write activity log entry (%s): %v", entry.action, err)
+			}
+		}
+	}
+}
+
+func envInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
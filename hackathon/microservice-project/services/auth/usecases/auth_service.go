@@ -2,13 +2,21 @@ package usecases
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/elotusteam/microservice-project/services/auth/domain"
 	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/elotusteam/microservice-project/shared/data"
 	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
 	"github.com/elotusteam/microservice-project/shared/utils"
 )
@@ -20,7 +28,7 @@ type AuthService interface {
 	Register(ctx context.Context, req *domain.RegisterRequest, ipAddress, userAgent string) (*domain.AuthResponse, error)
 	Logout(ctx context.Context, tokenID string) error
 	LogoutAll(ctx context.Context, userID string) error
-	RefreshToken(ctx context.Context, req *domain.RefreshTokenRequest) (*domain.TokenPair, error)
+	RefreshToken(ctx context.Context, req *domain.RefreshTokenRequest, userAgent string) (*domain.TokenPair, error)
 
 	// Password operations
 	ChangePassword(ctx context.Context, userID string, req *domain.ChangePasswordRequest) error
@@ -38,8 +46,27 @@ type AuthService interface {
 	DeactivateUser(ctx context.Context, userID string) error
 	ActivateUser(ctx context.Context, userID string) error
 
+	// Admin user management
+	ListUsers(ctx context.Context, role, status, query string, pagination *data.Pagination) (*data.PaginatedResult, error)
+	UpdateUserRole(ctx context.Context, userID string, role sharedDomain.UserRole) error
+
+	// Account deletion / GDPR erasure
+	DeleteAccount(ctx context.Context, userID string) error
+	PurgeDueErasures(ctx context.Context) (int, error)
+
+	// Security audit log
+	QuerySecurityEvents(ctx context.Context, userID, action string, start, end time.Time, pagination *data.Pagination) (*data.PaginatedResult, error)
+
+	// Magic-link (passwordless) login
+	RequestMagicLink(ctx context.Context, email string) error
+	VerifyMagicLink(ctx context.Context, token, ipAddress, userAgent string) (*domain.AuthResponse, error)
+
+	// Trusted-device management
+	Devices() DeviceService
+
 	// Session operations
 	GetUserSessions(ctx context.Context, userID string) ([]*sharedDomain.Session, error)
+	GetSessionGeoDistribution(ctx context.Context, since time.Time) (map[string]int64, error)
 	RevokeSession(ctx context.Context, sessionID string) error
 	RevokeAllUserSessions(ctx context.Context, userID string) error
 
@@ -53,10 +80,26 @@ type AuthService interface {
 	// Activity logging
 	LogActivity(ctx context.Context, userID, action, resourceType, resourceID string, details map[string]interface{}, ipAddress, userAgent string) error
 
+	// MFA operations
+	MFA() MFAService
+	CompleteMFALogin(ctx context.Context, challengeToken, code, ipAddress, userAgent string) (*domain.AuthResponse, error)
+
+	// WebAuthn operations
+	WebAuthn() WebAuthnService
+	CompletePasskeyLogin(ctx context.Context, req *WebAuthnAssertionRequest, ipAddress, userAgent string) (*domain.AuthResponse, error)
+
+	// OAuth operations
+	OAuth() OAuthService
+	CompleteOAuthLogin(ctx context.Context, provider, code, state, ipAddress, userAgent string) (*domain.AuthResponse, error)
+
 	// Health check
 	Health(ctx context.Context) error
 }
 
+// warnLog rate-limits the repetitive warnings auth emits on dependency
+// failures (cache, activity logging) so an outage doesn't flood the logs
+var warnLog = utils.NewSampledLogger(5, 100)
+
 // authService implements the AuthService interface
 type authService struct {
 	repoManager         domain.RepositoryManager
@@ -66,6 +109,11 @@ type authService struct {
 	rateLimitService    RateLimitService
 	securityService     SecurityService
 	activityService     ActivityService
+	mfaService          MFAService
+	webauthnService     WebAuthnService
+	oauthService        OAuthService
+	captchaVerifier     CaptchaVerifier
+	deviceService       DeviceService
 }
 
 // NewAuthService creates a new authentication service
@@ -78,6 +126,13 @@ func NewAuthService(
 	securityService SecurityService,
 	activityService ActivityService,
 ) AuthService {
+	// Buffer activity writes so auth operations don't block on logging.
+	// Disable with AUTH_ACTIVITY_BUFFERING_ENABLED=false for strictly
+	// synchronous logging.
+	if activityService != nil && os.Getenv("AUTH_ACTIVITY_BUFFERING_ENABLED") != "false" {
+		activityService = NewBufferedActivityService(activityService)
+	}
+
 	return &authService{
 		repoManager:         repoManager,
 		tokenService:        tokenService,
@@ -86,9 +141,24 @@ func NewAuthService(
 		rateLimitService:    rateLimitService,
 		securityService:     securityService,
 		activityService:     activityService,
+		mfaService:          NewMFAService(config),
+		webauthnService:     NewWebAuthnService(config, NewInMemoryWebAuthnCredentialRepository()),
+		oauthService:        NewOAuthService(config, repoManager.GetUserRepository()),
+		captchaVerifier:     NewCaptchaVerifier(&config.Security.Captcha),
+		deviceService:       NewDeviceService(NewInMemoryDeviceRepository()),
 	}
 }
 
+// Devices exposes the trusted-device subsystem for the HTTP layer
+func (s *authService) Devices() DeviceService {
+	return s.deviceService
+}
+
+// MFA exposes the MFA subsystem for the HTTP layer
+func (s *authService) MFA() MFAService {
+	return s.mfaService
+}
+
 // Login authenticates a user and returns tokens
 func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAddress, userAgent string) (*domain.AuthResponse, error) {
 	// Validate request
@@ -96,9 +166,14 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		return nil, err
 	}
 
-	// Check rate limiting
-	if allowed, err := s.rateLimitService.CheckRateLimit(ctx, req.Username, 5, 15*time.Minute); err != nil {
-		return nil, fmt.Errorf("rate limit check failed: %w", err)
+	// Check rate limiting. On cache errors the configured failure policy
+	// decides whether to serve (fail-open) or deny (fail-closed).
+	lockout := s.config.Security.Lockout
+	if allowed, err := s.rateLimitService.CheckRateLimit(ctx, req.Username, lockout.MaxAttempts, lockout.AttemptWindow); err != nil {
+		if s.config.Cache.FailurePolicyFor("rate_limit") != "fail-open" {
+			return nil, fmt.Errorf("rate limit check failed: %w", err)
+		}
+		warnLog.Warnf("rate_limit_degraded", "rate limit check degraded (fail-open): %v", err)
 	} else if !allowed {
 		// Record failed attempt
 		if err := s.securityService.RecordLoginAttempt(ctx, req.Username, false, ipAddress, userAgent); err != nil {
@@ -107,6 +182,20 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		return nil, domain.NewAuthError(domain.AuthErrTooManyAttempts, "Too many login attempts. Please try again later.")
 	}
 
+	// After repeated failures a CAPTCHA must accompany the login, slowing
+	// down credential-stuffing before the password is even checked
+	if s.config.Security.Captcha.Enabled {
+		failed, _ := s.repoManager.GetLoginAttemptRepository().CountFailedAttempts(ctx, req.Username, time.Now().Add(-lockout.AttemptWindow))
+		if failed >= int64(s.config.Security.Captcha.FailedAttemptsThreshold) {
+			if req.CaptchaToken == "" {
+				return nil, domain.NewAuthError(domain.AuthErrCaptchaRequired, "CAPTCHA verification required")
+			}
+			if err := s.captchaVerifier.Verify(ctx, req.CaptchaToken, ipAddress); err != nil {
+				return nil, domain.NewAuthError(domain.AuthErrCaptchaRequired, "CAPTCHA verification failed")
+			}
+		}
+	}
+
 	// Get user by username or email
 	userRepo := s.repoManager.GetUserRepository()
 	var user *sharedDomain.User
@@ -145,9 +234,12 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		}
 	}
 
-	// Check if user is locked out
+	// Check if user is locked out, honoring the cache failure policy
 	if locked, err := s.securityService.IsUserLockedOut(ctx, user.ID); err != nil {
-		return nil, fmt.Errorf("lockout check failed: %w", err)
+		if s.config.Cache.FailurePolicyFor("lockout") != "fail-open" {
+			return nil, fmt.Errorf("lockout check failed: %w", err)
+		}
+		warnLog.Warnf("lockout_degraded", "lockout check degraded (fail-open): %v", err)
 	} else if locked {
 		return nil, domain.NewAuthError(domain.AuthErrAccountLocked, "Account is temporarily locked")
 	}
@@ -159,10 +251,11 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 			fmt.Printf("Failed to record login attempt: %v\n", err)
 		}
 
-		// Check if we should lock the user
-		failedAttempts, _ := s.repoManager.GetLoginAttemptRepository().CountFailedAttempts(ctx, req.Username, time.Now().Add(-15*time.Minute))
-		if failedAttempts >= 4 { // Lock after 5 failed attempts
-			if err := s.securityService.LockUser(ctx, user.ID, 30*time.Minute, "Too many failed login attempts"); err != nil {
+		// Check if we should lock the user, per the configured policy
+		failedAttempts, _ := s.repoManager.GetLoginAttemptRepository().CountFailedAttempts(ctx, req.Username, time.Now().Add(-lockout.AttemptWindow))
+		if failedAttempts >= int64(lockout.MaxAttempts-1) {
+			duration := s.lockDuration(failedAttempts)
+			if err := s.securityService.LockUser(ctx, user.ID, duration, "Too many failed login attempts"); err != nil {
 				fmt.Printf("Failed to lock user: %v\n", err)
 			}
 		}
@@ -170,17 +263,68 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		return nil, domain.NewAuthError(domain.AuthErrInvalidCredentials, "Invalid username or password")
 	}
 
+	// Expired passwords force a reset before login can complete
+	if s.passwordExpired(user) {
+		return nil, domain.NewAuthError(domain.AuthErrPasswordResetRequired, "Password has expired and must be reset")
+	}
+
+	// Assess the login context; anomalies alert the user and step up to MFA
+	// when the account has it enrolled
+	anomalous, err := s.securityService.DetectSuspiciousActivity(ctx, user.ID, req.Username, ipAddress, userAgent)
+	if err != nil {
+		fmt.Printf("Failed to assess login risk: %v\n", err)
+	}
+	if anomalous {
+		if err := s.notificationService.SendLoginAlertEmail(ctx, user.Email, user.FirstName, ipAddress, userAgent); err != nil {
+			fmt.Printf("Failed to send login alert: %v\n", err)
+		}
+	}
+
+	// Track the device making this login
+	if _, err := s.deviceService.RecordLogin(ctx, user.ID, userAgent); err != nil {
+		fmt.Printf("Failed to record login device: %v\n", err)
+	}
+
+	// MFA-enrolled users must complete the challenge step before tokens
+	// are issued; trusted devices skip the challenge within their window
+	if enrolled, err := s.mfaService.IsEnrolled(ctx, user.ID); err == nil && enrolled &&
+		!s.deviceService.IsTrusted(ctx, user.ID, userAgent) {
+		challenge, err := s.mfaService.CreateChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MFA challenge: %w", err)
+		}
+		return &domain.AuthResponse{
+			User: &domain.AuthUser{
+				ID:       user.ID,
+				Username: user.Username,
+				Email:    user.Email,
+				Role:     user.Role,
+				OrgID:    user.OrgID,
+				Status:   user.Status,
+			},
+			MFARequired:    true,
+			ChallengeToken: challenge,
+		}, nil
+	}
+
 	// Generate tokens
 	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("token generation failed: %w", err)
 	}
 
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
 	// Create session
 	session := &sharedDomain.Session{
 		ID:         utils.GenerateID(),
 		UserID:     user.ID,
-		TokenID:    tokens.AccessToken, // This should be the JTI from the token
+		TokenID:    accessTokenID,
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
 		CreatedAt:  time.Now(),
@@ -189,6 +333,11 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		Status:     sharedDomain.SessionStatusActive,
 	}
 
+	// Bind the refresh token to this client's fingerprint when enabled
+	if s.config.Security.JWT.RefreshTokenBinding {
+		session.Fingerprint = clientFingerprint(userAgent, req.ClientSecret)
+	}
+
 	sessionRepo := s.repoManager.GetSessionRepository()
 	if err := sessionRepo.Create(ctx, session); err != nil {
 		return nil, fmt.Errorf("session creation failed: %w", err)
@@ -224,12 +373,14 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 		Username: user.Username,
 		Email:    user.Email,
 		Role:     user.Role,
+		OrgID:    user.OrgID,
 		Status:   user.Status,
 	}
 
-	// Cache user session
-	if err := s.repoManager.GetCacheRepository().SetUserSession(ctx, session.ID, authUser, s.config.Security.JWT.AccessTokenTTL); err != nil {
-		fmt.Printf("Failed to cache user session: %v\n", err)
+	// Cache user session keyed by the access-token JTI, the same key
+	// ValidateToken looks up
+	if err := s.repoManager.GetCacheRepository().SetUserSession(ctx, accessTokenID, authUser, s.config.Security.JWT.AccessTokenTTL); err != nil {
+		warnLog.Warnf("session_cache", "failed to cache user session: %v", err)
 	}
 
 	return &domain.AuthResponse{
@@ -238,6 +389,36 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest, ipAdd
 	}, nil
 }
 
+// validatePasswordStrength enforces the configured character-class rules and
+// the minimum strength score on every path that sets a password
+func (s *authService) validatePasswordStrength(password string) error {
+	passwordErrors := utils.ValidatePassword(
+		password,
+		s.config.Security.Password.MinLength,
+		s.config.Security.Password.RequireUppercase,
+		s.config.Security.Password.RequireLowercase,
+		s.config.Security.Password.RequireNumbers,
+		s.config.Security.Password.RequireSymbols,
+	)
+	if len(passwordErrors) > 0 {
+		return domain.NewAuthErrorWithField(domain.AuthErrWeakPassword, passwordErrors[0], "password")
+	}
+
+	// Rule-compliant passwords can still be guessable; reject passwords
+	// scoring below the configured minimum strength
+	if minScore := s.config.Security.Password.MinStrengthScore; minScore > 0 {
+		if score, guidance := utils.PasswordStrengthScore(password); score < minScore {
+			message := fmt.Sprintf("Password is too weak (score %d of minimum %d)", score, minScore)
+			if len(guidance) > 0 {
+				message += ": " + strings.Join(guidance, "; ")
+			}
+			return domain.NewAuthErrorWithField(domain.AuthErrWeakPassword, message, "password")
+		}
+	}
+
+	return nil
+}
+
 // Register creates a new user account
 func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest, ipAddress, userAgent string) (*domain.AuthResponse, error) {
 	// Validate request
@@ -245,6 +426,13 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest,
 		return nil, err
 	}
 
+	// Registration always requires a CAPTCHA when enabled
+	if s.config.Security.Captcha.Enabled {
+		if err := s.captchaVerifier.Verify(ctx, req.CaptchaToken, ipAddress); err != nil {
+			return nil, domain.NewAuthError(domain.AuthErrCaptchaRequired, "CAPTCHA verification failed")
+		}
+	}
+
 	// Validate email format
 	if !utils.ValidateEmail(req.Email) {
 		return nil, domain.NewAuthErrorWithField(sharedDomain.ErrorCodeValidation, "Invalid email format", "email")
@@ -256,16 +444,8 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest,
 	}
 
 	// Validate password strength
-	passwordErrors := utils.ValidatePassword(
-		req.Password,
-		s.config.Security.Password.MinLength,
-		s.config.Security.Password.RequireUppercase,
-		s.config.Security.Password.RequireLowercase,
-		s.config.Security.Password.RequireNumbers,
-		s.config.Security.Password.RequireSymbols,
-	)
-	if len(passwordErrors) > 0 {
-		return nil, domain.NewAuthErrorWithField(domain.AuthErrWeakPassword, passwordErrors[0], "password")
+	if err := s.validatePasswordStrength(req.Password); err != nil {
+		return nil, err
 	}
 
 	userRepo := s.repoManager.GetUserRepository()
@@ -316,11 +496,18 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest,
 		return nil, fmt.Errorf("token generation failed: %w", err)
 	}
 
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
 	// Create session
 	session := &sharedDomain.Session{
 		ID:         utils.GenerateID(),
 		UserID:     user.ID,
-		TokenID:    tokens.AccessToken, // This should be the JTI from the token
+		TokenID:    accessTokenID,
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
 		CreatedAt:  time.Now(),
@@ -357,12 +544,14 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest,
 		Username: user.Username,
 		Email:    user.Email,
 		Role:     user.Role,
+		OrgID:    user.OrgID,
 		Status:   user.Status,
 	}
 
-	// Cache user session
-	if err := s.repoManager.GetCacheRepository().SetUserSession(ctx, session.ID, authUser, s.config.Security.JWT.AccessTokenTTL); err != nil {
-		fmt.Printf("Failed to cache user session: %v\n", err)
+	// Cache user session keyed by the access-token JTI, the same key
+	// ValidateToken looks up
+	if err := s.repoManager.GetCacheRepository().SetUserSession(ctx, accessTokenID, authUser, s.config.Security.JWT.AccessTokenTTL); err != nil {
+		warnLog.Warnf("session_cache", "failed to cache user session: %v", err)
 	}
 
 	return &domain.AuthResponse{
@@ -399,6 +588,13 @@ func (s *authService) LogoutAll(ctx context.Context, userID string) error {
 		return fmt.Errorf("token revocation failed: %w", err)
 	}
 
+	// Watermark the revocation time so tokens the session table does not
+	// reference (refresh tokens in particular) are rejected too
+	watermark := []byte(strconv.FormatInt(time.Now().Unix(), 10))
+	if err := s.repoManager.GetCacheRepository().Set(ctx, "revoke_all:"+userID, watermark, s.config.Security.JWT.RefreshTokenTTL); err != nil {
+		fmt.Printf("Failed to set revocation watermark: %v\n", err)
+	}
+
 	// Delete all user sessions
 	sessionRepo := s.repoManager.GetSessionRepository()
 	if err := sessionRepo.DeleteByUserID(ctx, userID); err != nil {
@@ -414,7 +610,7 @@ func (s *authService) LogoutAll(ctx context.Context, userID string) error {
 }
 
 // RefreshToken generates new tokens using a refresh token
-func (s *authService) RefreshToken(ctx context.Context, req *domain.RefreshTokenRequest) (*domain.TokenPair, error) {
+func (s *authService) RefreshToken(ctx context.Context, req *domain.RefreshTokenRequest, userAgent string) (*domain.TokenPair, error) {
 	// Validate request
 	if err := req.Validate(); err != nil {
 		return nil, err
@@ -432,6 +628,9 @@ func (s *authService) RefreshToken(ctx context.Context, req *domain.RefreshToken
 	} else if revoked {
 		return nil, domain.NewAuthError(domain.AuthErrRevokedToken, "Token has been revoked")
 	}
+	if s.tokenPredatesRevokeAll(ctx, claims.UserID, claims.IssuedAt) {
+		return nil, domain.NewAuthError(domain.AuthErrRevokedToken, "Token has been revoked")
+	}
 
 	// Get user
 	userRepo := s.repoManager.GetUserRepository()
@@ -445,6 +644,13 @@ func (s *authService) RefreshToken(ctx context.Context, req *domain.RefreshToken
 		return nil, domain.NewAuthError(domain.AuthErrAccountInactive, "Account is not active")
 	}
 
+	// Verify the client fingerprint against the session the token was bound to
+	if s.config.Security.JWT.RefreshTokenBinding {
+		if err := s.verifyTokenBinding(ctx, user.ID, userAgent, req.ClientSecret); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate new token pair
 	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
 	if err != nil {
@@ -464,7 +670,589 @@ func (s *authService) RefreshToken(ctx context.Context, req *domain.RefreshToken
 	return tokens, nil
 }
 
+// tokenPredatesRevokeAll reports whether the token was issued at or before
+// the user's last revoke-all watermark and must therefore be rejected
+func (s *authService) tokenPredatesRevokeAll(ctx context.Context, userID string, issuedAt *domain.NumericDate) bool {
+	raw, err := s.repoManager.GetCacheRepository().Get(ctx, "revoke_all:"+userID)
+	if err != nil || len(raw) == 0 {
+		return false
+	}
+	cutoff, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return false
+	}
+	return issuedAt != nil && !issuedAt.Time.After(time.Unix(cutoff, 0))
+}
+
 // ValidateToken validates an access token and returns user info
+// CompleteMFALogin exchanges a login challenge token plus a verified OTP for
+// the token pair, finishing the mfa_required login step
+func (s *authService) CompleteMFALogin(ctx context.Context, challengeToken, code, ipAddress, userAgent string) (*domain.AuthResponse, error) {
+	userID, err := s.mfaService.VerifyChallenge(ctx, challengeToken, code)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoManager.GetUserRepository().GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewAuthError(domain.AuthErrUserNotFound, "User not found")
+	}
+
+	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("token generation failed: %w", err)
+	}
+
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
+	session := &sharedDomain.Session{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		TokenID:    accessTokenID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  tokens.ExpiresAt,
+		LastUsedAt: time.Now(),
+		Status:     sharedDomain.SessionStatusActive,
+	}
+	if err := s.repoManager.GetSessionRepository().Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("session creation failed: %w", err)
+	}
+
+	if err := s.activityService.LogActivity(ctx, user.ID, "user.login.mfa", "user", user.ID, nil, ipAddress, userAgent); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return &domain.AuthResponse{
+		User: &domain.AuthUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
+			OrgID:    user.OrgID,
+			Status:   user.Status,
+		},
+		Tokens: tokens,
+	}, nil
+}
+
+// WebAuthn exposes the passkey subsystem for the HTTP layer
+func (s *authService) WebAuthn() WebAuthnService {
+	return s.webauthnService
+}
+
+// CompletePasskeyLogin verifies a passkey assertion and issues the token
+// pair, mirroring the password login flow without a password
+func (s *authService) CompletePasskeyLogin(ctx context.Context, req *WebAuthnAssertionRequest, ipAddress, userAgent string) (*domain.AuthResponse, error) {
+	userID, err := s.webauthnService.FinishLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoManager.GetUserRepository().GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NewAuthError(domain.AuthErrUserNotFound, "User not found")
+	}
+	if user.Status != sharedDomain.UserStatusActive {
+		return nil, domain.NewAuthError(domain.AuthErrAccountInactive, "Account is not active")
+	}
+
+	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("token generation failed: %w", err)
+	}
+
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
+	session := &sharedDomain.Session{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		TokenID:    accessTokenID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  tokens.ExpiresAt,
+		LastUsedAt: time.Now(),
+		Status:     sharedDomain.SessionStatusActive,
+	}
+	if err := s.repoManager.GetSessionRepository().Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("session creation failed: %w", err)
+	}
+
+	if err := s.activityService.LogActivity(ctx, user.ID, "user.login.passkey", "user", user.ID, nil, ipAddress, userAgent); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return &domain.AuthResponse{
+		User: &domain.AuthUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
+			OrgID:    user.OrgID,
+			Status:   user.Status,
+		},
+		Tokens: tokens,
+	}, nil
+}
+
+// OAuth exposes the social-login subsystem for the HTTP layer
+func (s *authService) OAuth() OAuthService {
+	return s.oauthService
+}
+
+// CompleteOAuthLogin finishes a social-login callback by linking the
+// provider identity and issuing the standard token pair
+func (s *authService) CompleteOAuthLogin(ctx context.Context, provider, code, state, ipAddress, userAgent string) (*domain.AuthResponse, error) {
+	user, err := s.oauthService.HandleCallback(ctx, provider, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != sharedDomain.UserStatusActive {
+		return nil, domain.NewAuthError(domain.AuthErrAccountInactive, "Account is not active")
+	}
+
+	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("token generation failed: %w", err)
+	}
+
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
+	session := &sharedDomain.Session{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		TokenID:    accessTokenID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  tokens.ExpiresAt,
+		LastUsedAt: time.Now(),
+		Status:     sharedDomain.SessionStatusActive,
+	}
+	if err := s.repoManager.GetSessionRepository().Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("session creation failed: %w", err)
+	}
+
+	if err := s.activityService.LogActivity(ctx, user.ID, "user.login.oauth", "user", user.ID, map[string]interface{}{
+		"provider": provider,
+	}, ipAddress, userAgent); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return &domain.AuthResponse{
+		User: &domain.AuthUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
+			OrgID:    user.OrgID,
+			Status:   user.Status,
+		},
+		Tokens: tokens,
+	}, nil
+}
+
+// rejectReusedPassword enforces the password-history policy
+func (s *authService) rejectReusedPassword(ctx context.Context, userID, newPassword string) error {
+	if s.config.Security.Password.HistoryCount <= 0 {
+		return nil
+	}
+	reused, err := s.securityService.CheckPasswordHistory(ctx, userID, newPassword)
+	if err != nil {
+		return fmt.Errorf("password history check failed: %w", err)
+	}
+	if reused {
+		return domain.NewAuthError(domain.AuthErrWeakPassword,
+			fmt.Sprintf("Password was used recently; the last %d passwords cannot be reused", s.config.Security.Password.HistoryCount))
+	}
+	return nil
+}
+
+// recordPasswordChange retains the replaced hash for history checks and
+// stamps the change time for expiry enforcement
+func (s *authService) recordPasswordChange(ctx context.Context, userID, previousHash string) {
+	if err := s.securityService.AddPasswordToHistory(ctx, userID, previousHash); err != nil {
+		fmt.Printf("Failed to record password history: %v\n", err)
+	}
+
+	userRepo := s.repoManager.GetUserRepository()
+	if user, err := userRepo.GetByID(ctx, userID); err == nil {
+		if user.Metadata == nil {
+			user.Metadata = make(map[string]interface{})
+		}
+		user.Metadata["password_changed_at"] = time.Now().Format(time.RFC3339)
+		if err := userRepo.Update(ctx, user); err != nil {
+			fmt.Printf("Failed to stamp password change time: %v\n", err)
+		}
+	}
+}
+
+// passwordExpired reports whether the user's password exceeds the optional
+// maximum age, forcing a reset at login
+func (s *authService) passwordExpired(user *sharedDomain.User) bool {
+	maxAge := s.config.Security.Password.MaxAge
+	if maxAge <= 0 {
+		return false
+	}
+
+	changedAt := user.CreatedAt
+	if raw, ok := user.Metadata["password_changed_at"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			changedAt = parsed
+		}
+	}
+	return time.Since(changedAt) > maxAge
+}
+
+// ListUsers lists or searches users for the admin API, optionally filtered
+// by role, status or a username query
+func (s *authService) ListUsers(ctx context.Context, role, status, query string, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	userRepo := s.repoManager.GetUserRepository()
+
+	switch {
+	case role != "":
+		return userRepo.GetUsersByRole(ctx, sharedDomain.UserRole(role), pagination)
+	case status != "":
+		return userRepo.GetUsersByStatus(ctx, sharedDomain.UserStatus(status), pagination)
+	case query != "":
+		return userRepo.Search(ctx, map[string]interface{}{"username": query}, pagination)
+	default:
+		return userRepo.List(ctx, pagination)
+	}
+}
+
+// validUserRoles is the allowlist for admin role changes
+var validUserRoles = map[sharedDomain.UserRole]bool{
+	sharedDomain.UserRoleAdmin:     true,
+	sharedDomain.UserRoleModerator: true,
+	sharedDomain.UserRoleUser:      true,
+	sharedDomain.UserRoleGuest:     true,
+}
+
+// UpdateUserRole changes a user's role (admin operation)
+func (s *authService) UpdateUserRole(ctx context.Context, userID string, role sharedDomain.UserRole) error {
+	if !validUserRoles[role] {
+		return fmt.Errorf("unknown role %q", role)
+	}
+
+	userRepo := s.repoManager.GetUserRepository()
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.NewAuthError(domain.AuthErrUserNotFound, "User not found")
+	}
+
+	oldRole := user.Role
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	if err := userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	if err := s.activityService.LogActivity(ctx, userID, "user.role_changed", "user", userID, map[string]interface{}{
+		"old_role": oldRole,
+		"new_role": role,
+	}, "", ""); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return nil
+}
+
+// DeleteAccount soft-deletes the user, revokes every token and session, and
+// schedules permanent erasure of their files and analytics events after the
+// configured retention window. A user.deleted domain event is emitted so the
+// other services can react.
+func (s *authService) DeleteAccount(ctx context.Context, userID string) error {
+	userRepo := s.repoManager.GetUserRepository()
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.NewAuthError(domain.AuthErrUserNotFound, "User not found")
+	}
+
+	// Soft delete and stamp when the retention window elapses
+	now := time.Now()
+	erasureDue := now.Add(s.config.AccountDeletion.GracePeriod)
+	user.Status = sharedDomain.UserStatusDeleted
+	user.UpdatedAt = now
+	if user.Metadata == nil {
+		user.Metadata = make(map[string]interface{})
+	}
+	user.Metadata["erasure_due_at"] = erasureDue.Format(time.RFC3339)
+	if err := userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to mark account deleted: %w", err)
+	}
+
+	// Revoke every session and token immediately
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		fmt.Printf("Failed to revoke sessions during deletion: %v\n", err)
+	}
+
+	// Emit the user.deleted domain event for the file and analytics
+	// services, which own the scheduled purge of the user's data
+	event := &sharedDomain.Event{
+		ID:        utils.GenerateID(),
+		Type:      sharedDomain.EventTypeUserDeleted,
+		Source:    "auth",
+		Subject:   userID,
+		Timestamp: now,
+		Version:   "1",
+		Data: map[string]interface{}{
+			"erasure_due_at": erasureDue,
+		},
+	}
+	s.publishDomainEvent(ctx, event)
+
+	// Audit entry
+	if err := s.activityService.LogActivity(ctx, userID, "user.account_deleted", "user", userID, map[string]interface{}{
+		"erasure_due_at": erasureDue,
+	}, "", ""); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return nil
+}
+
+// PurgeDueErasures permanently erases accounts whose retention window has
+// elapsed, returning the number purged. Run periodically by an admin job.
+func (s *authService) PurgeDueErasures(ctx context.Context) (int, error) {
+	userRepo := s.repoManager.GetUserRepository()
+	result, err := userRepo.GetUsersByStatus(ctx, sharedDomain.UserStatusDeleted, &data.Pagination{Page: 1, PageSize: 500})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deleted users: %w", err)
+	}
+
+	users, ok := result.Data.([]*sharedDomain.User)
+	if !ok {
+		return 0, nil
+	}
+
+	purged := 0
+	for _, user := range users {
+		raw, _ := user.Metadata["erasure_due_at"].(string)
+		dueAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil || time.Now().Before(dueAt) {
+			continue
+		}
+
+		if err := userRepo.Delete(ctx, user.ID); err != nil {
+			fmt.Printf("Failed to purge user %s: %v\n", user.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// QuerySecurityEvents surfaces the audit trail for admins, filtered by
+// user, action or time range
+func (s *authService) QuerySecurityEvents(ctx context.Context, userID, action string, start, end time.Time, pagination *data.Pagination) (*data.PaginatedResult, error) {
+	logRepo := s.repoManager.GetActivityLogRepository()
+
+	switch {
+	case userID != "":
+		return logRepo.GetByUserID(ctx, userID, pagination)
+	case action != "":
+		return logRepo.GetByAction(ctx, action, pagination)
+	case !start.IsZero() && !end.IsZero():
+		return logRepo.GetByTimeRange(ctx, start, end, pagination)
+	default:
+		return logRepo.GetSecurityEvents(ctx, start, pagination)
+	}
+}
+
+// magicLinkTTL bounds how long a passwordless login link stays valid
+const magicLinkTTL = 15 * time.Minute
+
+// RequestMagicLink emails the user a one-time signed login link. To avoid
+// account enumeration the call succeeds silently for unknown addresses.
+func (s *authService) RequestMagicLink(ctx context.Context, email string) error {
+	user, err := s.repoManager.GetUserRepository().GetByEmail(ctx, email)
+	if err != nil || user == nil {
+		return nil
+	}
+	if user.Status != sharedDomain.UserStatusActive {
+		return nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("failed to generate magic link token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	// Single-use + short TTL, enforced via the cache repository
+	cacheRepo := s.repoManager.GetCacheRepository()
+	if err := cacheRepo.Set(ctx, "magic_link:"+token, []byte(user.ID), magicLinkTTL); err != nil {
+		return fmt.Errorf("failed to store magic link: %w", err)
+	}
+
+	magicLink := fmt.Sprintf("%s/api/v1/auth/magic/verify?token=%s", s.config.Services.Auth.BaseURL, token)
+	if err := s.notificationService.SendMagicLinkEmail(ctx, user.Email, magicLink); err != nil {
+		return fmt.Errorf("failed to send magic link: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyMagicLink consumes a magic-link token and issues the normal token
+// pair. A SetNX claim marker makes redemption atomic: concurrent requests
+// with the same token race on the claim and only the winner gets a session.
+func (s *authService) VerifyMagicLink(ctx context.Context, token, ipAddress, userAgent string) (*domain.AuthResponse, error) {
+	cacheRepo := s.repoManager.GetCacheRepository()
+
+	userIDBytes, err := cacheRepo.Get(ctx, "magic_link:"+token)
+	if err != nil || len(userIDBytes) == 0 {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid or expired magic link")
+	}
+
+	claimed, err := cacheRepo.SetNX(ctx, "magic_link_used:"+token, []byte("1"), magicLinkTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim magic link token: %w", err)
+	}
+	if !claimed {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid or expired magic link")
+	}
+
+	if err := cacheRepo.Delete(ctx, "magic_link:"+token); err != nil {
+		fmt.Printf("Failed to burn magic link token: %v\n", err)
+	}
+
+	user, err := s.repoManager.GetUserRepository().GetByID(ctx, string(userIDBytes))
+	if err != nil {
+		return nil, domain.NewAuthError(domain.AuthErrUserNotFound, "User not found")
+	}
+	if user.Status != sharedDomain.UserStatusActive {
+		return nil, domain.NewAuthError(domain.AuthErrAccountInactive, "Account is not active")
+	}
+
+	tokens, err := s.tokenService.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("token generation failed: %w", err)
+	}
+
+	// Sessions are keyed by the access token's JTI, which is what
+	// validation and revocation check against
+	accessTokenID, err := s.tokenService.ExtractTokenID(ctx, tokens.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("token ID extraction failed: %w", err)
+	}
+
+	session := &sharedDomain.Session{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		TokenID:    accessTokenID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  tokens.ExpiresAt,
+		LastUsedAt: time.Now(),
+		Status:     sharedDomain.SessionStatusActive,
+	}
+	if err := s.repoManager.GetSessionRepository().Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("session creation failed: %w", err)
+	}
+
+	if err := s.activityService.LogActivity(ctx, user.ID, "user.login.magic_link", "user", user.ID, nil, ipAddress, userAgent); err != nil {
+		fmt.Printf("Failed to log activity: %v\n", err)
+	}
+
+	return &domain.AuthResponse{
+		User: &domain.AuthUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
+			OrgID:    user.OrgID,
+			Status:   user.Status,
+		},
+		Tokens: tokens,
+	}, nil
+}
+
+// publishDomainEvent emits a domain event to the other services. Until a
+// message bus is wired it is recorded in the activity log, which the
+// consuming services poll.
+func (s *authService) publishDomainEvent(ctx context.Context, event *sharedDomain.Event) {
+	if err := s.activityService.LogActivity(ctx, event.Subject, "event."+event.Type, "domain_event", event.ID, event.Data, "", ""); err != nil {
+		fmt.Printf("Failed to publish domain event %s: %v\n", event.Type, err)
+	}
+}
+
+// lockDuration computes the lockout length from the failure count. With
+// exponential growth, every extra failure beyond the threshold doubles the
+// base duration, capped at the configured maximum.
+func (s *authService) lockDuration(failedAttempts int64) time.Duration {
+	lockout := s.config.Security.Lockout
+	duration := lockout.BaseLockDuration
+
+	if lockout.ExponentialGrowth {
+		excess := failedAttempts - int64(lockout.MaxAttempts-1)
+		for i := int64(0); i < excess && duration < lockout.MaxLockDuration; i++ {
+			duration *= 2
+		}
+	}
+
+	if lockout.MaxLockDuration > 0 && duration > lockout.MaxLockDuration {
+		duration = lockout.MaxLockDuration
+	}
+	return duration
+}
+
+// clientFingerprint hashes the user agent together with the client-provided
+// secret to produce a stable, non-reversible client identifier
+func clientFingerprint(userAgent, clientSecret string) string {
+	sum := sha256.Sum256([]byte(userAgent + "\x00" + clientSecret))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyTokenBinding checks that the refreshing client's fingerprint matches
+// one of the user's bound active sessions
+func (s *authService) verifyTokenBinding(ctx context.Context, userID, userAgent, clientSecret string) error {
+	presented := clientFingerprint(userAgent, clientSecret)
+
+	sessions, err := s.repoManager.GetSessionRepository().GetActiveSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("session lookup failed: %w", err)
+	}
+
+	bound := false
+	for _, session := range sessions {
+		if session.Fingerprint == "" {
+			continue
+		}
+		bound = true
+		if session.Fingerprint == presented {
+			return nil
+		}
+	}
+
+	// Sessions created before binding was enabled carry no fingerprint and
+	// are accepted for compatibility
+	if !bound {
+		return nil
+	}
+
+	return domain.NewAuthError(domain.AuthErrTokenBindingMismatch, "Refresh token is bound to a different client")
+}
+
 func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.AuthUser, error) {
 	// Validate token
 	claims, err := s.tokenService.ValidateAccessToken(ctx, token)
@@ -472,12 +1260,18 @@ func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.
 		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid token")
 	}
 
-	// Check if token is revoked
+	// Check if token is revoked, honoring the cache failure policy
 	if revoked, err := s.IsTokenRevoked(ctx, claims.TokenID); err != nil {
-		return nil, fmt.Errorf("token revocation check failed: %w", err)
+		if s.config.Cache.FailurePolicyFor("revocation") != "fail-open" {
+			return nil, fmt.Errorf("token revocation check failed: %w", err)
+		}
+		warnLog.Warnf("revocation_degraded", "token revocation check degraded (fail-open): %v", err)
 	} else if revoked {
 		return nil, domain.NewAuthError(domain.AuthErrRevokedToken, "Token has been revoked")
 	}
+	if s.tokenPredatesRevokeAll(ctx, claims.UserID, claims.IssuedAt) {
+		return nil, domain.NewAuthError(domain.AuthErrRevokedToken, "Token has been revoked")
+	}
 
 	// Try to get user from cache first
 	cacheRepo := s.repoManager.GetCacheRepository()
@@ -503,17 +1297,69 @@ func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.
 		Username: user.Username,
 		Email:    user.Email,
 		Role:     user.Role,
+		OrgID:    user.OrgID,
 		Status:   user.Status,
 	}
 
 	// Cache user session
 	if err := cacheRepo.SetUserSession(ctx, claims.TokenID, authUser, s.config.Security.JWT.AccessTokenTTL); err != nil {
-		fmt.Printf("Failed to cache user session: %v\n", err)
+		warnLog.Warnf("session_cache", "failed to cache user session: %v", err)
 	}
 
+	// Extend the session on activity when sliding sessions are enabled
+	s.extendSessionOnActivity(ctx, claims.TokenID)
+
 	return authUser, nil
 }
 
+// extendSessionOnActivity pushes the session's expiry forward by the
+// configured sliding extension, capped at the absolute maximum lifetime from
+// session creation. This keeps active users logged in without touching
+// refresh-token rotation, which still governs token renewal.
+func (s *authService) extendSessionOnActivity(ctx context.Context, tokenID string) {
+	sessionConfig := s.config.Security.Session
+	if !sessionConfig.SlidingEnabled || sessionConfig.SlidingExtension <= 0 {
+		return
+	}
+
+	sessionRepo := s.repoManager.GetSessionRepository()
+	session, err := sessionRepo.GetByTokenID(ctx, tokenID)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	newExpiry := now.Add(sessionConfig.SlidingExtension)
+
+	// Cap at the absolute maximum lifetime from session creation
+	if sessionConfig.SlidingMaxLifetime > 0 {
+		if absoluteMax := session.CreatedAt.Add(sessionConfig.SlidingMaxLifetime); newExpiry.After(absoluteMax) {
+			newExpiry = absoluteMax
+		}
+	}
+
+	if !newExpiry.After(session.ExpiresAt) {
+		return
+	}
+
+	session.ExpiresAt = newExpiry
+	if err := sessionRepo.Update(ctx, session); err != nil {
+		fmt.Printf("Failed to extend session %s: %v\n", session.ID, err)
+		return
+	}
+	if err := sessionRepo.UpdateLastUsed(ctx, session.ID, now); err != nil {
+		fmt.Printf("Failed to update session last-used time: %v\n", err)
+	}
+
+	// Keep the cached session entry alive for the extended window
+	cacheRepo := s.repoManager.GetCacheRepository()
+	if authUser, err := cacheRepo.GetUserSession(ctx, tokenID); err == nil && authUser != nil {
+		if err := cacheRepo.SetUserSession(ctx, tokenID, authUser, time.Until(newExpiry)); err != nil {
+			fmt.Printf("Failed to refresh cached session TTL: %v\n", err)
+		}
+	}
+}
+
 // RevokeToken revokes a specific token
 func (s *authService) RevokeToken(ctx context.Context, tokenID, userID, reason string) error {
 	// Add to revoked tokens
@@ -550,6 +1396,10 @@ func (s *authService) ChangePassword(ctx context.Context, userID string, req *do
 		return err
 	}
 
+	if err := s.validatePasswordStrength(req.NewPassword); err != nil {
+		return err
+	}
+
 	userRepo := s.repoManager.GetUserRepository()
 	user, err := userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -561,6 +1411,11 @@ func (s *authService) ChangePassword(ctx context.Context, userID string, req *do
 		return domain.NewAuthError(domain.AuthErrInvalidCredentials, "Current password is incorrect")
 	}
 
+	// Reject reuse of recent passwords
+	if err := s.rejectReusedPassword(ctx, userID, req.NewPassword); err != nil {
+		return err
+	}
+
 	// Hash new password
 	newPasswordHash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), s.config.Security.Password.BcryptCost)
 	if err != nil {
@@ -571,6 +1426,7 @@ func (s *authService) ChangePassword(ctx context.Context, userID string, req *do
 	if err := userRepo.UpdatePassword(ctx, userID, string(newPasswordHash)); err != nil {
 		return fmt.Errorf("password update failed: %w", err)
 	}
+	s.recordPasswordChange(ctx, userID, user.PasswordHash)
 
 	// Log activity
 	if err := s.activityService.LogActivity(ctx, userID, "user.password_changed", "user", userID, nil, "", ""); err != nil {
@@ -632,6 +1488,10 @@ func (s *authService) ConfirmPasswordReset(ctx context.Context, req *domain.Conf
 		return err
 	}
 
+	if err := s.validatePasswordStrength(req.NewPassword); err != nil {
+		return err
+	}
+
 	resetRepo := s.repoManager.GetPasswordResetTokenRepository()
 	resetToken, err := resetRepo.GetByToken(ctx, req.Token)
 	if err != nil {
@@ -642,6 +1502,11 @@ func (s *authService) ConfirmPasswordReset(ctx context.Context, req *domain.Conf
 		return domain.NewAuthError(domain.AuthErrExpiredToken, "Reset token has expired")
 	}
 
+	// Reject reuse of recent passwords
+	if err := s.rejectReusedPassword(ctx, resetToken.UserID, req.NewPassword); err != nil {
+		return err
+	}
+
 	// Hash new password
 	newPasswordHash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), s.config.Security.Password.BcryptCost)
 	if err != nil {
@@ -650,9 +1515,13 @@ func (s *authService) ConfirmPasswordReset(ctx context.Context, req *domain.Conf
 
 	// Update password
 	userRepo := s.repoManager.GetUserRepository()
+	previous, _ := userRepo.GetByID(ctx, resetToken.UserID)
 	if err := userRepo.UpdatePassword(ctx, resetToken.UserID, string(newPasswordHash)); err != nil {
 		return fmt.Errorf("password update failed: %w", err)
 	}
+	if previous != nil {
+		s.recordPasswordChange(ctx, resetToken.UserID, previous.PasswordHash)
+	}
 
 	// Mark token as used
 	if err := resetRepo.MarkAsUsed(ctx, resetToken.ID); err != nil {
@@ -685,6 +1554,7 @@ func (s *authService) GetUserProfile(ctx context.Context, userID string) (*domai
 		Username: user.Username,
 		Email:    user.Email,
 		Role:     user.Role,
+		OrgID:    user.OrgID,
 		Status:   user.Status,
 	}, nil
 }
@@ -787,6 +1657,39 @@ func (s *authService) GetUserSessions(ctx context.Context, userID string) ([]*sh
 	return sessionRepo.GetByUserID(ctx, userID)
 }
 
+// GetSessionGeoDistribution aggregates active sessions by country so
+// security dashboards can spot logins from unexpected regions. Sessions
+// without geo enrichment fall back to a coarse classification of their IP.
+func (s *authService) GetSessionGeoDistribution(ctx context.Context, since time.Time) (map[string]int64, error) {
+	sessions, err := s.repoManager.GetSessionRepository().GetAllActiveSessions(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active sessions: %w", err)
+	}
+
+	distribution := make(map[string]int64)
+	for _, session := range sessions {
+		country := session.Country
+		if country == "" {
+			country = classifyIP(session.IPAddress)
+		}
+		distribution[country]++
+	}
+
+	return distribution, nil
+}
+
+// classifyIP coarsely classifies an IP when no geo enrichment is stored
+func classifyIP(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return "unknown"
+	}
+	if ip.IsLoopback() || ip.IsPrivate() {
+		return "internal"
+	}
+	return "unresolved"
+}
+
 // RevokeSession revokes a specific session
 func (s *authService) RevokeSession(ctx context.Context, sessionID string) error {
 	sessionRepo := s.repoManager.GetSessionRepository()
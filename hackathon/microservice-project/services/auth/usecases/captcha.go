@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/elotusteam/microservice-project/shared/config"
+)
+
+// CaptchaVerifier validates CAPTCHA tokens against the configured provider.
+// Pluggable so tests (and deployments without CAPTCHA) can substitute a
+// no-op implementation.
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// captchaVerifyURLs maps providers to their server-side verification endpoints
+var captchaVerifyURLs = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"hcaptcha":  "https://hcaptcha.com/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// HTTPCaptchaVerifier verifies tokens against the provider's siteverify API
+type HTTPCaptchaVerifier struct {
+	config *config.CaptchaConfig
+	client *http.Client
+}
+
+// NewCaptchaVerifier creates a verifier for the configured provider; with
+// CAPTCHA disabled a no-op verifier is returned
+func NewCaptchaVerifier(cfg *config.CaptchaConfig) CaptchaVerifier {
+	if !cfg.Enabled {
+		return &NoopCaptchaVerifier{}
+	}
+	return &HTTPCaptchaVerifier{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify posts the token to the provider's verification endpoint
+func (v *HTTPCaptchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return fmt.Errorf("CAPTCHA token is required")
+	}
+
+	verifyURL, ok := captchaVerifyURLs[v.config.Provider]
+	if !ok {
+		return fmt.Errorf("unknown CAPTCHA provider %q", v.config.Provider)
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.config.Secret)
+	form.Set("response", token)
+	form.Set("remoteip", remoteIP)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("CAPTCHA verification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("invalid CAPTCHA verification response: %w", err)
+	}
+	if !payload.Success {
+		return fmt.Errorf("CAPTCHA verification failed")
+	}
+
+	return nil
+}
+
+// NoopCaptchaVerifier accepts everything; used when CAPTCHA is disabled
+type NoopCaptchaVerifier struct{}
+
+// Verify always succeeds
+func (v *NoopCaptchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	return nil
+}
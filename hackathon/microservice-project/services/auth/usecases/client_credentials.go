@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+)
+
+// ServiceTokenTTL bounds machine token lifetime; short because services can
+// re-request cheaply
+const ServiceTokenTTL = 15 * time.Minute
+
+// ServiceClient is a registered internal service allowed to obtain machine
+// tokens via the client-credentials grant
+type ServiceClient struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"-"`
+	Scopes       []string `json:"scopes"`
+}
+
+// ClientRegistry stores the registered service clients
+type ClientRegistry interface {
+	Get(ctx context.Context, clientID string) (*ServiceClient, error)
+	Register(ctx context.Context, client *ServiceClient) error
+}
+
+// InMemoryClientRegistry seeds clients from the AUTH_SERVICE_CLIENTS
+// environment variable, formatted as comma-separated
+// "client_id:client_secret:scope1|scope2" entries.
+type InMemoryClientRegistry struct {
+	mu      sync.RWMutex
+	clients map[string]*ServiceClient
+}
+
+// NewInMemoryClientRegistry creates the registry and loads env-seeded clients
+func NewInMemoryClientRegistry() ClientRegistry {
+	registry := &InMemoryClientRegistry{
+		clients: make(map[string]*ServiceClient),
+	}
+
+	for _, entry := range strings.Split(os.Getenv("AUTH_SERVICE_CLIENTS"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			continue
+		}
+		registry.clients[parts[0]] = &ServiceClient{
+			ClientID:     parts[0],
+			ClientSecret: parts[1],
+			Scopes:       strings.Split(parts[2], "|"),
+		}
+	}
+
+	return registry
+}
+
+func (r *InMemoryClientRegistry) Get(ctx context.Context, clientID string) (*ServiceClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, exists := r.clients[clientID]
+	if !exists {
+		return nil, fmt.Errorf("unknown client")
+	}
+	return client, nil
+}
+
+func (r *InMemoryClientRegistry) Register(ctx context.Context, client *ServiceClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[client.ClientID] = client
+	return nil
+}
+
+// ClientCredentialsService issues scoped machine tokens to internal services
+type ClientCredentialsService interface {
+	Token(ctx context.Context, clientID, clientSecret string, requestedScopes []string) (*ServiceTokenResponse, error)
+	VerifyServiceToken(ctx context.Context, token, requiredScope string) (*domain.JWTClaims, error)
+
+	// AuthenticateClient checks client credentials without issuing a token,
+	// for endpoints (e.g. introspection) that require caller authentication
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) error
+}
+
+// ServiceTokenResponse is the client-credentials grant response
+type ServiceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// clientCredentialsService implements ClientCredentialsService
+type clientCredentialsService struct {
+	config       *config.Config
+	registry     ClientRegistry
+	tokenService TokenService
+}
+
+// NewClientCredentialsService creates the client-credentials grant service
+func NewClientCredentialsService(config *config.Config, registry ClientRegistry, tokenService TokenService) ClientCredentialsService {
+	return &clientCredentialsService{
+		config:       config,
+		registry:     registry,
+		tokenService: tokenService,
+	}
+}
+
+// AuthenticateClient checks client credentials against the registry
+func (s *clientCredentialsService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) error {
+	client, err := s.registry.Get(ctx, clientID)
+	if err != nil {
+		return domain.NewAuthError(domain.AuthErrInvalidCredentials, "Invalid client credentials")
+	}
+	if subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) != 1 {
+		return domain.NewAuthError(domain.AuthErrInvalidCredentials, "Invalid client credentials")
+	}
+	return nil
+}
+
+// Token authenticates the client and issues a machine token restricted to
+// the granted scopes
+func (s *clientCredentialsService) Token(ctx context.Context, clientID, clientSecret string, requestedScopes []string) (*ServiceTokenResponse, error) {
+	if err := s.AuthenticateClient(ctx, clientID, clientSecret); err != nil {
+		return nil, err
+	}
+
+	client, err := s.registry.Get(ctx, clientID)
+	if err != nil {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidCredentials, "Invalid client credentials")
+	}
+
+	// Grant only scopes the client is registered for
+	granted := client.Scopes
+	if len(requestedScopes) > 0 {
+		allowed := make(map[string]bool, len(client.Scopes))
+		for _, scope := range client.Scopes {
+			allowed[scope] = true
+		}
+		granted = granted[:0]
+		for _, scope := range requestedScopes {
+			if !allowed[scope] {
+				return nil, domain.NewAuthError(domain.AuthErrInsufficientPerms, fmt.Sprintf("Scope %q is not granted to this client", scope))
+			}
+			granted = append(granted, scope)
+		}
+	}
+
+	token, err := s.tokenService.GenerateServiceToken(ctx, clientID, granted)
+	if err != nil {
+		return nil, fmt.Errorf("service token generation failed: %w", err)
+	}
+
+	return &ServiceTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(ServiceTokenTTL.Seconds()),
+		Scope:       strings.Join(granted, " "),
+	}, nil
+}
+
+// VerifyServiceToken validates a machine token and checks its audience and
+// scope claims, for use by middleware in the consuming services
+func (s *clientCredentialsService) VerifyServiceToken(ctx context.Context, token, requiredScope string) (*domain.JWTClaims, error) {
+	claims, err := s.tokenService.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid service token")
+	}
+	if claims.TokenType != "service" {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Token is not a service token")
+	}
+
+	if requiredScope != "" {
+		granted := strings.Fields(claims.Scope)
+		found := false
+		for _, scope := range granted {
+			if scope == requiredScope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, domain.NewAuthError(domain.AuthErrInsufficientPerms, fmt.Sprintf("Token lacks required scope %q", requiredScope))
+		}
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/utils"
+)
+
+// DefaultTrustedDeviceWindow is how long a trusted device skips MFA.
+// Override with SECURITY_TRUSTED_DEVICE_WINDOW.
+const DefaultTrustedDeviceWindow = 30 * 24 * time.Hour
+
+func trustedDeviceWindow() time.Duration {
+	if value := os.Getenv("SECURITY_TRUSTED_DEVICE_WINDOW"); value != "" {
+		if window, err := time.ParseDuration(value); err == nil && window > 0 {
+			return window
+		}
+	}
+	return DefaultTrustedDeviceWindow
+}
+
+// Device is a client device observed at login, identified by a fingerprint
+// derived from its user agent. Users can name and trust devices; trusted
+// devices skip MFA until the trust window elapses.
+type Device struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	Fingerprint  string     `json:"fingerprint"`
+	Name         string     `json:"name"`
+	UserAgent    string     `json:"user_agent"`
+	Trusted      bool       `json:"trusted"`
+	TrustedUntil *time.Time `json:"trusted_until,omitempty"`
+	FirstSeen    time.Time  `json:"first_seen"`
+	LastSeen     time.Time  `json:"last_seen"`
+}
+
+// DeviceRepository stores observed devices per user
+type DeviceRepository interface {
+	Upsert(ctx context.Context, device *Device) error
+	GetByID(ctx context.Context, deviceID string) (*Device, error)
+	GetByFingerprint(ctx context.Context, userID, fingerprint string) (*Device, error)
+	GetByUserID(ctx context.Context, userID string) ([]*Device, error)
+	Delete(ctx context.Context, deviceID string) error
+}
+
+// InMemoryDeviceRepository is the default device store
+type InMemoryDeviceRepository struct {
+	mu      sync.RWMutex
+	devices map[string]*Device
+}
+
+// NewInMemoryDeviceRepository creates an in-memory device store
+func NewInMemoryDeviceRepository() DeviceRepository {
+	return &InMemoryDeviceRepository{devices: make(map[string]*Device)}
+}
+
+func (r *InMemoryDeviceRepository) Upsert(ctx context.Context, device *Device) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices[device.ID] = device
+	return nil
+}
+
+func (r *InMemoryDeviceRepository) GetByID(ctx context.Context, deviceID string) (*Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	device, exists := r.devices[deviceID]
+	if !exists {
+		return nil, fmt.Errorf("device not found")
+	}
+	return device, nil
+}
+
+func (r *InMemoryDeviceRepository) GetByFingerprint(ctx context.Context, userID, fingerprint string) (*Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, device := range r.devices {
+		if device.UserID == userID && device.Fingerprint == fingerprint {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("device not found")
+}
+
+func (r *InMemoryDeviceRepository) GetByUserID(ctx context.Context, userID string) ([]*Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var devices []*Device
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *InMemoryDeviceRepository) Delete(ctx context.Context, deviceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.devices, deviceID)
+	return nil
+}
+
+// DeviceService tracks devices per user and manages trust
+type DeviceService interface {
+	// RecordLogin upserts the device seen at login and returns it
+	RecordLogin(ctx context.Context, userID, userAgent string) (*Device, error)
+
+	// IsTrusted reports whether the device fingerprint is currently trusted
+	IsTrusted(ctx context.Context, userID, userAgent string) bool
+
+	TrustDevice(ctx context.Context, userID, deviceID, name string) error
+	RevokeTrust(ctx context.Context, userID, deviceID string) error
+	ListDevices(ctx context.Context, userID string) ([]*Device, error)
+}
+
+// deviceService implements DeviceService
+type deviceService struct {
+	devices DeviceRepository
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(devices DeviceRepository) DeviceService {
+	return &deviceService{devices: devices}
+}
+
+// DeviceFingerprint derives the stable device identifier from the user agent
+func DeviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *deviceService) RecordLogin(ctx context.Context, userID, userAgent string) (*Device, error) {
+	fingerprint := DeviceFingerprint(userAgent)
+	now := time.Now()
+
+	device, err := s.devices.GetByFingerprint(ctx, userID, fingerprint)
+	if err != nil {
+		device = &Device{
+			ID:          utils.GenerateID(),
+			UserID:      userID,
+			Fingerprint: fingerprint,
+			UserAgent:   userAgent,
+			FirstSeen:   now,
+		}
+	}
+	device.LastSeen = now
+
+	if err := s.devices.Upsert(ctx, device); err != nil {
+		return nil, fmt.Errorf("failed to record device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *deviceService) IsTrusted(ctx context.Context, userID, userAgent string) bool {
+	device, err := s.devices.GetByFingerprint(ctx, userID, DeviceFingerprint(userAgent))
+	if err != nil || !device.Trusted {
+		return false
+	}
+	return device.TrustedUntil == nil || time.Now().Before(*device.TrustedUntil)
+}
+
+func (s *deviceService) TrustDevice(ctx context.Context, userID, deviceID, name string) error {
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device.UserID != userID {
+		return domain.NewAuthError(domain.AuthErrInsufficientPerms, "Device belongs to a different user")
+	}
+
+	until := time.Now().Add(trustedDeviceWindow())
+	device.Trusted = true
+	device.TrustedUntil = &until
+	if name != "" {
+		device.Name = name
+	}
+	return s.devices.Upsert(ctx, device)
+}
+
+func (s *deviceService) RevokeTrust(ctx context.Context, userID, deviceID string) error {
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device.UserID != userID {
+		return domain.NewAuthError(domain.AuthErrInsufficientPerms, "Device belongs to a different user")
+	}
+
+	device.Trusted = false
+	device.TrustedUntil = nil
+	return s.devices.Upsert(ctx, device)
+}
+
+func (s *deviceService) ListDevices(ctx context.Context, userID string) ([]*Device, error) {
+	return s.devices.GetByUserID(ctx, userID)
+}
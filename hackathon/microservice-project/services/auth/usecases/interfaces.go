@@ -29,6 +29,13 @@ type TokenService interface {
 	GetTokenExpiration(ctx context.Context, token string) (time.Time, error)
 	IsTokenExpired(ctx context.Context, token string) (bool, error)
 	GetTokenType(ctx context.Context, token string) (string, error)
+
+	// Service tokens (client-credentials grant)
+	GenerateServiceToken(ctx context.Context, clientID string, scopes []string) (string, error)
+
+	// Asymmetric signing (RS256 mode)
+	JWKS(ctx context.Context) (map[string]interface{}, error)
+	RotateSigningKey(ctx context.Context) (string, error)
 }
 
 // NotificationService defines the interface for notification operations
@@ -39,6 +46,7 @@ type NotificationService interface {
 	SendPasswordChangedEmail(ctx context.Context, email, firstName string) error
 	SendAccountLockedEmail(ctx context.Context, email, firstName string) error
 	SendLoginAlertEmail(ctx context.Context, email, firstName, ipAddress, userAgent string) error
+	SendMagicLinkEmail(ctx context.Context, email, magicLink string) error
 
 	// SMS notifications
 	SendSMS(ctx context.Context, phoneNumber, message string) error
@@ -108,8 +116,9 @@ type SecurityService interface {
 	GetLoginAttempts(ctx context.Context, identifier string, since time.Time) ([]*domain.LoginAttempt, error)
 	ClearLoginAttempts(ctx context.Context, identifier string) error
 
-	// Suspicious activity detection
-	DetectSuspiciousActivity(ctx context.Context, userID, ipAddress, userAgent string) (bool, error)
+	// Suspicious activity detection. identifier is the login identifier
+	// (username/email) that login attempts are recorded under.
+	DetectSuspiciousActivity(ctx context.Context, userID, identifier, ipAddress, userAgent string) (bool, error)
 	RecordSuspiciousActivity(ctx context.Context, userID, activityType, description, ipAddress, userAgent string) error
 
 	// IP and device management
@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TOTP parameters (RFC 6238): 30-second steps, 6 digits, one step of skew
+const (
+	totpPeriod = 30 * time.Second
+	totpDigits = 6
+	totpSkew   = 1
+
+	mfaBackupCodeCount = 8
+	mfaChallengeTTL    = 5 * time.Minute
+	mfaSecretBytes     = 20
+)
+
+// MFAEnrollment holds a user's TOTP enrollment state
+type MFAEnrollment struct {
+	UserID           string    `json:"user_id"`
+	Secret           string    `json:"-"` // base32 TOTP secret, hidden from JSON
+	Verified         bool      `json:"verified"`
+	BackupCodeHashes []string  `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// MFAService manages TOTP enrollment, verification and the login challenge
+// step. LoginRequest flows that hit an enrolled user return a challenge
+// token which must be exchanged here after the OTP is verified.
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (*MFAEnrollResponse, error)
+	VerifyEnrollment(ctx context.Context, userID, code string) error
+	Disable(ctx context.Context, userID string) error
+	IsEnrolled(ctx context.Context, userID string) (bool, error)
+
+	// CreateChallenge issues the short-lived token Login returns when MFA
+	// is required
+	CreateChallenge(ctx context.Context, userID string) (string, error)
+
+	// VerifyChallenge exchanges a challenge token plus a valid OTP (or
+	// backup code) for the user ID, consuming the challenge
+	VerifyChallenge(ctx context.Context, challengeToken, code string) (string, error)
+}
+
+// MFAEnrollResponse carries the provisioning data for an authenticator app
+type MFAEnrollResponse struct {
+	Secret      string   `json:"secret"`
+	OTPAuthURL  string   `json:"otpauth_url"`
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// mfaService implements MFAService with in-memory enrollment storage; a
+// database-backed store can replace the map without changing callers
+type mfaService struct {
+	config *config.Config
+
+	mu          sync.Mutex
+	enrollments map[string]*MFAEnrollment
+}
+
+// NewMFAService creates a new MFA service
+func NewMFAService(config *config.Config) MFAService {
+	return &mfaService{
+		config:      config,
+		enrollments: make(map[string]*MFAEnrollment),
+	}
+}
+
+// Enroll generates a TOTP secret and backup codes for the user. The
+// enrollment only takes effect once VerifyEnrollment confirms the user's
+// authenticator produces matching codes.
+func (s *mfaService) Enroll(ctx context.Context, userID string) (*MFAEnrollResponse, error) {
+	secretBytes := make([]byte, mfaSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate MFA secret: %w", err)
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secretBytes)
+
+	backupCodes := make([]string, mfaBackupCodeCount)
+	hashes := make([]string, mfaBackupCodeCount)
+	for i := range backupCodes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate backup code: %w", err)
+		}
+		backupCodes[i] = hex.EncodeToString(buf)
+		hash, err := bcrypt.GenerateFromPassword([]byte(backupCodes[i]), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash backup code: %w", err)
+		}
+		hashes[i] = string(hash)
+	}
+
+	s.mu.Lock()
+	s.enrollments[userID] = &MFAEnrollment{
+		UserID:           userID,
+		Secret:           secret,
+		BackupCodeHashes: hashes,
+		CreatedAt:        time.Now(),
+	}
+	s.mu.Unlock()
+
+	issuer := s.config.Security.JWT.Issuer
+	return &MFAEnrollResponse{
+		Secret:      secret,
+		OTPAuthURL:  fmt.Sprintf("otpauth://totp/%s:%s?secret=%s&issuer=%s", issuer, userID, secret, issuer),
+		BackupCodes: backupCodes,
+	}, nil
+}
+
+// VerifyEnrollment confirms the user's authenticator against the pending
+// secret and activates MFA for the account
+func (s *mfaService) VerifyEnrollment(ctx context.Context, userID, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, exists := s.enrollments[userID]
+	if !exists {
+		return domain.NewAuthError(domain.AuthErrMFANotEnrolled, "No MFA enrollment in progress")
+	}
+	if !validateTOTP(enrollment.Secret, code, time.Now()) {
+		return domain.NewAuthError(domain.AuthErrInvalidMFACode, "Invalid verification code")
+	}
+
+	enrollment.Verified = true
+	return nil
+}
+
+// Disable removes the user's MFA enrollment
+func (s *mfaService) Disable(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.enrollments, userID)
+	return nil
+}
+
+// IsEnrolled reports whether the user has verified MFA
+func (s *mfaService) IsEnrolled(ctx context.Context, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, exists := s.enrollments[userID]
+	return exists && enrollment.Verified, nil
+}
+
+// CreateChallenge issues a signed, short-lived challenge token for the
+// mfa_required login step
+func (s *mfaService) CreateChallenge(ctx context.Context, userID string) (string, error) {
+	expires := time.Now().Add(mfaChallengeTTL).Unix()
+	payload := fmt.Sprintf("%s|%d", userID, expires)
+	return payload + "|" + s.signChallenge(payload), nil
+}
+
+// VerifyChallenge validates the challenge token and the OTP (or a backup
+// code), returning the user ID for token issuance
+func (s *mfaService) VerifyChallenge(ctx context.Context, challengeToken, code string) (string, error) {
+	parts := strings.Split(challengeToken, "|")
+	if len(parts) != 3 {
+		return "", domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid challenge token")
+	}
+	payload := parts[0] + "|" + parts[1]
+	if !hmac.Equal([]byte(s.signChallenge(payload)), []byte(parts[2])) {
+		return "", domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid challenge token")
+	}
+
+	var expires int64
+	if _, err := fmt.Sscanf(parts[1], "%d", &expires); err != nil || time.Now().Unix() > expires {
+		return "", domain.NewAuthError(domain.AuthErrExpiredToken, "Challenge token expired")
+	}
+	userID := parts[0]
+
+	s.mu.Lock()
+	enrollment, exists := s.enrollments[userID]
+	s.mu.Unlock()
+	if !exists || !enrollment.Verified {
+		return "", domain.NewAuthError(domain.AuthErrMFANotEnrolled, "MFA is not enrolled")
+	}
+
+	if validateTOTP(enrollment.Secret, code, time.Now()) {
+		return userID, nil
+	}
+	if s.consumeBackupCode(enrollment, code) {
+		return userID, nil
+	}
+
+	return "", domain.NewAuthError(domain.AuthErrInvalidMFACode, "Invalid verification code")
+}
+
+// consumeBackupCode checks the code against the unused backup codes and
+// burns it on a match
+func (s *mfaService) consumeBackupCode(enrollment *MFAEnrollment, code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, hash := range enrollment.BackupCodeHashes {
+		if hash == "" {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil {
+			enrollment.BackupCodeHashes[i] = ""
+			return true
+		}
+	}
+	return false
+}
+
+// signChallenge signs challenge payloads with the JWT secret
+func (s *mfaService) signChallenge(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Security.JWT.SecretKey))
+	mac.Write([]byte("mfa-challenge:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateTOTP checks an RFC 6238 code against the secret, allowing one
+// period of clock skew in both directions
+func validateTOTP(secret, code string, at time.Time) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return false
+	}
+
+	counter := uint64(at.Unix()) / uint64(totpPeriod.Seconds())
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		if hotp(key, counter+uint64(offset)) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hotp computes an RFC 4226 HMAC-based one-time password
+func hotp(key []byte, counter uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
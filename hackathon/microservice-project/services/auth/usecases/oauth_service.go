@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+	"github.com/elotusteam/microservice-project/shared/utils"
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+// OAuthService runs the OAuth2/OIDC authorization-code flow with state and
+// PKCE handling, linking provider identities to existing users by email.
+type OAuthService interface {
+	// BeginFlow returns the provider's authorization URL for a new flow
+	BeginFlow(ctx context.Context, provider string) (string, error)
+
+	// HandleCallback exchanges the authorization code, resolves the
+	// provider identity and returns the linked (or newly created) user
+	HandleCallback(ctx context.Context, provider, code, state string) (*sharedDomain.User, error)
+}
+
+// oauthFlowState tracks one in-flight authorization flow
+type oauthFlowState struct {
+	provider     string
+	codeVerifier string
+	expiresAt    time.Time
+}
+
+// oauthService implements OAuthService
+type oauthService struct {
+	config   *config.Config
+	userRepo domain.UserRepository
+	client   *http.Client
+
+	mu     sync.Mutex
+	states map[string]*oauthFlowState
+}
+
+// NewOAuthService creates a new OAuth service
+func NewOAuthService(config *config.Config, userRepo domain.UserRepository) OAuthService {
+	return &oauthService{
+		config:   config,
+		userRepo: userRepo,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		states:   make(map[string]*oauthFlowState),
+	}
+}
+
+// providerConfig resolves and validates a configured provider
+func (s *oauthService) providerConfig(provider string) (*config.OAuthProviderConfig, error) {
+	if !s.config.Security.OAuth.Enabled {
+		return nil, fmt.Errorf("OAuth login is disabled")
+	}
+	cfg, ok := s.config.Security.OAuth.Providers[provider]
+	if !ok || cfg.ClientID == "" {
+		return nil, fmt.Errorf("OAuth provider %q is not configured", provider)
+	}
+	return &cfg, nil
+}
+
+// BeginFlow builds the authorization URL with a fresh state and PKCE pair
+func (s *oauthService) BeginFlow(ctx context.Context, provider string) (string, error) {
+	cfg, err := s.providerConfig(provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.states[state] = &oauthFlowState{
+		provider:     provider,
+		codeVerifier: codeVerifier,
+		expiresAt:    time.Now().Add(oauthStateTTL),
+	}
+	s.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	params := url.Values{}
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", s.config.Security.OAuth.RedirectURL)
+	params.Set("response_type", "code")
+	params.Set("scope", strings.Join(cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	return cfg.AuthURL + "?" + params.Encode(), nil
+}
+
+// HandleCallback validates the state, exchanges the code for an access token
+// and links the provider identity to a user by email
+func (s *oauthService) HandleCallback(ctx context.Context, provider, code, state string) (*sharedDomain.User, error) {
+	s.mu.Lock()
+	flow, exists := s.states[state]
+	delete(s.states, state)
+	s.mu.Unlock()
+
+	if !exists || flow.provider != provider {
+		return nil, domain.NewAuthError(domain.AuthErrInvalidToken, "Invalid OAuth state")
+	}
+	if time.Now().After(flow.expiresAt) {
+		return nil, domain.NewAuthError(domain.AuthErrExpiredToken, "OAuth state expired")
+	}
+
+	cfg, err := s.providerConfig(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.exchangeCode(ctx, cfg, code, flow.codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	email, name, err := s.fetchUserInfo(ctx, cfg, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if email == "" {
+		return nil, fmt.Errorf("provider did not return an email address")
+	}
+
+	// Link to an existing account by email, or provision a new one
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil && user != nil {
+		return user, nil
+	}
+
+	user = &sharedDomain.User{
+		ID:        utils.GenerateID(),
+		Username:  email,
+		Email:     email,
+		FirstName: name,
+		Role:      sharedDomain.UserRoleUser,
+		Status:    sharedDomain.UserStatusActive,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Metadata: map[string]interface{}{
+			"oauth_provider": provider,
+		},
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to provision OAuth user: %w", err)
+	}
+
+	return user, nil
+}
+
+// exchangeCode swaps the authorization code for an access token
+func (s *oauthService) exchangeCode(ctx context.Context, cfg *config.OAuthProviderConfig, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	form.Set("redirect_uri", s.config.Security.OAuth.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token exchange failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", fmt.Errorf("token response missing access token")
+	}
+
+	return payload.AccessToken, nil
+}
+
+// fetchUserInfo retrieves the provider identity's email and display name
+func (s *oauthService) fetchUserInfo(ctx context.Context, cfg *config.OAuthProviderConfig, accessToken string) (email, name string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.UserInfoURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("userinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("userinfo endpoint returned %s", resp.Status)
+	}
+
+	var payload struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", "", fmt.Errorf("invalid userinfo response: %w", err)
+	}
+
+	return payload.Email, payload.Name, nil
+}
+
+// randomToken returns a base64url random value for states and verifiers
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -89,6 +90,16 @@ func (s *passwordService) ValidatePasswordStrength(password string) []string {
 		errors = append(errors, "password must contain at least one special character")
 	}
 
+	// Rule-compliant passwords can still be guessable; reject passwords
+	// scoring below the configured minimum strength
+	if minScore := s.config.Security.Password.MinStrengthScore; minScore > 0 {
+		score, guidance := utils.PasswordStrengthScore(password)
+		if score < minScore {
+			errors = append(errors, fmt.Sprintf("password is too weak (score %d of minimum %d): %s",
+				score, minScore, strings.Join(guidance, "; ")))
+		}
+	}
+
 	return errors
 }
 
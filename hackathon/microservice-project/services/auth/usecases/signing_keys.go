@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// SigningKeyManager holds the RSA keys used for RS256 token signing. The
+// active key signs new tokens (its kid goes in the JWT header); rotated-out
+// keys stay available for verification so outstanding tokens remain valid
+// until they expire naturally.
+type SigningKeyManager struct {
+	mu       sync.RWMutex
+	activeID string
+	active   *rsa.PrivateKey
+	previous map[string]*rsa.PublicKey
+}
+
+// NewSigningKeyManager loads the signing key from privateKeyPath (PEM) when
+// configured, otherwise generates a fresh 2048-bit key at startup.
+func NewSigningKeyManager(privateKeyPath string) (*SigningKeyManager, error) {
+	m := &SigningKeyManager{
+		previous: make(map[string]*rsa.PublicKey),
+	}
+
+	if privateKeyPath != "" {
+		key, err := loadRSAPrivateKey(privateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		m.active = key
+	} else {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		m.active = key
+	}
+
+	m.activeID = newKeyID()
+	return m, nil
+}
+
+// ActiveKey returns the current signing key and its kid
+func (m *SigningKeyManager) ActiveKey() (string, *rsa.PrivateKey) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.activeID, m.active
+}
+
+// PublicKey resolves the verification key for a kid, covering both the
+// active key and previously rotated-out keys
+func (m *SigningKeyManager) PublicKey(kid string) (*rsa.PublicKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if kid == m.activeID {
+		return &m.active.PublicKey, true
+	}
+	key, ok := m.previous[kid]
+	return key, ok
+}
+
+// Rotate generates a new signing key. The old public key is retained for
+// verification so tokens signed before the rotation stay valid.
+func (m *SigningKeyManager) Rotate() (string, error) {
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.previous[m.activeID] = &m.active.PublicKey
+	m.active = newKey
+	m.activeID = newKeyID()
+	return m.activeID, nil
+}
+
+// JWKS returns the JSON Web Key Set covering every verification key
+func (m *SigningKeyManager) JWKS() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := []map[string]interface{}{jwkFor(m.activeID, &m.active.PublicKey)}
+	for kid, key := range m.previous {
+		keys = append(keys, jwkFor(kid, key))
+	}
+
+	return map[string]interface{}{"keys": keys}
+}
+
+// jwkFor encodes one RSA public key as a JWK entry
+func jwkFor(kid string, key *rsa.PublicKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"kid": kid,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// loadRSAPrivateKey reads a PEM-encoded RSA private key
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in private key file")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return key, nil
+}
+
+// newKeyID derives a time-based key identifier
+func newKeyID() string {
+	return fmt.Sprintf("key-%d", time.Now().UnixNano())
+}
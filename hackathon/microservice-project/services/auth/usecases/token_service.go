@@ -2,7 +2,10 @@ package usecases
 
 import (
 	"context"
+	"crypto"
 	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
@@ -19,13 +22,44 @@ import (
 // tokenService implements the TokenService interface
 type tokenService struct {
 	config *config.Config
+	keys   *SigningKeyManager
 }
 
-// NewTokenService creates a new token service
-func NewTokenService(config *config.Config) TokenService {
-	return &tokenService{
-		config: config,
+// NewTokenService creates a new token service. With the RS256 algorithm
+// configured, tokens are signed asymmetrically with kid-based key selection
+// and the key set is published at /.well-known/jwks.json. A misconfigured
+// RS256 key is a startup error: silently falling back to HS256 would defeat
+// the configured signing scheme.
+func NewTokenService(config *config.Config) (TokenService, error) {
+	service := &tokenService{config: config}
+
+	if config.Security.JWT.Algorithm == "RS256" {
+		keys, err := NewSigningKeyManager(config.Security.JWT.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize RS256 signing keys: %w", err)
+		}
+		service.keys = keys
+	}
+
+	return service, nil
+}
+
+// JWKS returns the published verification key set (RS256 mode only)
+func (s *tokenService) JWKS(ctx context.Context) (map[string]interface{}, error) {
+	if s.keys == nil {
+		return nil, fmt.Errorf("JWKS is only available with RS256 signing")
 	}
+	return s.keys.JWKS(), nil
+}
+
+// RotateSigningKey swaps in a fresh RS256 signing key. Outstanding tokens
+// stay valid because the old public key remains in the JWKS for
+// verification until those tokens expire.
+func (s *tokenService) RotateSigningKey(ctx context.Context) (string, error) {
+	if s.keys == nil {
+		return "", fmt.Errorf("key rotation is only available with RS256 signing")
+	}
+	return s.keys.Rotate()
 }
 
 // GenerateTokenPair generates both access and refresh tokens
@@ -103,6 +137,31 @@ func (s *tokenService) GenerateRefreshToken(ctx context.Context, user *sharedDom
 	return s.signToken(claims)
 }
 
+// GenerateServiceToken issues a scoped machine token for an internal
+// service via the client-credentials grant. The audience claim carries the
+// configured service audience so consumers can verify it.
+func (s *tokenService) GenerateServiceToken(ctx context.Context, clientID string, scopes []string) (string, error) {
+	now := time.Now()
+	tokenID := utils.GenerateID()
+
+	claims := &domain.JWTClaims{
+		UserID:    clientID,
+		Username:  clientID,
+		TokenID:   tokenID,
+		TokenType: "service",
+		Scope:     strings.Join(scopes, " "),
+		ID:        tokenID,
+		Subject:   clientID,
+		Audience:  []string{s.config.Security.JWT.Audience},
+		Issuer:    s.config.Security.JWT.Issuer,
+		IssuedAt:  domain.NewNumericDate(now),
+		NotBefore: domain.NewNumericDate(now),
+		ExpiresAt: domain.NewNumericDate(now.Add(ServiceTokenTTL)),
+	}
+
+	return s.signToken(claims)
+}
+
 // ValidateAccessToken validates an access token and returns claims
 func (s *tokenService) ValidateAccessToken(ctx context.Context, tokenString string) (*domain.JWTClaims, error) {
 	claims, err := s.parseAndValidateToken(tokenString)
@@ -195,13 +254,18 @@ func (s *tokenService) GetTokenType(ctx context.Context, tokenString string) (st
 	return claims.TokenType, nil
 }
 
-// signToken signs a JWT token with HMAC-SHA256
+// signToken signs a JWT, using RS256 with the active key when asymmetric
+// signing is configured and HMAC-SHA256 otherwise
 func (s *tokenService) signToken(claims *domain.JWTClaims) (string, error) {
-	// Create header
 	header := map[string]interface{}{
 		"typ": "JWT",
 		"alg": "HS256",
 	}
+	if s.keys != nil {
+		kid, _ := s.keys.ActiveKey()
+		header["alg"] = "RS256"
+		header["kid"] = kid
+	}
 
 	// Encode header
 	headerBytes, err := json.Marshal(header)
@@ -219,6 +283,17 @@ func (s *tokenService) signToken(claims *domain.JWTClaims) (string, error) {
 
 	// Create signature
 	message := headerEncoded + "." + payloadEncoded
+
+	if s.keys != nil {
+		_, key := s.keys.ActiveKey()
+		digest := sha256.Sum256([]byte(message))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", fmt.Errorf("RS256 signing failed: %w", err)
+		}
+		return message + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+	}
+
 	h := hmac.New(sha256.New, []byte(s.config.Security.JWT.SecretKey))
 	h.Write([]byte(message))
 	signature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
@@ -226,21 +301,62 @@ func (s *tokenService) signToken(claims *domain.JWTClaims) (string, error) {
 	return message + "." + signature, nil
 }
 
-// parseToken parses a JWT token
+// parseToken parses a JWT token, selecting the verification key via the
+// header's alg and kid fields
 func (s *tokenService) parseToken(tokenString string) (*domain.JWTClaims, error) {
 	parts := strings.Split(tokenString, ".")
 	if len(parts) != 3 {
 		return nil, fmt.Errorf("invalid token format")
 	}
 
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("header decoding failed: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("header unmarshaling failed: %w", err)
+	}
+
 	// Verify signature
 	message := parts[0] + "." + parts[1]
-	h := hmac.New(sha256.New, []byte(s.config.Security.JWT.SecretKey))
-	h.Write([]byte(message))
-	expectedSignature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
 
-	if parts[2] != expectedSignature {
-		return nil, fmt.Errorf("invalid token signature")
+	// With RS256 configured, only RS256 tokens are acceptable: honoring an
+	// attacker-chosen HS256 header would downgrade verification to the
+	// static shared secret and defeat key rotation.
+	if s.keys != nil && header.Alg != "RS256" {
+		return nil, fmt.Errorf("token signed with disallowed algorithm %q", header.Alg)
+	}
+
+	switch header.Alg {
+	case "RS256":
+		if s.keys == nil {
+			return nil, fmt.Errorf("RS256 token presented but asymmetric signing is not configured")
+		}
+		publicKey, ok := s.keys.PublicKey(header.Kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", header.Kid)
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("signature decoding failed: %w", err)
+		}
+		digest := sha256.Sum256([]byte(message))
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, fmt.Errorf("invalid token signature")
+		}
+	case "HS256":
+		h := hmac.New(sha256.New, []byte(s.config.Security.JWT.SecretKey))
+		h.Write([]byte(message))
+		expectedSignature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+		if !hmac.Equal([]byte(parts[2]), []byte(expectedSignature)) {
+			return nil, fmt.Errorf("invalid token signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
 	}
 
 	// Decode payload
@@ -0,0 +1,332 @@
+package usecases
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+)
+
+// WebAuthn ceremony parameters
+const (
+	webauthnChallengeSize = 32
+	webauthnChallengeTTL  = 5 * time.Minute
+)
+
+// WebAuthnCredential is a registered FIDO2/passkey credential. The public
+// key is stored as an uncompressed P-256 point (ES256 is the only supported
+// algorithm for now).
+type WebAuthnCredential struct {
+	ID         string    `json:"id"` // base64url credential ID
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	PublicKey  []byte    `json:"-"`
+	SignCount  uint32    `json:"sign_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// WebAuthnCredentialRepository stores registered passkey credentials
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *WebAuthnCredential) error
+	GetByID(ctx context.Context, credentialID string) (*WebAuthnCredential, error)
+	GetByUserID(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+	Update(ctx context.Context, credential *WebAuthnCredential) error
+	Delete(ctx context.Context, credentialID string) error
+}
+
+// InMemoryWebAuthnCredentialRepository is the default credential store until
+// a database-backed one is wired
+type InMemoryWebAuthnCredentialRepository struct {
+	mu          sync.RWMutex
+	credentials map[string]*WebAuthnCredential
+}
+
+// NewInMemoryWebAuthnCredentialRepository creates an in-memory store
+func NewInMemoryWebAuthnCredentialRepository() WebAuthnCredentialRepository {
+	return &InMemoryWebAuthnCredentialRepository{
+		credentials: make(map[string]*WebAuthnCredential),
+	}
+}
+
+func (r *InMemoryWebAuthnCredentialRepository) Create(ctx context.Context, credential *WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.credentials[credential.ID]; exists {
+		return fmt.Errorf("credential already registered")
+	}
+	r.credentials[credential.ID] = credential
+	return nil
+}
+
+func (r *InMemoryWebAuthnCredentialRepository) GetByID(ctx context.Context, credentialID string) (*WebAuthnCredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	credential, exists := r.credentials[credentialID]
+	if !exists {
+		return nil, fmt.Errorf("credential not found")
+	}
+	return credential, nil
+}
+
+func (r *InMemoryWebAuthnCredentialRepository) GetByUserID(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var credentials []*WebAuthnCredential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, credential)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *InMemoryWebAuthnCredentialRepository) Update(ctx context.Context, credential *WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.credentials[credential.ID] = credential
+	return nil
+}
+
+func (r *InMemoryWebAuthnCredentialRepository) Delete(ctx context.Context, credentialID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.credentials, credentialID)
+	return nil
+}
+
+// WebAuthnService runs the passkey registration and login ceremonies
+type WebAuthnService interface {
+	BeginRegistration(ctx context.Context, userID string) (*WebAuthnChallenge, error)
+	FinishRegistration(ctx context.Context, userID string, req *WebAuthnRegistrationRequest) (*WebAuthnCredential, error)
+	BeginLogin(ctx context.Context, userID string) (*WebAuthnChallenge, error)
+	FinishLogin(ctx context.Context, req *WebAuthnAssertionRequest) (string, error)
+	ListCredentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+	DeleteCredential(ctx context.Context, userID, credentialID string) error
+}
+
+// WebAuthnChallenge is the server-generated challenge for a ceremony
+type WebAuthnChallenge struct {
+	Challenge string `json:"challenge"` // base64url
+	RPID      string `json:"rp_id"`
+	Timeout   int64  `json:"timeout_ms"`
+}
+
+// WebAuthnRegistrationRequest finishes a registration ceremony. The public
+// key is the credential's uncompressed P-256 point (ES256); attestation
+// format "none" is assumed.
+type WebAuthnRegistrationRequest struct {
+	CredentialID   string `json:"credential_id" binding:"required"`
+	PublicKey      string `json:"public_key" binding:"required"` // base64url uncompressed point
+	Challenge      string `json:"challenge" binding:"required"`
+	CredentialName string `json:"credential_name"`
+}
+
+// WebAuthnAssertionRequest finishes a login ceremony
+type WebAuthnAssertionRequest struct {
+	CredentialID      string `json:"credential_id" binding:"required"`
+	Challenge         string `json:"challenge" binding:"required"`
+	AuthenticatorData string `json:"authenticator_data" binding:"required"` // base64url
+	ClientDataJSON    string `json:"client_data_json" binding:"required"`   // base64url
+	Signature         string `json:"signature" binding:"required"`          // base64url ASN.1 DER
+	SignCount         uint32 `json:"sign_count"`
+}
+
+// webauthnService implements WebAuthnService
+type webauthnService struct {
+	config      *config.Config
+	credentials WebAuthnCredentialRepository
+
+	mu         sync.Mutex
+	challenges map[string]time.Time // issued challenge -> expiry
+}
+
+// NewWebAuthnService creates a new WebAuthn service
+func NewWebAuthnService(config *config.Config, credentials WebAuthnCredentialRepository) WebAuthnService {
+	return &webauthnService{
+		config:      config,
+		credentials: credentials,
+		challenges:  make(map[string]time.Time),
+	}
+}
+
+// newChallenge issues and remembers a ceremony challenge
+func (s *webauthnService) newChallenge() (*WebAuthnChallenge, error) {
+	buf := make([]byte, webauthnChallengeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(buf)
+
+	s.mu.Lock()
+	s.challenges[challenge] = time.Now().Add(webauthnChallengeTTL)
+	s.mu.Unlock()
+
+	return &WebAuthnChallenge{
+		Challenge: challenge,
+		RPID:      s.config.Security.JWT.Issuer,
+		Timeout:   webauthnChallengeTTL.Milliseconds(),
+	}, nil
+}
+
+// consumeChallenge validates and burns an issued challenge
+func (s *webauthnService) consumeChallenge(challenge string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiry, exists := s.challenges[challenge]
+	if !exists {
+		return domain.NewAuthError(domain.AuthErrInvalidToken, "Unknown WebAuthn challenge")
+	}
+	delete(s.challenges, challenge)
+	if time.Now().After(expiry) {
+		return domain.NewAuthError(domain.AuthErrExpiredToken, "WebAuthn challenge expired")
+	}
+	return nil
+}
+
+// BeginRegistration starts a credential registration ceremony
+func (s *webauthnService) BeginRegistration(ctx context.Context, userID string) (*WebAuthnChallenge, error) {
+	return s.newChallenge()
+}
+
+// FinishRegistration verifies the ceremony response and stores the credential
+func (s *webauthnService) FinishRegistration(ctx context.Context, userID string, req *WebAuthnRegistrationRequest) (*WebAuthnCredential, error) {
+	if err := s.consumeChallenge(req.Challenge); err != nil {
+		return nil, err
+	}
+
+	publicKey, err := base64.RawURLEncoding.DecodeString(req.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding")
+	}
+	if _, err := parseP256PublicKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	credential := &WebAuthnCredential{
+		ID:        req.CredentialID,
+		UserID:    userID,
+		Name:      req.CredentialName,
+		PublicKey: publicKey,
+		CreatedAt: time.Now(),
+	}
+	if credential.Name == "" {
+		credential.Name = "passkey"
+	}
+
+	if err := s.credentials.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// BeginLogin starts an assertion ceremony
+func (s *webauthnService) BeginLogin(ctx context.Context, userID string) (*WebAuthnChallenge, error) {
+	return s.newChallenge()
+}
+
+// FinishLogin verifies the assertion and returns the credential's user ID.
+// The signature covers authenticatorData || SHA-256(clientDataJSON) per the
+// WebAuthn spec, verified against the stored ES256 public key.
+func (s *webauthnService) FinishLogin(ctx context.Context, req *WebAuthnAssertionRequest) (string, error) {
+	if err := s.consumeChallenge(req.Challenge); err != nil {
+		return "", err
+	}
+
+	credential, err := s.credentials.GetByID(ctx, req.CredentialID)
+	if err != nil {
+		return "", domain.NewAuthError(domain.AuthErrInvalidCredentials, "Unknown credential")
+	}
+
+	authenticatorData, err := base64.RawURLEncoding.DecodeString(req.AuthenticatorData)
+	if err != nil {
+		return "", fmt.Errorf("invalid authenticator data encoding")
+	}
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(req.ClientDataJSON)
+	if err != nil {
+		return "", fmt.Errorf("invalid client data encoding")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(req.Signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature encoding")
+	}
+
+	// The challenge inside clientDataJSON must match the ceremony
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return "", fmt.Errorf("invalid client data")
+	}
+	if clientData.Type != "webauthn.get" || clientData.Challenge != req.Challenge {
+		return "", domain.NewAuthError(domain.AuthErrInvalidCredentials, "Client data mismatch")
+	}
+
+	publicKey, err := parseP256PublicKey(credential.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+	if !ecdsa.VerifyASN1(publicKey, signed[:], signature) {
+		return "", domain.NewAuthError(domain.AuthErrInvalidCredentials, "Assertion signature verification failed")
+	}
+
+	// A non-increasing sign count indicates a cloned authenticator
+	if req.SignCount != 0 && req.SignCount <= credential.SignCount {
+		return "", domain.NewAuthError(domain.AuthErrInvalidCredentials, "Authenticator sign count regression")
+	}
+
+	credential.SignCount = req.SignCount
+	credential.LastUsedAt = time.Now()
+	if err := s.credentials.Update(ctx, credential); err != nil {
+		return "", err
+	}
+
+	return credential.UserID, nil
+}
+
+// ListCredentials lists the user's registered passkeys
+func (s *webauthnService) ListCredentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	return s.credentials.GetByUserID(ctx, userID)
+}
+
+// DeleteCredential removes one of the user's passkeys
+func (s *webauthnService) DeleteCredential(ctx context.Context, userID, credentialID string) error {
+	credential, err := s.credentials.GetByID(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+	if credential.UserID != userID {
+		return domain.NewAuthError(domain.AuthErrInsufficientPerms, "Credential belongs to a different user")
+	}
+	return s.credentials.Delete(ctx, credentialID)
+}
+
+// parseP256PublicKey decodes an uncompressed P-256 public key point
+func parseP256PublicKey(raw []byte) (*ecdsa.PublicKey, error) {
+	if len(raw) != 65 || raw[0] != 0x04 {
+		return nil, fmt.Errorf("public key must be an uncompressed P-256 point")
+	}
+
+	x := new(big.Int).SetBytes(raw[1:33])
+	y := new(big.Int).SetBytes(raw[33:65])
+	if !elliptic.P256().IsOnCurve(x, y) {
+		return nil, fmt.Errorf("public key is not on the P-256 curve")
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
@@ -144,3 +144,81 @@ func (c *NotificationClient) SendStorageQuotaNotification(ctx context.Context, u
 
 	return nil
 }
+
+// SendSecurityAlertNotificationRequest represents the request payload
+type SendSecurityAlertNotificationRequest struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Message string    `json:"message"`
+}
+
+// SendSecurityAlertNotification sends a security alert to a user
+func (c *NotificationClient) SendSecurityAlertNotification(ctx context.Context, userID uuid.UUID, message string) error {
+	req := SendSecurityAlertNotificationRequest{
+		UserID:  userID,
+		Message: message,
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/notifications/security-alert", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("send security alert notification failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SendFileExpiryWarningNotificationRequest represents the request payload
+type SendFileExpiryWarningNotificationRequest struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Filename  string    `json:"filename"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SendFileExpiryWarningNotification warns a user that a file expires soon
+func (c *NotificationClient) SendFileExpiryWarningNotification(ctx context.Context, userID uuid.UUID, filename string, expiresAt time.Time) error {
+	req := SendFileExpiryWarningNotificationRequest{
+		UserID:    userID,
+		Filename:  filename,
+		ExpiresAt: expiresAt,
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/notifications/file-expiring", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("send file expiry warning failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -7,31 +7,35 @@ import (
 
 // File represents a file entity in the system
 type File struct {
-	ID           uuid.UUID              `json:"id" db:"id"`
-	UserID       uuid.UUID              `json:"user_id" db:"user_id"`
-	Filename     string                 `json:"filename" db:"filename"`
-	OriginalName string                 `json:"original_name" db:"original_name"`
-	MimeType     string                 `json:"mime_type" db:"mime_type"`
-	Size         int64                  `json:"size" db:"size"`
-	Path         string                 `json:"path" db:"path"`
-	URL          string                 `json:"url" db:"url"`
-	Checksum     string                 `json:"checksum" db:"checksum"`
-	Status       FileStatus             `json:"status" db:"status"`
-	Metadata     map[string]interface{} `json:"metadata" db:"metadata"`
-	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at" db:"updated_at"`
-	DeletedAt    *time.Time             `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID             uuid.UUID              `json:"id" db:"id"`
+	UserID         uuid.UUID              `json:"user_id" db:"user_id"`
+	Filename       string                 `json:"filename" db:"filename"`
+	OriginalName   string                 `json:"original_name" db:"original_name"`
+	MimeType       string                 `json:"mime_type" db:"mime_type"`
+	Size           int64                  `json:"size" db:"size"`
+	Path           string                 `json:"path" db:"path"`
+	URL            string                 `json:"url" db:"url"`
+	Checksum       string                 `json:"checksum" db:"checksum"`
+	Status         FileStatus             `json:"status" db:"status"`
+	Metadata       map[string]interface{} `json:"metadata" db:"metadata"`
+	Tags           []string               `json:"tags,omitempty" db:"tags"`
+	PerceptualHash string                 `json:"perceptual_hash,omitempty" db:"perceptual_hash"` // 64-bit pHash (hex) for images
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
+	ExpiresAt      *time.Time             `json:"expires_at,omitempty" db:"expires_at"`
+	DeletedAt      *time.Time             `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // FileStatus represents the status of a file
 type FileStatus string
 
 const (
-	FileStatusUploading  FileStatus = "uploading"
-	FileStatusProcessing FileStatus = "processing"
-	FileStatusReady      FileStatus = "ready"
-	FileStatusError      FileStatus = "error"
-	FileStatusDeleted    FileStatus = "deleted"
+	FileStatusUploading   FileStatus = "uploading"
+	FileStatusProcessing  FileStatus = "processing"
+	FileStatusReady       FileStatus = "ready"
+	FileStatusError       FileStatus = "error"
+	FileStatusDeleted     FileStatus = "deleted"
+	FileStatusQuarantined FileStatus = "quarantined"
 )
 
 // UploadSession represents an upload session for chunked uploads
@@ -60,15 +64,18 @@ const (
 
 // FileShare represents file sharing permissions
 type FileShare struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	FileID      uuid.UUID  `json:"file_id" db:"file_id"`
-	SharedBy    uuid.UUID  `json:"shared_by" db:"shared_by"`
-	SharedWith  *uuid.UUID `json:"shared_with,omitempty" db:"shared_with"`
-	ShareToken  string     `json:"share_token" db:"share_token"`
-	Permissions []string   `json:"permissions" db:"permissions"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID           uuid.UUID  `json:"id" db:"id"`
+	FileID       uuid.UUID  `json:"file_id" db:"file_id"`
+	SharedBy     uuid.UUID  `json:"shared_by" db:"shared_by"`
+	SharedWith   *uuid.UUID `json:"shared_with,omitempty" db:"shared_with"`
+	ShareToken   string     `json:"share_token" db:"share_token"`
+	PasswordHash string     `json:"-" db:"password_hash"` // Hidden from JSON; empty when not password-protected
+	Permissions  []string   `json:"permissions" db:"permissions"`
+	AccessCount  int64      `json:"access_count" db:"access_count"`
+	LastAccessed *time.Time `json:"last_accessed,omitempty" db:"last_accessed"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // FileVersion represents file versioning
@@ -79,23 +86,25 @@ type FileVersion struct {
 	Path      string    `json:"path" db:"path"`
 	Size      int64     `json:"size" db:"size"`
 	Checksum  string    `json:"checksum" db:"checksum"`
+	Pinned    bool      `json:"pinned" db:"pinned"` // Pinned versions are exempt from retention pruning
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 // ImageVariant represents different image sizes and qualities
 type ImageVariant struct {
-	ID          string             `json:"id" db:"id"`
-	FileID      uuid.UUID          `json:"file_id" db:"file_id"`
-	VariantType string             `json:"variant_type" db:"variant_type"`
-	Width       int                `json:"width" db:"width"`
-	Height      int                `json:"height" db:"height"`
-	Size        int64              `json:"size" db:"size"`
-	Path        string             `json:"path" db:"path"`
-	Format      string             `json:"format" db:"format"`
-	Quality     int                `json:"quality" db:"quality"`
-	Status      ImageVariantStatus `json:"status" db:"status"`
-	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+	ID           string             `json:"id" db:"id"`
+	FileID       uuid.UUID          `json:"file_id" db:"file_id"`
+	VariantType  string             `json:"variant_type" db:"variant_type"`
+	Width        int                `json:"width" db:"width"`
+	Height       int                `json:"height" db:"height"`
+	Size         int64              `json:"size" db:"size"`
+	Path         string             `json:"path" db:"path"`
+	Format       string             `json:"format" db:"format"`
+	Quality      int                `json:"quality" db:"quality"`
+	Status       ImageVariantStatus `json:"status" db:"status"`
+	AttemptCount int                `json:"attempt_count" db:"attempt_count"` // generation attempts so far
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
 }
 
 // ImageVariantStatus represents the status of an image variant
@@ -105,4 +114,19 @@ const (
 	ImageVariantStatusProcessing ImageVariantStatus = "processing"
 	ImageVariantStatusReady      ImageVariantStatus = "ready"
 	ImageVariantStatusError      ImageVariantStatus = "error"
+	// ImageVariantStatusAbandoned is terminal: retries are exhausted
+	ImageVariantStatusAbandoned ImageVariantStatus = "abandoned"
 )
+
+// FilePermission grants a user or group a permission on a file, beyond the
+// owner/share-token model
+type FilePermission struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	FileID     uuid.UUID  `json:"file_id" db:"file_id"`
+	UserID     *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	GroupID    *uuid.UUID `json:"group_id,omitempty" db:"group_id"`
+	Permission string     `json:"permission" db:"permission"` // read, write, delete, share, admin
+	GrantedBy  uuid.UUID  `json:"granted_by" db:"granted_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
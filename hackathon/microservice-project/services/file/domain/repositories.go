@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,6 +11,7 @@ import (
 type FileRepository interface {
 	Create(ctx context.Context, file *File) error
 	GetByID(ctx context.Context, id uuid.UUID) (*File, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*File, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*File, error)
 	Update(ctx context.Context, file *File) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -16,6 +19,26 @@ type FileRepository interface {
 	Search(ctx context.Context, query string, userID uuid.UUID, limit, offset int) ([]*File, error)
 	GetTotalSize(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetFileCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetTagFacets(ctx context.Context, userID uuid.UUID, limit int) ([]*TagFacet, error)
+	ListPaths(ctx context.Context) ([]string, error)
+	GetSizeEvents(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*SizeEvent, error)
+	GetByStatus(ctx context.Context, status FileStatus, limit, offset int) ([]*File, error)
+	GetExpiringSoon(ctx context.Context, userID uuid.UUID, before time.Time, limit int) ([]*File, error)
+	GetMissingChecksums(ctx context.Context, limit int) ([]*File, error)
+}
+
+// SizeEvent captures one file's size with its creation and (optional)
+// deletion timestamps, used to compute storage growth over time
+type SizeEvent struct {
+	Size      int64      `json:"size" db:"size"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// TagFacet is a distinct tag with the number of files carrying it
+type TagFacet struct {
+	Tag   string `json:"tag" db:"tag"`
+	Count int64  `json:"count" db:"count"`
 }
 
 // UploadSessionRepository defines the interface for upload session operations
@@ -26,6 +49,7 @@ type UploadSessionRepository interface {
 	Update(ctx context.Context, session *UploadSession) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteExpired(ctx context.Context) error
+	GetExpired(ctx context.Context, limit int) ([]*UploadSession, error)
 	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*UploadSession, error)
 }
 
@@ -39,6 +63,8 @@ type FileShareRepository interface {
 	GetBySharedWith(ctx context.Context, userID uuid.UUID) ([]*FileShare, error)
 	Update(ctx context.Context, share *FileShare) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByFileID(ctx context.Context, fileID uuid.UUID) (int64, error)
+	DeleteBySharedWith(ctx context.Context, sharedBy, sharedWith uuid.UUID) (int64, error)
 	DeleteExpired(ctx context.Context) error
 }
 
@@ -48,8 +74,10 @@ type FileVersionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*FileVersion, error)
 	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*FileVersion, error)
 	GetLatestByFileID(ctx context.Context, fileID uuid.UUID) (*FileVersion, error)
+	Update(ctx context.Context, version *FileVersion) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByFileID(ctx context.Context, fileID uuid.UUID) error
+	ListPaths(ctx context.Context) ([]string, error)
 }
 
 // ImageVariantRepository defines the interface for image variant operations
@@ -60,6 +88,18 @@ type ImageVariantRepository interface {
 	Update(ctx context.Context, variant *ImageVariant) error
 	Delete(ctx context.Context, id string) error
 	DeleteByFileID(ctx context.Context, fileID uuid.UUID) error
+	ListPaths(ctx context.Context) ([]string, error)
+	GetByStatus(ctx context.Context, status ImageVariantStatus, limit int) ([]*ImageVariant, error)
+}
+
+// FilePermissionRepository defines the interface for file ACL operations
+type FilePermissionRepository interface {
+	Create(ctx context.Context, permission *FilePermission) error
+	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*FilePermission, error)
+	GetByFileAndUser(ctx context.Context, fileID, userID uuid.UUID) ([]*FilePermission, error)
+	GetByFileAndGroup(ctx context.Context, fileID, groupID uuid.UUID) ([]*FilePermission, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByFileID(ctx context.Context, fileID uuid.UUID) error
 }
 
 // RepositoryManager aggregates all file-related repositories
@@ -69,6 +109,7 @@ type RepositoryManager interface {
 	FileShare() FileShareRepository
 	FileVersion() FileVersionRepository
 	ImageVariant() ImageVariantRepository
+	FilePermission() FilePermissionRepository
 	BeginTx(ctx context.Context) (RepositoryManager, error)
 	Commit() error
 	Rollback() error
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/elotusteam/microservice-project/shared/config"
 	"github.com/elotusteam/microservice-project/shared/data"
@@ -42,7 +43,9 @@ func NewPostgreSQLConnection(cfg *config.DatabaseConfig) (data.DatabaseConnectio
 
 // Query executes a query and returns rows
 func (w *PostgreSQLWrapper) Query(ctx context.Context, query string, args ...interface{}) (data.Rows, error) {
+	start := time.Now()
 	rows, err := w.db.QueryContext(ctx, query, args...)
+	data.DefaultSlowQueryRecorder.Record(query, time.Since(start))
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +60,9 @@ func (w *PostgreSQLWrapper) QueryRow(ctx context.Context, query string, args ...
 
 // Exec executes a query without returning rows
 func (w *PostgreSQLWrapper) Exec(ctx context.Context, query string, args ...interface{}) (data.Result, error) {
+	start := time.Now()
 	result, err := w.db.ExecContext(ctx, query, args...)
+	data.DefaultSlowQueryRecorder.Record(query, time.Since(start))
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +84,7 @@ func (w *PostgreSQLWrapper) Begin(ctx context.Context) (data.Transaction, error)
 	if err != nil {
 		return nil, err
 	}
-	return &TransactionWrapper{tx: tx}, nil
+	return &TransactionWrapper{tx: tx, ctx: ctx}, nil
 }
 
 // Ping pings the database
@@ -171,7 +176,8 @@ func (s *StatementWrapper) Close() error {
 
 // TransactionWrapper wraps sql.Tx to implement data.Transaction and data.DatabaseConnection
 type TransactionWrapper struct {
-	tx *sql.Tx
+	tx  *sql.Tx
+	ctx context.Context
 }
 
 func (t *TransactionWrapper) Commit() error {
@@ -182,8 +188,12 @@ func (t *TransactionWrapper) Rollback() error {
 	return t.tx.Rollback()
 }
 
+// Context returns the context the transaction was started with so request
+// cancellation and deadlines propagate to queries issued inside it
 func (t *TransactionWrapper) Context() context.Context {
-	// sql.Tx doesn't have a context method, so we return background
+	if t.ctx != nil {
+		return t.ctx
+	}
 	return context.Background()
 }
 
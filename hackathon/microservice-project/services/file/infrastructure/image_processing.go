@@ -6,9 +6,14 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
@@ -22,6 +27,10 @@ type ImageProcessingService struct {
 	repoManager    fileDomain.RepositoryManager
 	storageService fileUsecases.StorageService
 	config         *config.Config
+
+	// Served-variant usage tracking for demand-based recommendations
+	usageMu      sync.Mutex
+	variantUsage map[string]int64
 }
 
 // NewImageProcessingService creates a new image processing service
@@ -58,6 +67,101 @@ func (s *ImageProcessingService) getVariantConfigs() []ImageVariantConfig {
 	}
 }
 
+// isOnDemandVariant reports whether the variant type is configured for
+// lazy generation instead of eager generation at upload time
+func (s *ImageProcessingService) isOnDemandVariant(variantType string) bool {
+	for _, onDemand := range s.config.ImageProcessing.OnDemandVariants {
+		if onDemand == variantType {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordVariantServed tracks that a variant type was actually served, so the
+// usage report can recommend which configured variants are worth generating
+func (s *ImageProcessingService) RecordVariantServed(variantType string) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	if s.variantUsage == nil {
+		s.variantUsage = make(map[string]int64)
+	}
+	s.variantUsage[variantType]++
+}
+
+// GetVariantUsageReport compares configured variants against observed demand
+// and recommends which to drop, keep, generate on demand, or add
+func (s *ImageProcessingService) GetVariantUsageReport() []*fileUsecases.VariantUsage {
+	s.usageMu.Lock()
+	usage := make(map[string]int64, len(s.variantUsage))
+	for variantType, count := range s.variantUsage {
+		usage[variantType] = count
+	}
+	s.usageMu.Unlock()
+
+	var report []*fileUsecases.VariantUsage
+	configured := make(map[string]bool)
+
+	for _, config := range s.getVariantConfigs() {
+		configured[config.Type] = true
+		entry := &fileUsecases.VariantUsage{
+			VariantType: config.Type,
+			ServedCount: usage[config.Type],
+			Configured:  true,
+			OnDemand:    s.isOnDemandVariant(config.Type),
+		}
+		switch {
+		case entry.ServedCount == 0:
+			entry.Recommendation = "drop"
+		case entry.ServedCount < 10 && !entry.OnDemand:
+			entry.Recommendation = "make-on-demand"
+		default:
+			entry.Recommendation = "keep"
+		}
+		report = append(report, entry)
+	}
+
+	// Requested variant types that aren't configured at all
+	for variantType, count := range usage {
+		if !configured[variantType] {
+			report = append(report, &fileUsecases.VariantUsage{
+				VariantType:    variantType,
+				ServedCount:    count,
+				Recommendation: "add",
+			})
+		}
+	}
+
+	return report
+}
+
+// GenerateVariantOnDemand generates a single (typically rarely-used) variant
+// the first time it is requested
+func (s *ImageProcessingService) GenerateVariantOnDemand(ctx context.Context, fileID uuid.UUID, originalPath, variantType string) error {
+	if !s.isImageFile(originalPath) {
+		return fmt.Errorf("file is not an image: %s", originalPath)
+	}
+
+	for _, config := range s.getVariantConfigs() {
+		if config.Type != variantType {
+			continue
+		}
+
+		originalImage, err := s.loadImage(originalPath)
+		if err != nil {
+			return fmt.Errorf("failed to load original image: %w", err)
+		}
+
+		variant, err := s.generateVariant(ctx, fileID, originalImage, config, originalPath)
+		if err != nil {
+			return fmt.Errorf("failed to generate variant %s: %w", variantType, err)
+		}
+		return s.repoManager.ImageVariant().Create(ctx, variant)
+	}
+
+	return fmt.Errorf("unknown variant type: %s", variantType)
+}
+
 // GenerateVariants generates image variants for a given file
 func (s *ImageProcessingService) GenerateVariants(ctx context.Context, fileID uuid.UUID, originalPath string) error {
 	// Check if the file is an image
@@ -71,13 +175,31 @@ func (s *ImageProcessingService) GenerateVariants(ctx context.Context, fileID uu
 		return fmt.Errorf("failed to load original image: %w", err)
 	}
 
-	// Generate variants
+	// Generate variants, deferring on-demand types until first requested
 	configs := s.getVariantConfigs()
 	for _, config := range configs {
+		if s.isOnDemandVariant(config.Type) {
+			continue
+		}
 		variant, err := s.generateVariant(ctx, fileID, originalImage, config, originalPath)
 		if err != nil {
-			// Log error but continue with other variants
+			// Record the failure so the background retrier can pick it up
 			fmt.Printf("Failed to generate variant %s: %v\n", config.Type, err)
+			failed := &fileDomain.ImageVariant{
+				ID:           fmt.Sprintf("%s_%s", fileID.String(), config.Type),
+				FileID:       fileID,
+				VariantType:  config.Type,
+				Path:         originalPath,
+				Format:       config.Format,
+				Quality:      config.Quality,
+				Status:       fileDomain.ImageVariantStatusError,
+				AttemptCount: 1,
+				CreatedAt:    time.Now(),
+				UpdatedAt:    time.Now(),
+			}
+			if createErr := s.repoManager.ImageVariant().Create(ctx, failed); createErr != nil {
+				fmt.Printf("Failed to record failed variant %s: %v\n", config.Type, createErr)
+			}
 			continue
 		}
 
@@ -114,6 +236,87 @@ func (s *ImageProcessingService) DeleteVariants(ctx context.Context, fileID uuid
 	return s.repoManager.ImageVariant().DeleteByFileID(ctx, fileID)
 }
 
+// ComputePerceptualHash computes a 64-bit DCT-based perceptual hash (pHash)
+// of the image, returned as a hex string. Unlike checksums, perceptually
+// similar images (re-encoded, resized) produce hashes within a small Hamming
+// distance of each other.
+func (s *ImageProcessingService) ComputePerceptualHash(ctx context.Context, content io.Reader) (string, error) {
+	img, _, err := image.Decode(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// Reduce to 32x32 grayscale
+	const size = 32
+	small := s.resizeImage(img, size, size)
+	gray := make([][]float64, size)
+	for y := 0; y < size; y++ {
+		gray[y] = make([]float64, size)
+		for x := 0; x < size; x++ {
+			// Clamp since resizeImage preserves aspect ratio
+			sx, sy := x, y
+			bounds := small.Bounds()
+			if sx >= bounds.Dx() {
+				sx = bounds.Dx() - 1
+			}
+			if sy >= bounds.Dy() {
+				sy = bounds.Dy() - 1
+			}
+			r, g, b, _ := small.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+			gray[y][x] = 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+		}
+	}
+
+	// 2D DCT, keeping the top-left 8x8 low-frequency block
+	const hashSize = 8
+	dct := make([][]float64, hashSize)
+	for u := 0; u < hashSize; u++ {
+		dct[u] = make([]float64, hashSize)
+		for v := 0; v < hashSize; v++ {
+			var sum float64
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += gray[y][x] *
+						math.Cos(float64(2*y+1)*float64(u)*math.Pi/(2*size)) *
+						math.Cos(float64(2*x+1)*float64(v)*math.Pi/(2*size))
+				}
+			}
+			dct[u][v] = sum
+		}
+	}
+
+	// Median of the coefficients, excluding the DC term
+	coefficients := make([]float64, 0, hashSize*hashSize-1)
+	for u := 0; u < hashSize; u++ {
+		for v := 0; v < hashSize; v++ {
+			if u == 0 && v == 0 {
+				continue
+			}
+			coefficients = append(coefficients, dct[u][v])
+		}
+	}
+	sorted := append([]float64(nil), coefficients...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	// Bits above the median form the hash; DC bit is always 0
+	var hash uint64
+	bit := 1
+	for u := 0; u < hashSize; u++ {
+		for v := 0; v < hashSize; v++ {
+			if u == 0 && v == 0 {
+				continue
+			}
+			if dct[u][v] > median {
+				hash |= 1 << uint(64-bit)
+			}
+			bit++
+		}
+	}
+
+	return fmt.Sprintf("%016x", hash), nil
+}
+
 // generateVariant creates a single image variant
 func (s *ImageProcessingService) generateVariant(
 	ctx context.Context,
@@ -261,3 +464,99 @@ func (s *ImageProcessingService) generateVariantPath(originalPath, variantType,
 	variantFilename := fmt.Sprintf("%s_%s.%s", name, variantType, format)
 	return filepath.Join(dir, "variants", variantFilename)
 }
+
+// Variant retry defaults; overridable via environment
+const (
+	DefaultVariantMaxAttempts  = 3
+	DefaultVariantRetryBackoff = time.Minute
+)
+
+func variantMaxAttempts() int {
+	if value := os.Getenv("IMAGE_PROCESSING_VARIANT_MAX_ATTEMPTS"); value != "" {
+		if attempts, err := strconv.Atoi(value); err == nil && attempts > 0 {
+			return attempts
+		}
+	}
+	return DefaultVariantMaxAttempts
+}
+
+func variantRetryBackoff() time.Duration {
+	if value := os.Getenv("IMAGE_PROCESSING_VARIANT_RETRY_BACKOFF"); value != "" {
+		if backoff, err := time.ParseDuration(value); err == nil && backoff > 0 {
+			return backoff
+		}
+	}
+	return DefaultVariantRetryBackoff
+}
+
+// RetryFailedVariants re-attempts generation for variants in the failed
+// state, recording the attempt count and abandoning variants once the
+// configured maximum is exhausted. Returns the number recovered.
+func (s *ImageProcessingService) RetryFailedVariants(ctx context.Context) (int, error) {
+	failed, err := s.repoManager.ImageVariant().GetByStatus(ctx, fileDomain.ImageVariantStatusError, 100)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list failed variants: %w", err)
+	}
+
+	maxAttempts := variantMaxAttempts()
+	recovered := 0
+
+	for _, variant := range failed {
+		if variant.AttemptCount >= maxAttempts {
+			variant.Status = fileDomain.ImageVariantStatusAbandoned
+			variant.UpdatedAt = time.Now()
+			if err := s.repoManager.ImageVariant().Update(ctx, variant); err != nil {
+				fmt.Printf("Failed to abandon variant %s: %v\n", variant.ID, err)
+			}
+			continue
+		}
+
+		variant.AttemptCount++
+		variant.UpdatedAt = time.Now()
+
+		// Persist the attempt before retrying so a crash mid-retry still
+		// counts toward the cap
+		if err := s.repoManager.ImageVariant().Update(ctx, variant); err != nil {
+			fmt.Printf("Failed to update variant attempt count: %v\n", err)
+			continue
+		}
+
+		// Drop the error row first: GenerateVariantOnDemand creates a fresh
+		// ready row under the same ID, which must replace the failed one
+		// rather than collide with it
+		if err := s.repoManager.ImageVariant().Delete(ctx, variant.ID); err != nil {
+			fmt.Printf("Failed to remove failed variant row: %v\n", err)
+			continue
+		}
+
+		if err := s.GenerateVariantOnDemand(ctx, variant.FileID, variant.Path, variant.VariantType); err != nil {
+			// Still failing; restore the error row with its attempt count
+			if createErr := s.repoManager.ImageVariant().Create(ctx, variant); createErr != nil {
+				fmt.Printf("Failed to restore failed variant row: %v\n", createErr)
+			}
+			continue
+		}
+
+		recovered++
+	}
+
+	return recovered, nil
+}
+
+// StartVariantRetrier runs the failed-variant retry loop with backoff until
+// the context is cancelled
+func (s *ImageProcessingService) StartVariantRetrier(ctx context.Context) {
+	ticker := time.NewTicker(variantRetryBackoff())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RetryFailedVariants(ctx); err != nil {
+				fmt.Printf("Variant retry pass failed: %v\n", err)
+			}
+		}
+	}
+}
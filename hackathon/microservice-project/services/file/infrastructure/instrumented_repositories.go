@@ -0,0 +1,398 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
+	"github.com/elotusteam/microservice-project/shared/data"
+	"github.com/google/uuid"
+)
+
+// Instrumented repository decorators. Each wraps the underlying repository
+// and records operation name, duration and outcome as Prometheus metrics via
+// data.ObserveRepositoryOperation, so slow or failing queries are visible
+// without changing any call sites. They are applied in the repository
+// manager's accessor methods.
+
+// instrumentedFileRepository decorates fileDomain.FileRepository with metrics
+type instrumentedFileRepository struct {
+	next fileDomain.FileRepository
+}
+
+func (r *instrumentedFileRepository) Create(ctx context.Context, file *fileDomain.File) error {
+	start := time.Now()
+	err := r.next.Create(ctx, file)
+	data.ObserveRepositoryOperation("file", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedFileRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	data.ObserveRepositoryOperation("file", "GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetByIDs(ctx, ids)
+	data.ObserveRepositoryOperation("file", "GetByIDs", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetByUserID(ctx, userID, limit, offset)
+	data.ObserveRepositoryOperation("file", "GetByUserID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) Update(ctx context.Context, file *fileDomain.File) error {
+	start := time.Now()
+	err := r.next.Update(ctx, file)
+	data.ObserveRepositoryOperation("file", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedFileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	data.ObserveRepositoryOperation("file", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedFileRepository) GetByChecksum(ctx context.Context, checksum string) (*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetByChecksum(ctx, checksum)
+	data.ObserveRepositoryOperation("file", "GetByChecksum", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) Search(ctx context.Context, query string, userID uuid.UUID, limit, offset int) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.Search(ctx, query, userID, limit, offset)
+	data.ObserveRepositoryOperation("file", "Search", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetTotalSize(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	result, err := r.next.GetTotalSize(ctx, userID)
+	data.ObserveRepositoryOperation("file", "GetTotalSize", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetFileCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	result, err := r.next.GetFileCount(ctx, userID)
+	data.ObserveRepositoryOperation("file", "GetFileCount", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetTagFacets(ctx context.Context, userID uuid.UUID, limit int) ([]*fileDomain.TagFacet, error) {
+	start := time.Now()
+	result, err := r.next.GetTagFacets(ctx, userID, limit)
+	data.ObserveRepositoryOperation("file", "GetTagFacets", start, err)
+	return result, err
+}
+
+// instrumentedUploadSessionRepository decorates fileDomain.UploadSessionRepository with metrics
+type instrumentedUploadSessionRepository struct {
+	next fileDomain.UploadSessionRepository
+}
+
+func (r *instrumentedUploadSessionRepository) Create(ctx context.Context, session *fileDomain.UploadSession) error {
+	start := time.Now()
+	err := r.next.Create(ctx, session)
+	data.ObserveRepositoryOperation("upload_session", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedUploadSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.UploadSession, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	data.ObserveRepositoryOperation("upload_session", "GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedUploadSessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*fileDomain.UploadSession, error) {
+	start := time.Now()
+	result, err := r.next.GetByUserID(ctx, userID)
+	data.ObserveRepositoryOperation("upload_session", "GetByUserID", start, err)
+	return result, err
+}
+
+func (r *instrumentedUploadSessionRepository) Update(ctx context.Context, session *fileDomain.UploadSession) error {
+	start := time.Now()
+	err := r.next.Update(ctx, session)
+	data.ObserveRepositoryOperation("upload_session", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedUploadSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	data.ObserveRepositoryOperation("upload_session", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedUploadSessionRepository) DeleteExpired(ctx context.Context) error {
+	start := time.Now()
+	err := r.next.DeleteExpired(ctx)
+	data.ObserveRepositoryOperation("upload_session", "DeleteExpired", start, err)
+	return err
+}
+
+func (r *instrumentedUploadSessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*fileDomain.UploadSession, error) {
+	start := time.Now()
+	result, err := r.next.GetActiveByUserID(ctx, userID)
+	data.ObserveRepositoryOperation("upload_session", "GetActiveByUserID", start, err)
+	return result, err
+}
+
+// instrumentedFileShareRepository decorates fileDomain.FileShareRepository with metrics
+type instrumentedFileShareRepository struct {
+	next fileDomain.FileShareRepository
+}
+
+func (r *instrumentedFileShareRepository) Create(ctx context.Context, share *fileDomain.FileShare) error {
+	start := time.Now()
+	err := r.next.Create(ctx, share)
+	data.ObserveRepositoryOperation("file_share", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedFileShareRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.FileShare, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	data.ObserveRepositoryOperation("file_share", "GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) GetByToken(ctx context.Context, token string) (*fileDomain.FileShare, error) {
+	start := time.Now()
+	result, err := r.next.GetByToken(ctx, token)
+	data.ObserveRepositoryOperation("file_share", "GetByToken", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.FileShare, error) {
+	start := time.Now()
+	result, err := r.next.GetByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("file_share", "GetByFileID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) GetBySharedBy(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error) {
+	start := time.Now()
+	result, err := r.next.GetBySharedBy(ctx, userID)
+	data.ObserveRepositoryOperation("file_share", "GetBySharedBy", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) GetBySharedWith(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error) {
+	start := time.Now()
+	result, err := r.next.GetBySharedWith(ctx, userID)
+	data.ObserveRepositoryOperation("file_share", "GetBySharedWith", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) Update(ctx context.Context, share *fileDomain.FileShare) error {
+	start := time.Now()
+	err := r.next.Update(ctx, share)
+	data.ObserveRepositoryOperation("file_share", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedFileShareRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	data.ObserveRepositoryOperation("file_share", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedFileShareRepository) DeleteExpired(ctx context.Context) error {
+	start := time.Now()
+	err := r.next.DeleteExpired(ctx)
+	data.ObserveRepositoryOperation("file_share", "DeleteExpired", start, err)
+	return err
+}
+
+// instrumentedFileVersionRepository decorates fileDomain.FileVersionRepository with metrics
+type instrumentedFileVersionRepository struct {
+	next fileDomain.FileVersionRepository
+}
+
+func (r *instrumentedFileVersionRepository) Create(ctx context.Context, version *fileDomain.FileVersion) error {
+	start := time.Now()
+	err := r.next.Create(ctx, version)
+	data.ObserveRepositoryOperation("file_version", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedFileVersionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.FileVersion, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	data.ObserveRepositoryOperation("file_version", "GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileVersionRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.FileVersion, error) {
+	start := time.Now()
+	result, err := r.next.GetByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("file_version", "GetByFileID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileVersionRepository) GetLatestByFileID(ctx context.Context, fileID uuid.UUID) (*fileDomain.FileVersion, error) {
+	start := time.Now()
+	result, err := r.next.GetLatestByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("file_version", "GetLatestByFileID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileVersionRepository) Update(ctx context.Context, version *fileDomain.FileVersion) error {
+	start := time.Now()
+	err := r.next.Update(ctx, version)
+	data.ObserveRepositoryOperation("file_version", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedFileVersionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	data.ObserveRepositoryOperation("file_version", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedFileVersionRepository) DeleteByFileID(ctx context.Context, fileID uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeleteByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("file_version", "DeleteByFileID", start, err)
+	return err
+}
+
+// instrumentedImageVariantRepository decorates fileDomain.ImageVariantRepository with metrics
+type instrumentedImageVariantRepository struct {
+	next fileDomain.ImageVariantRepository
+}
+
+func (r *instrumentedImageVariantRepository) Create(ctx context.Context, variant *fileDomain.ImageVariant) error {
+	start := time.Now()
+	err := r.next.Create(ctx, variant)
+	data.ObserveRepositoryOperation("image_variant", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedImageVariantRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.ImageVariant, error) {
+	start := time.Now()
+	result, err := r.next.GetByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("image_variant", "GetByFileID", start, err)
+	return result, err
+}
+
+func (r *instrumentedImageVariantRepository) GetByID(ctx context.Context, id string) (*fileDomain.ImageVariant, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	data.ObserveRepositoryOperation("image_variant", "GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedImageVariantRepository) Update(ctx context.Context, variant *fileDomain.ImageVariant) error {
+	start := time.Now()
+	err := r.next.Update(ctx, variant)
+	data.ObserveRepositoryOperation("image_variant", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedImageVariantRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	data.ObserveRepositoryOperation("image_variant", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedImageVariantRepository) DeleteByFileID(ctx context.Context, fileID uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeleteByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("image_variant", "DeleteByFileID", start, err)
+	return err
+}
+
+func (r *instrumentedFileRepository) ListPaths(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := r.next.ListPaths(ctx)
+	data.ObserveRepositoryOperation("file", "ListPaths", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileVersionRepository) ListPaths(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := r.next.ListPaths(ctx)
+	data.ObserveRepositoryOperation("file_version", "ListPaths", start, err)
+	return result, err
+}
+
+func (r *instrumentedImageVariantRepository) ListPaths(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := r.next.ListPaths(ctx)
+	data.ObserveRepositoryOperation("image_variant", "ListPaths", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetSizeEvents(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*fileDomain.SizeEvent, error) {
+	startTime := time.Now()
+	result, err := r.next.GetSizeEvents(ctx, userID, start, end)
+	data.ObserveRepositoryOperation("file", "GetSizeEvents", startTime, err)
+	return result, err
+}
+
+func (r *instrumentedUploadSessionRepository) GetExpired(ctx context.Context, limit int) ([]*fileDomain.UploadSession, error) {
+	start := time.Now()
+	result, err := r.next.GetExpired(ctx, limit)
+	data.ObserveRepositoryOperation("upload_session", "GetExpired", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetByStatus(ctx context.Context, status fileDomain.FileStatus, limit, offset int) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetByStatus(ctx, status, limit, offset)
+	data.ObserveRepositoryOperation("file", "GetByStatus", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) DeleteByFileID(ctx context.Context, fileID uuid.UUID) (int64, error) {
+	start := time.Now()
+	result, err := r.next.DeleteByFileID(ctx, fileID)
+	data.ObserveRepositoryOperation("file_share", "DeleteByFileID", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileShareRepository) DeleteBySharedWith(ctx context.Context, sharedBy, sharedWith uuid.UUID) (int64, error) {
+	start := time.Now()
+	result, err := r.next.DeleteBySharedWith(ctx, sharedBy, sharedWith)
+	data.ObserveRepositoryOperation("file_share", "DeleteBySharedWith", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetExpiringSoon(ctx context.Context, userID uuid.UUID, before time.Time, limit int) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetExpiringSoon(ctx, userID, before, limit)
+	data.ObserveRepositoryOperation("file", "GetExpiringSoon", start, err)
+	return result, err
+}
+
+func (r *instrumentedFileRepository) GetMissingChecksums(ctx context.Context, limit int) ([]*fileDomain.File, error) {
+	start := time.Now()
+	result, err := r.next.GetMissingChecksums(ctx, limit)
+	data.ObserveRepositoryOperation("file", "GetMissingChecksums", start, err)
+	return result, err
+}
+
+func (r *instrumentedImageVariantRepository) GetByStatus(ctx context.Context, status fileDomain.ImageVariantStatus, limit int) ([]*fileDomain.ImageVariant, error) {
+	start := time.Now()
+	result, err := r.next.GetByStatus(ctx, status, limit)
+	data.ObserveRepositoryOperation("image_variant", "GetByStatus", start, err)
+	return result, err
+}
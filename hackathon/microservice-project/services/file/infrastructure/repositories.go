@@ -10,6 +10,7 @@ import (
 	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
 	"github.com/elotusteam/microservice-project/shared/data"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // PostgreSQLRepositoryManager implements fileDomain.RepositoryManager
@@ -32,27 +33,32 @@ func NewPostgreSQLRepositoryManager(db data.DatabaseConnection) fileDomain.Repos
 
 // File returns the file repository
 func (rm *PostgreSQLRepositoryManager) File() fileDomain.FileRepository {
-	return &PostgreSQLFileRepository{db: rm.getDB()}
+	return &instrumentedFileRepository{next: &PostgreSQLFileRepository{db: rm.getDB()}}
 }
 
 // UploadSession returns the upload session repository
 func (rm *PostgreSQLRepositoryManager) UploadSession() fileDomain.UploadSessionRepository {
-	return &PostgreSQLUploadSessionRepository{db: rm.getDB()}
+	return &instrumentedUploadSessionRepository{next: &PostgreSQLUploadSessionRepository{db: rm.getDB()}}
 }
 
 // FileShare returns the file share repository
 func (rm *PostgreSQLRepositoryManager) FileShare() fileDomain.FileShareRepository {
-	return &PostgreSQLFileShareRepository{db: rm.getDB()}
+	return &instrumentedFileShareRepository{next: &PostgreSQLFileShareRepository{db: rm.getDB()}}
 }
 
 // FileVersion returns the file version repository
 func (rm *PostgreSQLRepositoryManager) FileVersion() fileDomain.FileVersionRepository {
-	return &PostgreSQLFileVersionRepository{db: rm.getDB()}
+	return &instrumentedFileVersionRepository{next: &PostgreSQLFileVersionRepository{db: rm.getDB()}}
 }
 
 // ImageVariant returns the image variant repository
 func (rm *PostgreSQLRepositoryManager) ImageVariant() fileDomain.ImageVariantRepository {
-	return &PostgreSQLImageVariantRepository{db: rm.getDB()}
+	return &instrumentedImageVariantRepository{next: &PostgreSQLImageVariantRepository{db: rm.getDB()}}
+}
+
+// FilePermission returns the file permission repository
+func (rm *PostgreSQLRepositoryManager) FilePermission() fileDomain.FilePermissionRepository {
+	return &PostgreSQLFilePermissionRepository{db: rm.getDB()}
 }
 
 // BeginTx starts a new transaction
@@ -106,13 +112,13 @@ func (r *PostgreSQLFileRepository) Create(ctx context.Context, file *fileDomain.
 	}
 
 	query := `
-		INSERT INTO files (id, user_id, filename, original_name, mime_type, size, path, url, checksum, status, metadata, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO files (id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	_, err = r.db.Exec(ctx, query,
 		file.ID, file.UserID, file.Filename, file.OriginalName, file.MimeType,
-		file.Size, file.Path, file.URL, file.Checksum, file.Status,
+		file.Size, file.Path, file.URL, file.Checksum, file.PerceptualHash, file.Status,
 		metadataJSON, file.CreatedAt, file.UpdatedAt,
 	)
 
@@ -126,7 +132,7 @@ func (r *PostgreSQLFileRepository) Create(ctx context.Context, file *fileDomain.
 // GetByID retrieves a file by ID
 func (r *PostgreSQLFileRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.File, error) {
 	query := `
-		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, status, metadata, created_at, updated_at, deleted_at
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
 		FROM files
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -135,10 +141,40 @@ func (r *PostgreSQLFileRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 	return r.scanFile(row)
 }
 
+// GetByIDs retrieves multiple files in a single query
+func (r *PostgreSQLFileRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*fileDomain.File, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
+		FROM files
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.Query(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var files []*fileDomain.File
+	for rows.Next() {
+		file, err := r.scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
 // GetByUserID retrieves files by user ID with pagination
 func (r *PostgreSQLFileRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*fileDomain.File, error) {
 	query := `
-		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, status, metadata, created_at, updated_at, deleted_at
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
 		FROM files
 		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -173,13 +209,13 @@ func (r *PostgreSQLFileRepository) Update(ctx context.Context, file *fileDomain.
 	query := `
 		UPDATE files
 		SET filename = $2, original_name = $3, mime_type = $4, size = $5, path = $6, url = $7,
-		    checksum = $8, status = $9, metadata = $10, updated_at = $11
+		    checksum = $8, perceptual_hash = $9, status = $10, metadata = $11, updated_at = $12
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	_, err = r.db.Exec(ctx, query,
 		file.ID, file.Filename, file.OriginalName, file.MimeType, file.Size,
-		file.Path, file.URL, file.Checksum, file.Status, metadataJSON, time.Now(),
+		file.Path, file.URL, file.Checksum, file.PerceptualHash, file.Status, metadataJSON, time.Now(),
 	)
 
 	if err != nil {
@@ -202,7 +238,7 @@ func (r *PostgreSQLFileRepository) Delete(ctx context.Context, id uuid.UUID) err
 // GetByChecksum retrieves a file by checksum
 func (r *PostgreSQLFileRepository) GetByChecksum(ctx context.Context, checksum string) (*fileDomain.File, error) {
 	query := `
-		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, status, metadata, created_at, updated_at, deleted_at
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
 		FROM files
 		WHERE checksum = $1 AND deleted_at IS NULL
 		LIMIT 1
@@ -215,7 +251,7 @@ func (r *PostgreSQLFileRepository) GetByChecksum(ctx context.Context, checksum s
 // Search searches files by query
 func (r *PostgreSQLFileRepository) Search(ctx context.Context, query string, userID uuid.UUID, limit, offset int) ([]*fileDomain.File, error) {
 	sqlQuery := `
-		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, status, metadata, created_at, updated_at, deleted_at
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
 		FROM files
 		WHERE user_id = $1 AND deleted_at IS NULL
 		  AND (filename ILIKE $2 OR original_name ILIKE $2)
@@ -264,6 +300,180 @@ func (r *PostgreSQLFileRepository) GetFileCount(ctx context.Context, userID uuid
 	return count, nil
 }
 
+// GetTagFacets returns the user's distinct tags with file counts, ordered by
+// frequency, in a single grouped query over the tags column
+func (r *PostgreSQLFileRepository) GetTagFacets(ctx context.Context, userID uuid.UUID, limit int) ([]*fileDomain.TagFacet, error) {
+	query := `
+		SELECT tag, COUNT(*) AS count
+		FROM files, unnest(tags) AS tag
+		WHERE user_id = $1 AND deleted_at IS NULL
+		GROUP BY tag
+		ORDER BY count DESC, tag ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag facets: %w", err)
+	}
+	defer rows.Close()
+
+	var facets []*fileDomain.TagFacet
+	for rows.Next() {
+		var facet fileDomain.TagFacet
+		if err := rows.Scan(&facet.Tag, &facet.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag facet: %w", err)
+		}
+		facets = append(facets, &facet)
+	}
+
+	return facets, nil
+}
+
+// GetExpiringSoon retrieves files whose expiry falls before the given time.
+// With uuid.Nil as userID, files of every user are considered (for the
+// expiry-warning job).
+func (r *PostgreSQLFileRepository) GetExpiringSoon(ctx context.Context, userID uuid.UUID, before time.Time, limit int) ([]*fileDomain.File, error) {
+	query := `
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
+		FROM files
+		WHERE deleted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= $1
+		  AND ($2::uuid = '00000000-0000-0000-0000-000000000000' OR user_id = $2)
+		ORDER BY expires_at ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, before, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expiring files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []*fileDomain.File
+	for rows.Next() {
+		file, err := r.scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// GetMissingChecksums retrieves files without a checksum, oldest first, for
+// the backfill job
+func (r *PostgreSQLFileRepository) GetMissingChecksums(ctx context.Context, limit int) ([]*fileDomain.File, error) {
+	query := `
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
+		FROM files
+		WHERE deleted_at IS NULL AND (checksum IS NULL OR checksum = '')
+		ORDER BY created_at ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files missing checksums: %w", err)
+	}
+	defer rows.Close()
+
+	var files []*fileDomain.File
+	for rows.Next() {
+		file, err := r.scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// GetByStatus retrieves files by status with pagination
+func (r *PostgreSQLFileRepository) GetByStatus(ctx context.Context, status fileDomain.FileStatus, limit, offset int) ([]*fileDomain.File, error) {
+	query := `
+		SELECT id, user_id, filename, original_name, mime_type, size, path, url, checksum, perceptual_hash, status, metadata, created_at, updated_at, deleted_at
+		FROM files
+		WHERE status = $1 AND deleted_at IS NULL
+		ORDER BY updated_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files by status: %w", err)
+	}
+	defer rows.Close()
+
+	var files []*fileDomain.File
+	for rows.Next() {
+		file, err := r.scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// GetSizeEvents returns each file's size with creation/deletion timestamps
+// overlapping the range, including soft-deleted rows so deletions register
+// as negative growth
+func (r *PostgreSQLFileRepository) GetSizeEvents(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*fileDomain.SizeEvent, error) {
+	query := `
+		SELECT size, created_at, deleted_at
+		FROM files
+		WHERE user_id = $1 AND created_at <= $3 AND (deleted_at IS NULL OR deleted_at >= $2)
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query size events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*fileDomain.SizeEvent
+	for rows.Next() {
+		var event fileDomain.SizeEvent
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&event.Size, &event.CreatedAt, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan size event: %w", err)
+		}
+		if deletedAt.Valid {
+			event.DeletedAt = &deletedAt.Time
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// ListPaths lists the storage paths of all live files
+func (r *PostgreSQLFileRepository) ListPaths(ctx context.Context) ([]string, error) {
+	return r.listPathsQuery(ctx, `SELECT path FROM files WHERE deleted_at IS NULL`)
+}
+
+// listPathsQuery runs a single-column path query
+func (r *PostgreSQLFileRepository) listPathsQuery(ctx context.Context, query string) ([]string, error) {
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // scanFile scans a database row into a File struct
 func (r *PostgreSQLFileRepository) scanFile(row data.Row) (*fileDomain.File, error) {
 	var file fileDomain.File
@@ -272,7 +482,7 @@ func (r *PostgreSQLFileRepository) scanFile(row data.Row) (*fileDomain.File, err
 
 	err := row.Scan(
 		&file.ID, &file.UserID, &file.Filename, &file.OriginalName, &file.MimeType,
-		&file.Size, &file.Path, &file.URL, &file.Checksum, &file.Status,
+		&file.Size, &file.Path, &file.URL, &file.Checksum, &file.PerceptualHash, &file.Status,
 		&metadataJSON, &file.CreatedAt, &file.UpdatedAt, &deletedAt,
 	)
 
@@ -387,6 +597,35 @@ func (r *PostgreSQLUploadSessionRepository) Delete(ctx context.Context, id uuid.
 }
 
 // DeleteExpired deletes expired upload sessions
+// GetExpired retrieves upload sessions whose expiry has passed, for cleanup
+// with stats tracking
+func (r *PostgreSQLUploadSessionRepository) GetExpired(ctx context.Context, limit int) ([]*fileDomain.UploadSession, error) {
+	query := `
+		SELECT id, user_id, filename, total_size, chunk_size, uploaded_size, status, expires_at, created_at, updated_at
+		FROM upload_sessions
+		WHERE expires_at < $1
+		ORDER BY expires_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*fileDomain.UploadSession
+	for rows.Next() {
+		session, err := r.scanUploadSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
 func (r *PostgreSQLUploadSessionRepository) DeleteExpired(ctx context.Context) error {
 	query := `DELETE FROM upload_sessions WHERE expires_at < $1`
 	_, err := r.db.Exec(ctx, query, time.Now())
@@ -658,6 +897,26 @@ func (r *PostgreSQLFileShareRepository) scanFileShare(row data.Row) (*fileDomain
 	return &share, nil
 }
 
+// DeleteByFileID bulk-deletes every share on a file in one statement
+func (r *PostgreSQLFileShareRepository) DeleteByFileID(ctx context.Context, fileID uuid.UUID) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM file_shares WHERE file_id = $1`, fileID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete shares by file: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected, nil
+}
+
+// DeleteBySharedWith bulk-deletes everything one user shared with another
+func (r *PostgreSQLFileShareRepository) DeleteBySharedWith(ctx context.Context, sharedBy, sharedWith uuid.UUID) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM file_shares WHERE shared_by = $1 AND shared_with = $2`, sharedBy, sharedWith)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete shares by recipient: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected, nil
+}
+
 // PostgreSQLFileVersionRepository implements fileDomain.FileVersionRepository
 type PostgreSQLFileVersionRepository struct {
 	db data.DatabaseConnection
@@ -666,13 +925,13 @@ type PostgreSQLFileVersionRepository struct {
 // Create creates a new file version
 func (r *PostgreSQLFileVersionRepository) Create(ctx context.Context, version *fileDomain.FileVersion) error {
 	query := `
-		INSERT INTO file_versions (id, file_id, version, path, size, checksum, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO file_versions (id, file_id, version, path, size, checksum, pinned, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err := r.db.Exec(ctx, query,
 		version.ID, version.FileID, version.Version, version.Path,
-		version.Size, version.Checksum, version.CreatedAt,
+		version.Size, version.Checksum, version.Pinned, version.CreatedAt,
 	)
 
 	if err != nil {
@@ -685,7 +944,7 @@ func (r *PostgreSQLFileVersionRepository) Create(ctx context.Context, version *f
 // GetByID retrieves a file version by ID
 func (r *PostgreSQLFileVersionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fileDomain.FileVersion, error) {
 	query := `
-		SELECT id, file_id, version, path, size, checksum, created_at
+		SELECT id, file_id, version, path, size, checksum, pinned, created_at
 		FROM file_versions
 		WHERE id = $1
 	`
@@ -697,7 +956,7 @@ func (r *PostgreSQLFileVersionRepository) GetByID(ctx context.Context, id uuid.U
 // GetByFileID retrieves file versions by file ID
 func (r *PostgreSQLFileVersionRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.FileVersion, error) {
 	query := `
-		SELECT id, file_id, version, path, size, checksum, created_at
+		SELECT id, file_id, version, path, size, checksum, pinned, created_at
 		FROM file_versions
 		WHERE file_id = $1
 		ORDER BY version DESC
@@ -724,7 +983,7 @@ func (r *PostgreSQLFileVersionRepository) GetByFileID(ctx context.Context, fileI
 // GetLatestByFileID retrieves the latest file version by file ID
 func (r *PostgreSQLFileVersionRepository) GetLatestByFileID(ctx context.Context, fileID uuid.UUID) (*fileDomain.FileVersion, error) {
 	query := `
-		SELECT id, file_id, version, path, size, checksum, created_at
+		SELECT id, file_id, version, path, size, checksum, pinned, created_at
 		FROM file_versions
 		WHERE file_id = $1
 		ORDER BY version DESC
@@ -735,6 +994,25 @@ func (r *PostgreSQLFileVersionRepository) GetLatestByFileID(ctx context.Context,
 	return r.scanFileVersion(row)
 }
 
+// Update updates a file version (e.g. toggling the pinned flag)
+func (r *PostgreSQLFileVersionRepository) Update(ctx context.Context, version *fileDomain.FileVersion) error {
+	query := `
+		UPDATE file_versions
+		SET path = $2, size = $3, checksum = $4, pinned = $5
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		version.ID, version.Path, version.Size, version.Checksum, version.Pinned,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update file version: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes a file version
 func (r *PostgreSQLFileVersionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM file_versions WHERE id = $1`
@@ -755,13 +1033,32 @@ func (r *PostgreSQLFileVersionRepository) DeleteByFileID(ctx context.Context, fi
 	return nil
 }
 
+// ListPaths lists the storage paths of all file versions
+func (r *PostgreSQLFileVersionRepository) ListPaths(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT path FROM file_versions`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query version paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan version path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // scanFileVersion scans a database row into a FileVersion struct
 func (r *PostgreSQLFileVersionRepository) scanFileVersion(row data.Row) (*fileDomain.FileVersion, error) {
 	var version fileDomain.FileVersion
 
 	err := row.Scan(
 		&version.ID, &version.FileID, &version.Version, &version.Path,
-		&version.Size, &version.Checksum, &version.CreatedAt,
+		&version.Size, &version.Checksum, &version.Pinned, &version.CreatedAt,
 	)
 
 	if err != nil {
@@ -781,7 +1078,9 @@ type dbWrapper struct {
 }
 
 func (w *dbWrapper) Query(ctx context.Context, query string, args ...interface{}) (data.Rows, error) {
+	start := time.Now()
 	rows, err := w.db.QueryContext(ctx, query, args...)
+	data.DefaultSlowQueryRecorder.Record(query, time.Since(start))
 	if err != nil {
 		return nil, err
 	}
@@ -793,7 +1092,9 @@ func (w *dbWrapper) QueryRow(ctx context.Context, query string, args ...interfac
 }
 
 func (w *dbWrapper) Exec(ctx context.Context, query string, args ...interface{}) (data.Result, error) {
+	start := time.Now()
 	result, err := w.db.ExecContext(ctx, query, args...)
+	data.DefaultSlowQueryRecorder.Record(query, time.Since(start))
 	if err != nil {
 		return nil, err
 	}
@@ -813,7 +1114,7 @@ func (w *dbWrapper) Begin(ctx context.Context) (data.Transaction, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &txWrapper{tx: tx}, nil
+	return &txWrapper{tx: tx, ctx: ctx}, nil
 }
 
 func (w *dbWrapper) Ping(ctx context.Context) error {
@@ -825,7 +1126,8 @@ func (w *dbWrapper) Close() error {
 }
 
 type txWrapper struct {
-	tx *sql.Tx
+	tx  *sql.Tx
+	ctx context.Context
 }
 
 func (w *txWrapper) Query(ctx context.Context, query string, args ...interface{}) (data.Rows, error) {
@@ -876,7 +1178,12 @@ func (w *txWrapper) Rollback() error {
 	return w.tx.Rollback()
 }
 
+// Context returns the context the transaction was started with so request
+// cancellation and deadlines propagate to queries issued inside it
 func (w *txWrapper) Context() context.Context {
+	if w.ctx != nil {
+		return w.ctx
+	}
 	return context.Background()
 }
 
@@ -960,13 +1267,13 @@ type PostgreSQLImageVariantRepository struct {
 // Create creates a new image variant
 func (r *PostgreSQLImageVariantRepository) Create(ctx context.Context, variant *fileDomain.ImageVariant) error {
 	query := `
-		INSERT INTO image_variants (id, file_id, variant_type, width, height, size, path, format, quality, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO image_variants (id, file_id, variant_type, width, height, size, path, format, quality, status, attempt_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err := r.db.Exec(ctx, query,
 		variant.ID, variant.FileID, variant.VariantType, variant.Width, variant.Height,
-		variant.Size, variant.Path, variant.Format, variant.Quality, variant.Status, variant.CreatedAt, variant.UpdatedAt,
+		variant.Size, variant.Path, variant.Format, variant.Quality, variant.Status, variant.AttemptCount, variant.CreatedAt, variant.UpdatedAt,
 	)
 
 	if err != nil {
@@ -979,7 +1286,7 @@ func (r *PostgreSQLImageVariantRepository) Create(ctx context.Context, variant *
 // GetByID retrieves an image variant by ID
 func (r *PostgreSQLImageVariantRepository) GetByID(ctx context.Context, id string) (*fileDomain.ImageVariant, error) {
 	query := `
-		SELECT id, file_id, variant_type, width, height, size, path, format, quality, status, created_at, updated_at
+		SELECT id, file_id, variant_type, width, height, size, path, format, quality, status, attempt_count, created_at, updated_at
 		FROM image_variants
 		WHERE id = $1
 	`
@@ -991,7 +1298,7 @@ func (r *PostgreSQLImageVariantRepository) GetByID(ctx context.Context, id strin
 // GetByFileID retrieves image variants by file ID
 func (r *PostgreSQLImageVariantRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.ImageVariant, error) {
 	query := `
-		SELECT id, file_id, variant_type, width, height, size, path, format, quality, status, created_at, updated_at
+		SELECT id, file_id, variant_type, width, height, size, path, format, quality, status, attempt_count, created_at, updated_at
 		FROM image_variants
 		WHERE file_id = $1
 		ORDER BY created_at ASC
@@ -1019,13 +1326,13 @@ func (r *PostgreSQLImageVariantRepository) GetByFileID(ctx context.Context, file
 func (r *PostgreSQLImageVariantRepository) Update(ctx context.Context, variant *fileDomain.ImageVariant) error {
 	query := `
 		UPDATE image_variants 
-		SET variant_type = $2, width = $3, height = $4, size = $5, path = $6, format = $7, quality = $8, status = $9, updated_at = $10
+		SET variant_type = $2, width = $3, height = $4, size = $5, path = $6, format = $7, quality = $8, status = $9, attempt_count = $10, updated_at = $11
 		WHERE id = $1
 	`
 
 	_, err := r.db.Exec(ctx, query,
 		variant.ID, variant.VariantType, variant.Width, variant.Height,
-		variant.Size, variant.Path, variant.Format, variant.Quality, variant.Status, variant.UpdatedAt,
+		variant.Size, variant.Path, variant.Format, variant.Quality, variant.Status, variant.AttemptCount, variant.UpdatedAt,
 	)
 
 	if err != nil {
@@ -1055,13 +1362,61 @@ func (r *PostgreSQLImageVariantRepository) DeleteByFileID(ctx context.Context, f
 	return nil
 }
 
+// GetByStatus retrieves image variants by status (e.g. failed ones pending
+// retry)
+func (r *PostgreSQLImageVariantRepository) GetByStatus(ctx context.Context, status fileDomain.ImageVariantStatus, limit int) ([]*fileDomain.ImageVariant, error) {
+	query := `
+		SELECT id, file_id, variant_type, width, height, size, path, format, quality, status, attempt_count, created_at, updated_at
+		FROM image_variants
+		WHERE status = $1
+		ORDER BY updated_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query variants by status: %w", err)
+	}
+	defer rows.Close()
+
+	var variants []*fileDomain.ImageVariant
+	for rows.Next() {
+		variant, err := r.scanImageVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// ListPaths lists the storage paths of all image variants
+func (r *PostgreSQLImageVariantRepository) ListPaths(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT path FROM image_variants`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query variant paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan variant path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // scanImageVariant scans a database row into an ImageVariant struct
 func (r *PostgreSQLImageVariantRepository) scanImageVariant(row data.Row) (*fileDomain.ImageVariant, error) {
 	var variant fileDomain.ImageVariant
 
 	err := row.Scan(
 		&variant.ID, &variant.FileID, &variant.VariantType, &variant.Width, &variant.Height,
-		&variant.Size, &variant.Path, &variant.Format, &variant.Quality, &variant.Status, &variant.CreatedAt, &variant.UpdatedAt,
+		&variant.Size, &variant.Path, &variant.Format, &variant.Quality, &variant.Status, &variant.AttemptCount, &variant.CreatedAt, &variant.UpdatedAt,
 	)
 
 	if err != nil {
@@ -1073,3 +1428,103 @@ func (r *PostgreSQLImageVariantRepository) scanImageVariant(row data.Row) (*file
 
 	return &variant, nil
 }
+
+// PostgreSQLFilePermissionRepository implements fileDomain.FilePermissionRepository
+type PostgreSQLFilePermissionRepository struct {
+	db data.DatabaseConnection
+}
+
+// Create creates a new file permission grant
+func (r *PostgreSQLFilePermissionRepository) Create(ctx context.Context, permission *fileDomain.FilePermission) error {
+	query := `
+		INSERT INTO file_permissions (id, file_id, user_id, group_id, permission, granted_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		permission.ID, permission.FileID, permission.UserID, permission.GroupID,
+		permission.Permission, permission.GrantedBy, permission.ExpiresAt, permission.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create file permission: %w", err)
+	}
+
+	return nil
+}
+
+// GetByFileID retrieves all permission grants on a file
+func (r *PostgreSQLFilePermissionRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.FilePermission, error) {
+	query := `
+		SELECT id, file_id, user_id, group_id, permission, granted_by, expires_at, created_at
+		FROM file_permissions
+		WHERE file_id = $1
+	`
+	return r.queryPermissions(ctx, query, fileID)
+}
+
+// GetByFileAndUser retrieves a user's direct grants on a file
+func (r *PostgreSQLFilePermissionRepository) GetByFileAndUser(ctx context.Context, fileID, userID uuid.UUID) ([]*fileDomain.FilePermission, error) {
+	query := `
+		SELECT id, file_id, user_id, group_id, permission, granted_by, expires_at, created_at
+		FROM file_permissions
+		WHERE file_id = $1 AND user_id = $2
+	`
+	return r.queryPermissions(ctx, query, fileID, userID)
+}
+
+// GetByFileAndGroup retrieves a group's grants on a file
+func (r *PostgreSQLFilePermissionRepository) GetByFileAndGroup(ctx context.Context, fileID, groupID uuid.UUID) ([]*fileDomain.FilePermission, error) {
+	query := `
+		SELECT id, file_id, user_id, group_id, permission, granted_by, expires_at, created_at
+		FROM file_permissions
+		WHERE file_id = $1 AND group_id = $2
+	`
+	return r.queryPermissions(ctx, query, fileID, groupID)
+}
+
+// Delete removes a permission grant
+func (r *PostgreSQLFilePermissionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM file_permissions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete file permission: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFileID removes all grants on a file
+func (r *PostgreSQLFilePermissionRepository) DeleteByFileID(ctx context.Context, fileID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM file_permissions WHERE file_id = $1`, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete file permissions: %w", err)
+	}
+	return nil
+}
+
+// queryPermissions runs a permission query and scans the rows
+func (r *PostgreSQLFilePermissionRepository) queryPermissions(ctx context.Context, query string, args ...interface{}) ([]*fileDomain.FilePermission, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []*fileDomain.FilePermission
+	for rows.Next() {
+		var permission fileDomain.FilePermission
+		var expiresAt sql.NullTime
+		err := rows.Scan(
+			&permission.ID, &permission.FileID, &permission.UserID, &permission.GroupID,
+			&permission.Permission, &permission.GrantedBy, &expiresAt, &permission.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file permission: %w", err)
+		}
+		if expiresAt.Valid {
+			permission.ExpiresAt = &expiresAt.Time
+		}
+		permissions = append(permissions, &permission)
+	}
+
+	return permissions, nil
+}
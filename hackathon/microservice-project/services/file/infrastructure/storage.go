@@ -244,6 +244,36 @@ func (s *MultiCDNStorageService) GetSize(ctx context.Context, path string) (int6
 	return info.Size(), nil
 }
 
+// ListKeys lists stored object keys under the prefix, relative to the
+// storage root (primary CDN)
+func (s *MultiCDNStorageService) ListKeys(ctx context.Context, prefix string) ([]string, error) {
+	root := filepath.Join(s.primaryPath, prefix)
+
+	var keys []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(s.primaryPath, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(relative))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage keys: %w", err)
+	}
+
+	return keys, nil
+}
+
 // Private helper methods
 
 func (s *MultiCDNStorageService) uploadToCDN(ctx context.Context, cdnPath, filePath string, content []byte) error {
@@ -6,12 +6,19 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/elotusteam/microservice-project/services/file/usecases"
+	"github.com/elotusteam/microservice-project/shared/authrpc"
+	"github.com/elotusteam/microservice-project/shared/data"
+	"github.com/elotusteam/microservice-project/shared/featureflags"
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -40,12 +47,98 @@ var (
 		},
 		[]string{"status"},
 	)
+	uploadSessionsAbandoned = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "file_upload_sessions_abandoned_total",
+			Help: "Expired resumable upload sessions reclaimed by cleanup",
+		},
+	)
+	uploadBytesReclaimed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "file_upload_bytes_reclaimed_total",
+			Help: "Bytes reclaimed from abandoned upload sessions",
+		},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(httpRequestsTotal)
 	prometheus.MustRegister(httpRequestDuration)
 	prometheus.MustRegister(fileUploadsTotal)
+	prometheus.MustRegister(uploadSessionsAbandoned)
+	prometheus.MustRegister(uploadBytesReclaimed)
+}
+
+// authTokenValidator validates bearer tokens against the auth service over
+// gRPC (AUTH_GRPC_ADDR), failing closed when the auth service is unreachable
+func authTokenValidator() middleware.TokenValidator {
+	target := getEnv("AUTH_GRPC_ADDR", "localhost:9081")
+	var (
+		clientOnce sync.Once
+		client     *authrpc.Client
+		dialErr    error
+	)
+
+	return func(ctx context.Context, token string) (string, string, error) {
+		clientOnce.Do(func() {
+			client, dialErr = authrpc.Dial(target)
+		})
+		if dialErr != nil {
+			return "", "", fmt.Errorf("auth service unavailable: %w", dialErr)
+		}
+
+		resp, err := client.ValidateToken(ctx, token)
+		if err != nil {
+			return "", "", err
+		}
+		if !resp.Valid {
+			return "", "", fmt.Errorf("invalid token: %s", resp.Error)
+		}
+		return resp.UserID, resp.Role, nil
+	}
+}
+
+// expiryWarningWindow returns how far ahead the expiry notifier looks,
+// configurable via FILE_EXPIRY_WARNING_WINDOW
+func expiryWarningWindow() time.Duration {
+	if value := os.Getenv("FILE_EXPIRY_WARNING_WINDOW"); value != "" {
+		if within, err := time.ParseDuration(value); err == nil && within > 0 {
+			return within
+		}
+	}
+	return 72 * time.Hour
+}
+
+// runExpiryNotifier periodically warns owners about files expiring within
+// the warning window, on the FILE_EXPIRY_NOTIFY_INTERVAL cadence
+func runExpiryNotifier(ctx context.Context, container *ServiceContainer) {
+	interval := time.Hour
+	if value := os.Getenv("FILE_EXPIRY_NOTIFY_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := container.FileService.NotifyExpiringFiles(ctx, expiryWarningWindow()); err != nil {
+				fmt.Printf("Expiry notification pass failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// fileFields is the sparse-fieldset allowlist for file listings
+var fileFields = map[string]bool{
+	"id": true, "filename": true, "original_name": true, "mime_type": true,
+	"size": true, "status": true, "url": true, "checksum": true,
+	"created_at": true, "updated_at": true, "expires_at": true, "tags": true,
 }
 
 // Prometheus middleware
@@ -68,6 +161,12 @@ func main() {
 		log.Fatalf("Failed to initialize service container: %v", err)
 	}
 
+	// Retry failed image-variant generation in the background
+	go container.ImageProcessingService.StartVariantRetrier(context.Background())
+
+	// Warn owners about files approaching expiry in the background
+	go runExpiryNotifier(context.Background(), container)
+
 	// Load configuration from environment
 	host := getEnv("SERVER_HOST", "localhost")
 	port := getEnvAsInt("SERVER_PORT", 8082)
@@ -81,6 +180,127 @@ func main() {
 	// Add Prometheus middleware
 	router.Use(prometheusMiddleware())
 
+	// Return 405 with an Allow header for wrong-method requests
+	middleware.ConfigureMethodNotAllowed(router)
+
+	// Read-only maintenance mode: uploads/deletes/updates return 503 while
+	// enabled, downloads and listings keep working
+	maintenanceMode := middleware.NewMaintenanceMode(getEnv("FILE_MAINTENANCE_MODE", "false") == "true")
+	router.Use(maintenanceMode.Middleware())
+
+	// Admin routes authenticate via the auth service's gRPC token
+	// validation; the role comes from validated claims, never from
+	// client-supplied headers
+	admin := router.Group("/admin")
+	admin.Use(middleware.RequireRole(authTokenValidator(), "admin"))
+
+	// Per-user feature-flag override management for operators
+	flagFactory := featureflags.NewFactory(&featureflags.FeatureFlagConfig{
+		Enabled:     true,
+		Environment: getEnv("ENVIRONMENT", "development"),
+		Service:     "file",
+		StorageType: "memory",
+	}, nil)
+	flagManager, err := flagFactory.CreateManager()
+	if err != nil {
+		log.Fatalf("Failed to initialize feature flags: %v", err)
+	}
+	featureflags.RegisterOverrideAdminRoutes(admin, flagManager)
+
+	admin.GET("/quarantine", func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+		files, err := container.FileService.ListQuarantinedFiles(c.Request.Context(), limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"files": files, "total": len(files)})
+	})
+	admin.POST("/quarantine/:id/release", func(c *gin.Context) {
+		fileID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+			return
+		}
+
+		if err := container.FileService.ReleaseQuarantinedFile(c.Request.Context(), fileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "File released from quarantine"})
+	})
+	admin.DELETE("/quarantine/:id", func(c *gin.Context) {
+		fileID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+			return
+		}
+
+		if err := container.FileService.DeleteQuarantinedFile(c.Request.Context(), fileID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Quarantined file deleted"})
+	})
+	admin.POST("/backfill-checksums", func(c *gin.Context) {
+		report, err := container.FileService.BackfillChecksums(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	})
+	admin.GET("/variant-usage", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"variants": container.ImageProcessingService.GetVariantUsageReport()})
+	})
+	admin.GET("/slow-queries", func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		c.JSON(http.StatusOK, gin.H{"queries": data.DefaultSlowQueryRecorder.Top(limit)})
+	})
+	admin.POST("/uploads/cleanup", func(c *gin.Context) {
+		report, err := container.FileService.CleanupExpiredUploadSessions(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		uploadSessionsAbandoned.Add(float64(report.AbandonedSessions))
+		uploadBytesReclaimed.Add(float64(report.ReclaimedBytes))
+
+		c.JSON(http.StatusOK, report)
+	})
+	admin.POST("/orphans", func(c *gin.Context) {
+		cleanup, _ := strconv.ParseBool(c.DefaultQuery("cleanup", "false"))
+
+		report, err := container.FileService.FindOrphans(c.Request.Context(), cleanup)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	})
+	admin.GET("/maintenance", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"enabled": maintenanceMode.Enabled()})
+	})
+	admin.PUT("/maintenance", func(c *gin.Context) {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		maintenanceMode.Set(req.Enabled)
+		c.JSON(http.StatusOK, gin.H{"enabled": maintenanceMode.Enabled()})
+	})
+
 	// Metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -96,8 +316,10 @@ func main() {
 	// Basic API routes using the file service
 	api := router.Group("/api/v1")
 	{
-		// File routes
+		// File routes. Caller identity comes from the validated bearer
+		// token, never from client-supplied headers
 		files := api.Group("/files")
+		files.Use(middleware.RequireAuth(authTokenValidator()))
 		{
 			files.POST("/upload", func(c *gin.Context) {
 				// File service is available via container.FileService
@@ -117,9 +339,446 @@ func main() {
 				// Implementation would use container.FileService.ListFiles()
 				c.JSON(http.StatusOK, gin.H{"message": "List files endpoint - file service integrated"})
 			})
+			files.GET("/:id/download", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				file, err := container.FileService.GetFile(c.Request.Context(), fileID, userID)
+				if err != nil {
+					c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+					return
+				}
+
+				// Conditional GET: the checksum is a natural strong ETag and
+				// UpdatedAt backs Last-Modified, so unchanged files cost 304s
+				// instead of full transfers
+				etag := fmt.Sprintf("%q", file.Checksum)
+				lastModified := file.UpdatedAt.UTC().Truncate(time.Second)
+
+				c.Header("ETag", etag)
+				c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+				if match := c.GetHeader("If-None-Match"); match != "" {
+					if match == etag || match == "*" {
+						c.Status(http.StatusNotModified)
+						return
+					}
+				} else if since := c.GetHeader("If-Modified-Since"); since != "" {
+					if sinceTime, err := http.ParseTime(since); err == nil && !lastModified.After(sinceTime) {
+						c.Status(http.StatusNotModified)
+						return
+					}
+				}
+
+				content, err := container.FileService.GetFileContent(c.Request.Context(), fileID, userID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				defer content.Close()
+
+				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.OriginalName))
+				c.DataFromReader(http.StatusOK, file.Size, file.MimeType, content, nil)
+			})
+			files.POST("/upload-url", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				var req usecases.SignedUploadRequest
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				response, err := container.FileService.CreateSignedUploadURL(c.Request.Context(), userID, &req)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusCreated, response)
+			})
+			files.POST("/upload-url/complete", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				var req struct {
+					RegistrationToken string `json:"registration_token" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				file, err := container.FileService.CompleteSignedUpload(c.Request.Context(), userID, req.RegistrationToken)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusCreated, file)
+			})
+			files.GET("/expiring", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				within := 72 * time.Hour
+				if value := c.Query("within"); value != "" {
+					parsed, err := time.ParseDuration(value)
+					if err != nil || parsed <= 0 {
+						c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid within duration"})
+						return
+					}
+					within = parsed
+				}
+
+				fields, err := middleware.ParseFieldsParam(c, fileFields)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				files, err := container.FileService.GetExpiringSoon(c.Request.Context(), userID, within)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				payload, err := middleware.FilterFields(files, fields)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"files": payload, "total": len(files)})
+			})
+			files.GET("/storage-growth", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				granularity := c.DefaultQuery("granularity", "day")
+				end := time.Now()
+				start := end.AddDate(0, 0, -30)
+				if value := c.Query("start"); value != "" {
+					if parsed, err := time.Parse("2006-01-02", value); err == nil {
+						start = parsed
+					}
+				}
+				if value := c.Query("end"); value != "" {
+					if parsed, err := time.Parse("2006-01-02", value); err == nil {
+						end = parsed
+					}
+				}
+
+				response, err := container.FileService.GetStorageGrowth(c.Request.Context(), userID, granularity, start, end)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, response)
+			})
+			files.GET("/my-shares", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				shares, err := container.FileShareService.ListSharesWithStats(c.Request.Context(), userID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"shares": shares, "total": len(shares)})
+			})
+			files.POST("/batch-get", func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				var req struct {
+					IDs []uuid.UUID `json:"ids" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				response, err := container.FileService.BatchGetFiles(c.Request.Context(), userID, req.IDs)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, response)
+			})
+			files.GET("/:id/similar", func(c *gin.Context) {
+				// Near-duplicate lookup by perceptual hash. Registered as
+				// /files/:id/similar because gin cannot mix a literal
+				// "similar" segment with the /files/:id wildcard.
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				similar, err := container.FileService.FindSimilarImages(c.Request.Context(), fileID, userID)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"files": similar, "total": len(similar)})
+			})
+			files.GET("/:id/permissions", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				callerID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				targetID, err := uuid.Parse(c.Query("user_id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+					return
+				}
+
+				// Only users with share rights may inspect a file's grants,
+				// matching the grant/revoke endpoints
+				allowed, err := container.PermissionService.CheckFilePermission(c.Request.Context(), callerID, fileID, "share")
+				if err != nil || !allowed {
+					c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+					return
+				}
+
+				permissions, err := container.PermissionService.ListFilePermissions(c.Request.Context(), targetID, fileID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"user_id": targetID, "permissions": permissions})
+			})
+			files.POST("/:id/permissions", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				callerID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				var req struct {
+					UserID     uuid.UUID `json:"user_id" binding:"required"`
+					Permission string    `json:"permission" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				// Only users with share rights may grant access
+				allowed, err := container.PermissionService.CheckFilePermission(c.Request.Context(), callerID, fileID, "share")
+				if err != nil || !allowed {
+					c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+					return
+				}
+
+				if err := container.PermissionService.GrantFilePermission(c.Request.Context(), req.UserID, fileID, req.Permission); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusCreated, gin.H{"message": "Permission granted"})
+			})
+			files.DELETE("/:id/permissions", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				callerID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				var req struct {
+					UserID     uuid.UUID `json:"user_id" binding:"required"`
+					Permission string    `json:"permission" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				allowed, err := container.PermissionService.CheckFilePermission(c.Request.Context(), callerID, fileID, "share")
+				if err != nil || !allowed {
+					c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+					return
+				}
+
+				if err := container.PermissionService.RevokeFilePermission(c.Request.Context(), req.UserID, fileID, req.Permission); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"message": "Permission revoked"})
+			})
+			files.DELETE("/:id/shares", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				revoked, err := container.FileShareService.RevokeSharesByFile(c.Request.Context(), fileID, userID)
+				if err != nil {
+					c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+			})
+			files.DELETE("/shared-with/:user_id", func(c *gin.Context) {
+				recipientID, err := uuid.Parse(c.Param("user_id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recipient ID"})
+					return
+				}
+
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				revoked, err := container.FileShareService.RevokeSharesByRecipient(c.Request.Context(), userID, recipientID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+			})
+			files.POST("/:id/variants/regenerate", func(c *gin.Context) {
+				fileID, err := uuid.Parse(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+					return
+				}
+
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user ID"})
+					return
+				}
+
+				dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+				response, err := container.FileService.RegenerateImageVariants(c.Request.Context(), fileID, userID, dryRun)
+				if err != nil {
+					c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+					return
+				}
+
+				c.JSON(http.StatusAccepted, response)
+			})
 		}
 	}
 
+	// Direct-upload target for pre-signed URLs. The signature and expiry in
+	// the query string are verified before anything touches storage.
+	api.PUT("/uploads/direct/*path", func(c *gin.Context) {
+		path := strings.TrimPrefix(c.Param("path"), "/")
+		if path == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload path required"})
+			return
+		}
+
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires parameter"})
+			return
+		}
+
+		err = container.FileService.DirectUpload(c.Request.Context(), path, expires,
+			c.Query("signature"), c.Request.Body, c.ContentType())
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Status(http.StatusOK)
+	})
+
+	// Shared file access by token. Registered as /shares/:token because gin
+	// cannot mix the literal "shared" segment with the /files/:id wildcard.
+	api.GET("/shares/:token", func(c *gin.Context) {
+		// Password may come via query param or header for
+		// password-protected shares
+		password := c.Query("password")
+		if password == "" {
+			password = c.GetHeader("X-Share-Password")
+		}
+
+		response, err := container.FileShareService.GetSharedFile(c.Request.Context(), c.Param("token"), password)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	})
+
 	// File service is now available via container.FileService with image processing capabilities
 	_ = container // Suppress unused variable warning for now
 
@@ -133,28 +792,11 @@ func main() {
 		IdleTimeout:       120 * time.Second,
 	}
 
-	// Graceful shutdown
-	go func() {
-		log.Printf("File service starting on %s:%d", host, port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// The context is used to inform the server it has 5 seconds to finish
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	// Run with graceful shutdown handled by the shared helper
+	log.Printf("File service starting on %s:%d", host, port)
+	if err := sharedServer.RunServer(context.Background(), server, 5*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
-
-	log.Println("Server exiting")
 }
 
 // Helper functions
@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
+	"github.com/google/uuid"
+)
+
+// GroupMembershipService resolves the groups a user belongs to so group
+// grants can be honored. Backed by the user service; may be nil, in which
+// case only direct user grants apply.
+type GroupMembershipService interface {
+	GetUserGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// permissionImplies maps each permission to the permissions it covers;
+// admin covers everything, write implies read.
+var permissionImplies = map[string][]string{
+	"admin":  {"admin", "read", "write", "delete", "share"},
+	"write":  {"write", "read"},
+	"read":   {"read"},
+	"delete": {"delete"},
+	"share":  {"share"},
+}
+
+// aclPermissionService implements PermissionService using the file service's
+// own FilePermission ACLs instead of a remote permission check. Owners hold
+// every permission implicitly; other users need a direct or group grant.
+type aclPermissionService struct {
+	repoManager fileDomain.RepositoryManager
+	groups      GroupMembershipService
+}
+
+// NewACLPermissionService creates a permission service backed by file ACLs
+func NewACLPermissionService(repoManager fileDomain.RepositoryManager, groups GroupMembershipService) PermissionService {
+	return &aclPermissionService{
+		repoManager: repoManager,
+		groups:      groups,
+	}
+}
+
+// CheckFilePermission reports whether the user holds the permission on the
+// file, via ownership, a direct grant, or a grant to one of their groups
+func (s *aclPermissionService) CheckFilePermission(ctx context.Context, userID, fileID uuid.UUID, permission string) (bool, error) {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	// Owners hold every permission implicitly
+	if file.UserID == userID {
+		return true, nil
+	}
+
+	// Direct user grants
+	grants, err := s.repoManager.FilePermission().GetByFileAndUser(ctx, fileID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if grantsInclude(grants, permission) {
+		return true, nil
+	}
+
+	// Group grants, when a membership resolver is wired
+	if s.groups != nil {
+		groupIDs, err := s.groups.GetUserGroupIDs(ctx, userID)
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve group membership: %w", err)
+		}
+		for _, groupID := range groupIDs {
+			groupGrants, err := s.repoManager.FilePermission().GetByFileAndGroup(ctx, fileID, groupID)
+			if err != nil {
+				return false, fmt.Errorf("failed to check group permissions: %w", err)
+			}
+			if grantsInclude(groupGrants, permission) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// GrantFilePermission grants a user a permission on a file
+func (s *aclPermissionService) GrantFilePermission(ctx context.Context, userID, fileID uuid.UUID, permission string) error {
+	if _, ok := permissionImplies[permission]; !ok {
+		return fmt.Errorf("unknown permission %q", permission)
+	}
+
+	grant := &fileDomain.FilePermission{
+		ID:         uuid.New(),
+		FileID:     fileID,
+		UserID:     &userID,
+		Permission: permission,
+		CreatedAt:  time.Now(),
+	}
+	return s.repoManager.FilePermission().Create(ctx, grant)
+}
+
+// RevokeFilePermission revokes a user's direct grants of a permission
+func (s *aclPermissionService) RevokeFilePermission(ctx context.Context, userID, fileID uuid.UUID, permission string) error {
+	grants, err := s.repoManager.FilePermission().GetByFileAndUser(ctx, fileID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list permissions: %w", err)
+	}
+
+	for _, grant := range grants {
+		if grant.Permission != permission {
+			continue
+		}
+		if err := s.repoManager.FilePermission().Delete(ctx, grant.ID); err != nil {
+			return fmt.Errorf("failed to revoke permission: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListFilePermissions lists a user's effective direct permissions on a file
+func (s *aclPermissionService) ListFilePermissions(ctx context.Context, userID, fileID uuid.UUID) ([]string, error) {
+	grants, err := s.repoManager.FilePermission().GetByFileAndUser(ctx, fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	var permissions []string
+	for _, grant := range grants {
+		if grant.ExpiresAt != nil && grant.ExpiresAt.Before(now) {
+			continue
+		}
+		if !seen[grant.Permission] {
+			seen[grant.Permission] = true
+			permissions = append(permissions, grant.Permission)
+		}
+	}
+
+	return permissions, nil
+}
+
+// grantsInclude reports whether any unexpired grant covers the permission
+func grantsInclude(grants []*fileDomain.FilePermission, permission string) bool {
+	now := time.Now()
+	for _, grant := range grants {
+		if grant.ExpiresAt != nil && grant.ExpiresAt.Before(now) {
+			continue
+		}
+		for _, implied := range permissionImplies[grant.Permission] {
+			if implied == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"math/bits"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -64,22 +66,39 @@ func (s *fileService) UploadFile(ctx context.Context, req *UploadFileRequest) (*
 		return nil, fmt.Errorf("insufficient storage space")
 	}
 
-	// Generate file ID and path
+	// Generate file ID
 	fileID := uuid.New()
-	filename := s.generateUniqueFilename(req.Header.Filename)
-	filePath := s.generateFilePath(req.UserID, filename)
 
-	// Calculate checksum
+	// Calculate checksum (also used for path sharding when enabled)
 	checksum, err := s.calculateChecksum(req.File)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate checksum: %w", err)
 	}
 
+	// Generate storage path, regenerating on collision so concurrent uploads
+	// (or sharding/dedup edge cases) never silently overwrite each other
+	filename, filePath, err := s.resolveStoragePath(ctx, req.UserID, req.Header.Filename, checksum)
+	if err != nil {
+		return nil, err
+	}
+
 	// Reset file reader
 	if _, err := req.File.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("failed to reset file reader: %w", err)
 	}
 
+	// Compute a perceptual hash for images so near-duplicates can be found
+	// even when re-encoding changes the checksum
+	perceptualHash := ""
+	if s.isImageFile(req.Header.Header.Get("Content-Type")) {
+		if hash, err := s.imageProcessingService.ComputePerceptualHash(ctx, req.File); err == nil {
+			perceptualHash = hash
+		}
+		if _, err := req.File.Seek(0, 0); err != nil {
+			return nil, fmt.Errorf("failed to reset file reader: %w", err)
+		}
+	}
+
 	// Check for duplicate files
 	if !req.Overwrite {
 		existingFile, err := s.repoManager.File().GetByChecksum(ctx, checksum)
@@ -106,19 +125,20 @@ func (s *fileService) UploadFile(ctx context.Context, req *UploadFileRequest) (*
 
 	// Create file record
 	file := &fileDomain.File{
-		ID:           fileID,
-		UserID:       req.UserID,
-		Filename:     filename,
-		OriginalName: req.Header.Filename,
-		MimeType:     req.Header.Header.Get("Content-Type"),
-		Size:         req.Header.Size,
-		Path:         filePath,
-		URL:          fileURL,
-		Checksum:     checksum,
-		Status:       fileDomain.FileStatusReady,
-		Metadata:     req.Metadata,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:             fileID,
+		UserID:         req.UserID,
+		Filename:       filename,
+		OriginalName:   req.Header.Filename,
+		MimeType:       req.Header.Header.Get("Content-Type"),
+		Size:           req.Header.Size,
+		Path:           filePath,
+		URL:            fileURL,
+		Checksum:       checksum,
+		PerceptualHash: perceptualHash,
+		Status:         fileDomain.FileStatusReady,
+		Metadata:       req.Metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}
 
 	// Save file to database
@@ -205,7 +225,14 @@ func (s *fileService) GetFileContent(ctx context.Context, fileID uuid.UUID, user
 
 	content, err := s.storageService.Retrieve(ctx, file.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve file content: %w", err)
+		// Tolerate files stored before path sharding was enabled (or after it
+		// was disabled) by falling back to the alternate layout
+		if fallbackPath := s.alternateFilePath(file); fallbackPath != file.Path {
+			content, err = s.storageService.Retrieve(ctx, fallbackPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve file content: %w", err)
+		}
 	}
 
 	// Log activity
@@ -361,6 +388,534 @@ func (s *fileService) UpdateFileMetadata(ctx context.Context, fileID uuid.UUID,
 	return nil
 }
 
+// RegenerateImageVariants deletes a file's existing image variants and
+// re-enqueues generation with the current variant configuration. Only the
+// file owner or a user with admin permission on the file may trigger it.
+// With dryRun set, it only reports how many variants would be replaced.
+func (s *fileService) RegenerateImageVariants(ctx context.Context, fileID uuid.UUID, userID uuid.UUID, dryRun bool) (*RegenerateVariantsResponse, error) {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	// Restrict to the owner or users with admin permission on the file
+	if file.UserID != userID {
+		hasPermission, err := s.permissionService.CheckFilePermission(ctx, userID, fileID, "admin")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permissions: %w", err)
+		}
+		if !hasPermission {
+			return nil, fmt.Errorf("access denied")
+		}
+	}
+
+	if !s.isImageFile(file.MimeType) {
+		return nil, fmt.Errorf("file is not an image")
+	}
+
+	variants, err := s.imageProcessingService.GetVariants(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	response := &RegenerateVariantsResponse{
+		FileID:        fileID,
+		ExistingCount: len(variants),
+		DryRun:        dryRun,
+	}
+
+	if dryRun {
+		return response, nil
+	}
+
+	if err := s.imageProcessingService.DeleteVariants(ctx, fileID); err != nil {
+		return nil, fmt.Errorf("failed to delete existing variants: %w", err)
+	}
+
+	go func() {
+		// Use background context for async processing
+		bgCtx := context.Background()
+		if err := s.imageProcessingService.GenerateVariants(bgCtx, file.ID, file.Path); err != nil {
+			fmt.Printf("Failed to regenerate image variants for file %s: %v\n", file.ID, err)
+		}
+	}()
+
+	response.RegenerationRun = true
+	return response, nil
+}
+
+// GetTagFacets returns the user's distinct tags with file counts for faceted
+// browsing, capped at the configured maximum
+func (s *fileService) GetTagFacets(ctx context.Context, userID uuid.UUID) ([]*fileDomain.TagFacet, error) {
+	limit := s.config.FileUpload.MaxTagFacets
+	if limit <= 0 {
+		limit = 50
+	}
+
+	facets, err := s.repoManager.File().GetTagFacets(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag facets: %w", err)
+	}
+	return facets, nil
+}
+
+// BatchGetFiles resolves many file IDs in one repository query, returning
+// the files the user may read plus the IDs that were missing or forbidden.
+// This replaces N individual GetFile calls for clients resolving references.
+func (s *fileService) BatchGetFiles(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*BatchGetFilesResponse, error) {
+	files, err := s.repoManager.File().GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch files: %w", err)
+	}
+
+	found := make(map[uuid.UUID]*fileDomain.File, len(files))
+	for _, file := range files {
+		found[file.ID] = file
+	}
+
+	response := &BatchGetFilesResponse{}
+	for _, id := range ids {
+		file, ok := found[id]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+
+		if file.UserID != userID {
+			hasPermission, err := s.permissionService.CheckFilePermission(ctx, userID, id, "read")
+			if err != nil || !hasPermission {
+				response.Forbidden = append(response.Forbidden, id)
+				continue
+			}
+		}
+
+		response.Files = append(response.Files, file)
+	}
+
+	return response, nil
+}
+
+// storageGrowthGranularities maps granularity names to bucket sizes
+var storageGrowthGranularities = map[string]time.Duration{
+	"hour":  time.Hour,
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// GetStorageGrowth computes the user's cumulative stored bytes over time
+// buckets from file creation and deletion timestamps, for capacity planning
+// and usage trends.
+func (s *fileService) GetStorageGrowth(ctx context.Context, userID uuid.UUID, granularity string, start, end time.Time) (*StorageGrowthResponse, error) {
+	bucketSize, ok := storageGrowthGranularities[granularity]
+	if !ok {
+		return nil, fmt.Errorf("unsupported granularity %q", granularity)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	events, err := s.repoManager.File().GetSizeEvents(ctx, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load size events: %w", err)
+	}
+
+	start = start.Truncate(bucketSize)
+	bucketCount := int(end.Sub(start)/bucketSize) + 1
+
+	response := &StorageGrowthResponse{
+		Granularity: granularity,
+		Points:      make([]*StorageGrowthPoint, bucketCount),
+	}
+	for i := range response.Points {
+		response.Points[i] = &StorageGrowthPoint{Bucket: start.Add(time.Duration(i) * bucketSize)}
+	}
+
+	// Sum size deltas into their buckets; a cumulative baseline accounts for
+	// files created before the range that still existed at its start
+	var baseline int64
+	for _, event := range events {
+		if event.CreatedAt.Before(start) {
+			baseline += event.Size
+		} else if index := int(event.CreatedAt.Sub(start) / bucketSize); index >= 0 && index < bucketCount {
+			response.Points[index].BytesAdded += event.Size
+		}
+
+		if event.DeletedAt != nil && !event.DeletedAt.After(end) {
+			if event.DeletedAt.Before(start) {
+				baseline -= event.Size
+			} else if index := int(event.DeletedAt.Sub(start) / bucketSize); index >= 0 && index < bucketCount {
+				response.Points[index].BytesRemoved += event.Size
+			}
+		}
+	}
+
+	cumulative := baseline
+	for _, point := range response.Points {
+		cumulative += point.BytesAdded - point.BytesRemoved
+		point.CumulativeBytes = cumulative
+	}
+
+	return response, nil
+}
+
+// CleanupExpiredUploadSessions reclaims abandoned resumable upload sessions
+// older than the configured maximum age, reporting how many were abandoned
+// and how many bytes were reclaimed so operators can tune expiry.
+func (s *fileService) CleanupExpiredUploadSessions(ctx context.Context) (*UploadCleanupReport, error) {
+	report := &UploadCleanupReport{}
+
+	const batchSize = 100
+	for {
+		sessions, err := s.repoManager.UploadSession().GetExpired(ctx, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to list expired sessions: %w", err)
+		}
+		if len(sessions) == 0 {
+			break
+		}
+
+		for _, session := range sessions {
+			if err := s.repoManager.UploadSession().Delete(ctx, session.ID); err != nil {
+				fmt.Printf("Failed to delete expired upload session %s: %v\n", session.ID, err)
+				continue
+			}
+			report.AbandonedSessions++
+			report.ReclaimedBytes += session.UploadedSize
+		}
+
+		if len(sessions) < batchSize {
+			break
+		}
+	}
+
+	// Emit an event so operators can track abandoned-upload behavior
+	if report.AbandonedSessions > 0 {
+		details := map[string]interface{}{
+			"abandoned_sessions": report.AbandonedSessions,
+			"reclaimed_bytes":    report.ReclaimedBytes,
+		}
+		if err := s.activityService.LogActivity(ctx, uuid.Nil, "upload_sessions_cleaned", "upload_session", nil, details, "", ""); err != nil {
+			fmt.Printf("Failed to log cleanup event: %v\n", err)
+		}
+	}
+
+	return report, nil
+}
+
+// quarantinePath returns the file's storage key inside the quarantine prefix
+func (s *fileService) quarantinePath(file *fileDomain.File) string {
+	prefix := s.config.Storage.QuarantinePath
+	if prefix == "" {
+		prefix = "quarantine"
+	}
+	return fmt.Sprintf("%s/%s", prefix, file.Path)
+}
+
+// QuarantineFile moves a flagged file into the quarantine storage location,
+// marks it quarantined with the scan details, and sends the owner a security
+// alert. Quarantined files are invisible to normal reads until released.
+func (s *fileService) QuarantineFile(ctx context.Context, fileID uuid.UUID, scanDetails string) error {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.Status == fileDomain.FileStatusQuarantined {
+		return fmt.Errorf("file is already quarantined")
+	}
+
+	if err := s.storageService.Move(ctx, file.Path, s.quarantinePath(file)); err != nil {
+		return fmt.Errorf("failed to move file to quarantine: %w", err)
+	}
+
+	file.Status = fileDomain.FileStatusQuarantined
+	if file.Metadata == nil {
+		file.Metadata = make(map[string]interface{})
+	}
+	file.Metadata["scan_details"] = scanDetails
+	file.Metadata["quarantined_at"] = time.Now().Format(time.RFC3339)
+	file.UpdatedAt = time.Now()
+
+	if err := s.repoManager.File().Update(ctx, file); err != nil {
+		return fmt.Errorf("failed to update file status: %w", err)
+	}
+
+	message := fmt.Sprintf("Your file %q was quarantined by virus scanning: %s", file.OriginalName, scanDetails)
+	if err := s.notificationService.SendSecurityAlertNotification(ctx, file.UserID, message); err != nil {
+		fmt.Printf("Failed to send quarantine alert: %v\n", err)
+	}
+
+	return nil
+}
+
+// ListQuarantinedFiles lists quarantined files with their scan details for
+// admin review
+func (s *fileService) ListQuarantinedFiles(ctx context.Context, limit, offset int) ([]*fileDomain.File, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.repoManager.File().GetByStatus(ctx, fileDomain.FileStatusQuarantined, limit, offset)
+}
+
+// ReleaseQuarantinedFile restores a quarantined file to normal storage and
+// marks it ready again (e.g. after a false-positive review)
+func (s *fileService) ReleaseQuarantinedFile(ctx context.Context, fileID uuid.UUID) error {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.Status != fileDomain.FileStatusQuarantined {
+		return fmt.Errorf("file is not quarantined")
+	}
+
+	if err := s.storageService.Move(ctx, s.quarantinePath(file), file.Path); err != nil {
+		return fmt.Errorf("failed to restore file from quarantine: %w", err)
+	}
+
+	file.Status = fileDomain.FileStatusReady
+	delete(file.Metadata, "scan_details")
+	delete(file.Metadata, "quarantined_at")
+	file.UpdatedAt = time.Now()
+
+	return s.repoManager.File().Update(ctx, file)
+}
+
+// DeleteQuarantinedFile permanently removes a quarantined file
+func (s *fileService) DeleteQuarantinedFile(ctx context.Context, fileID uuid.UUID) error {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.Status != fileDomain.FileStatusQuarantined {
+		return fmt.Errorf("file is not quarantined")
+	}
+
+	if err := s.storageService.Delete(ctx, s.quarantinePath(file)); err != nil {
+		fmt.Printf("Failed to delete quarantined object %s: %v\n", s.quarantinePath(file), err)
+	}
+
+	return s.repoManager.File().Delete(ctx, fileID)
+}
+
+// GetExpiringSoon lists the user's files whose expiry falls within the
+// window, so they can extend TTLs before the files vanish
+func (s *fileService) GetExpiringSoon(ctx context.Context, userID uuid.UUID, within time.Duration) ([]*fileDomain.File, error) {
+	if within <= 0 {
+		within = 72 * time.Hour
+	}
+	return s.repoManager.File().GetExpiringSoon(ctx, userID, time.Now().Add(within), 500)
+}
+
+// NotifyExpiringFiles warns owners about files expiring within the window.
+// Intended to run periodically as a background job; returns how many
+// warnings were sent.
+func (s *fileService) NotifyExpiringFiles(ctx context.Context, within time.Duration) (int, error) {
+	if within <= 0 {
+		within = 72 * time.Hour
+	}
+
+	files, err := s.repoManager.File().GetExpiringSoon(ctx, uuid.Nil, time.Now().Add(within), 500)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expiring files: %w", err)
+	}
+
+	notified := 0
+	for _, file := range files {
+		if file.ExpiresAt == nil {
+			continue
+		}
+		if err := s.notificationService.SendFileExpiryWarningNotification(ctx, file.UserID, file.Filename, *file.ExpiresAt); err != nil {
+			fmt.Printf("Failed to send expiry warning for file %s: %v\n", file.ID, err)
+			continue
+		}
+		notified++
+	}
+
+	return notified, nil
+}
+
+// BackfillChecksums computes checksums for legacy files that lack one by
+// streaming their content from storage and updating the records in batches.
+// Files whose storage object is missing are skipped and counted. Enables
+// dedup and verification on historical data.
+func (s *fileService) BackfillChecksums(ctx context.Context) (*ChecksumBackfillReport, error) {
+	report := &ChecksumBackfillReport{}
+
+	const batchSize = 100
+	for {
+		files, err := s.repoManager.File().GetMissingChecksums(ctx, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to list files missing checksums: %w", err)
+		}
+		if len(files) == 0 {
+			return report, nil
+		}
+
+		progressBefore := report.Updated
+		for _, file := range files {
+			report.Processed++
+
+			content, err := s.storageService.Retrieve(ctx, file.Path)
+			if err != nil {
+				report.MissingStorage++
+				continue
+			}
+
+			checksum, err := s.calculateChecksum(content)
+			content.Close()
+			if err != nil {
+				report.Failed++
+				continue
+			}
+
+			file.Checksum = checksum
+			file.UpdatedAt = time.Now()
+			if err := s.repoManager.File().Update(ctx, file); err != nil {
+				report.Failed++
+				continue
+			}
+			report.Updated++
+		}
+
+		fmt.Printf("Checksum backfill progress: %d processed, %d updated\n", report.Processed, report.Updated)
+
+		// If nothing in this batch could be updated the remaining files are
+		// permanently unprocessable; stop rather than loop forever
+		if report.Updated == progressBefore {
+			return report, nil
+		}
+
+		if len(files) < batchSize {
+			return report, nil
+		}
+	}
+}
+
+// FindOrphans reconciles storage against the database: it reports storage
+// objects with no file/version/variant reference and DB rows whose storage
+// object is missing. With cleanup enabled, unreferenced storage objects are
+// deleted. Intended to run as an admin job for storage hygiene.
+func (s *fileService) FindOrphans(ctx context.Context, cleanup bool) (*OrphanReport, error) {
+	// Collect every path the database knows about
+	referenced := make(map[string]bool)
+
+	filePaths, err := s.repoManager.File().ListPaths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file paths: %w", err)
+	}
+	versionPaths, err := s.repoManager.FileVersion().ListPaths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list version paths: %w", err)
+	}
+	variantPaths, err := s.repoManager.ImageVariant().ListPaths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variant paths: %w", err)
+	}
+
+	allDBPaths := make([]string, 0, len(filePaths)+len(versionPaths)+len(variantPaths))
+	for _, paths := range [][]string{filePaths, versionPaths, variantPaths} {
+		for _, path := range paths {
+			referenced[path] = true
+			allDBPaths = append(allDBPaths, path)
+		}
+	}
+
+	storageKeys, err := s.storageService.ListKeys(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage keys: %w", err)
+	}
+
+	report := &OrphanReport{}
+
+	// Storage objects nothing in the database points at
+	stored := make(map[string]bool, len(storageKeys))
+	for _, key := range storageKeys {
+		stored[key] = true
+		if !referenced[key] {
+			report.StorageOnly = append(report.StorageOnly, key)
+		}
+	}
+
+	// Database rows whose storage object is gone
+	for _, path := range allDBPaths {
+		if !stored[path] {
+			report.DatabaseOnly = append(report.DatabaseOnly, path)
+		}
+	}
+
+	if cleanup {
+		for _, key := range report.StorageOnly {
+			if err := s.storageService.Delete(ctx, key); err != nil {
+				fmt.Printf("Failed to clean up orphaned object %s: %v\n", key, err)
+				continue
+			}
+			report.CleanedUp++
+		}
+	}
+
+	return report, nil
+}
+
+// FindSimilarImages returns the user's images whose perceptual hashes are
+// within the configured Hamming distance of the given file's hash.
+// Non-image files (no stored hash) are rejected.
+func (s *fileService) FindSimilarImages(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) ([]*fileDomain.File, error) {
+	file, err := s.GetFile(ctx, fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if file.PerceptualHash == "" {
+		return nil, fmt.Errorf("file has no perceptual hash")
+	}
+
+	maxDistance := s.config.ImageProcessing.SimilarityMaxDistance
+	if maxDistance <= 0 {
+		maxDistance = 10
+	}
+
+	var similar []*fileDomain.File
+	const pageSize = 500
+	for offset := 0; ; offset += pageSize {
+		candidates, err := s.repoManager.File().GetByUserID(ctx, userID, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+
+		for _, candidate := range candidates {
+			if candidate.ID == file.ID || candidate.PerceptualHash == "" {
+				continue
+			}
+			distance, err := hammingDistanceHex(file.PerceptualHash, candidate.PerceptualHash)
+			if err != nil {
+				continue
+			}
+			if distance <= maxDistance {
+				similar = append(similar, candidate)
+			}
+		}
+
+		if len(candidates) < pageSize {
+			break
+		}
+	}
+
+	return similar, nil
+}
+
+// hammingDistanceHex computes the bit-level Hamming distance between two
+// hex-encoded 64-bit hashes
+func hammingDistanceHex(a, b string) (int, error) {
+	hashA, err := strconv.ParseUint(a, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	hashB, err := strconv.ParseUint(b, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return bits.OnesCount64(hashA ^ hashB), nil
+}
+
 // isImageFile checks if the file is an image based on its MIME type
 func (s *fileService) isImageFile(mimeType string) bool {
 	return strings.HasPrefix(mimeType, "image/")
@@ -389,6 +944,45 @@ func (s *fileService) GetUserStorageStats(ctx context.Context, userID uuid.UUID)
 	}, nil
 }
 
+// PathCollisionError indicates a storage path could not be resolved without
+// colliding with an existing object, even after regeneration
+type PathCollisionError struct {
+	Path     string
+	Attempts int
+}
+
+// Error implements the error interface
+func (e *PathCollisionError) Error() string {
+	return fmt.Sprintf("storage path collision at %s after %d attempts", e.Path, e.Attempts)
+}
+
+// resolveStoragePath generates a unique filename and storage path, retrying
+// with a fresh filename when the path is already occupied. Returns a typed
+// PathCollisionError when the configured retries are exhausted.
+func (s *fileService) resolveStoragePath(ctx context.Context, userID uuid.UUID, originalName, checksum string) (string, string, error) {
+	retries := s.config.FileUpload.MaxCollisionRetries
+	if retries <= 0 {
+		retries = 3
+	}
+
+	var lastPath string
+	for attempt := 0; attempt <= retries; attempt++ {
+		filename := s.generateUniqueFilename(originalName)
+		path := s.generateFilePath(userID, filename, checksum)
+
+		exists, err := s.storageService.Exists(ctx, path)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to check storage path: %w", err)
+		}
+		if !exists {
+			return filename, path, nil
+		}
+		lastPath = path
+	}
+
+	return "", "", &PathCollisionError{Path: lastPath, Attempts: retries + 1}
+}
+
 // Helper functions
 
 func (s *fileService) generateUniqueFilename(originalName string) string {
@@ -399,8 +993,53 @@ func (s *fileService) generateUniqueFilename(originalName string) string {
 	return fmt.Sprintf("%s_%d_%s%s", name, timestamp, uuid, ext)
 }
 
-func (s *fileService) generateFilePath(userID uuid.UUID, filename string) string {
-	return fmt.Sprintf("users/%s/files/%s", userID.String(), filename)
+func (s *fileService) generateFilePath(userID uuid.UUID, filename, checksum string) string {
+	base := fmt.Sprintf("users/%s/files", userID.String())
+	if shard := s.shardPrefix(checksum); shard != "" {
+		return fmt.Sprintf("%s/%s/%s", base, shard, filename)
+	}
+	return fmt.Sprintf("%s/%s", base, filename)
+}
+
+// shardPrefix derives the shard subdirectories from the leading hex chars of
+// the checksum, e.g. "ab/cd" for two levels of two chars. Returns an empty
+// string when sharding is disabled or the checksum is too short.
+func (s *fileService) shardPrefix(checksum string) string {
+	sharding := s.config.Storage.Sharding
+	if !sharding.Enabled || sharding.Levels <= 0 || sharding.CharsPerLevel <= 0 {
+		return ""
+	}
+	if len(checksum) < sharding.Levels*sharding.CharsPerLevel {
+		return ""
+	}
+
+	segments := make([]string, sharding.Levels)
+	for i := 0; i < sharding.Levels; i++ {
+		segments[i] = checksum[i*sharding.CharsPerLevel : (i+1)*sharding.CharsPerLevel]
+	}
+	return strings.Join(segments, "/")
+}
+
+// alternateFilePath returns the file's path in the other layout: the flat
+// layout for a sharded path, or the sharded layout for a flat path. This keeps
+// lookups working for files stored before the sharding configuration changed.
+func (s *fileService) alternateFilePath(file *fileDomain.File) string {
+	base := fmt.Sprintf("users/%s/files", file.UserID.String())
+	if !strings.HasPrefix(file.Path, base+"/") {
+		return file.Path
+	}
+
+	relative := strings.TrimPrefix(file.Path, base+"/")
+	filename := relative[strings.LastIndex(relative, "/")+1:]
+	if relative != filename {
+		// Sharded path: fall back to the old flat layout
+		return fmt.Sprintf("%s/%s", base, filename)
+	}
+	if shard := s.shardPrefix(file.Checksum); shard != "" {
+		// Flat path: try the sharded layout
+		return fmt.Sprintf("%s/%s/%s", base, shard, filename)
+	}
+	return file.Path
 }
 
 func (s *fileService) calculateChecksum(file io.Reader) (string, error) {
@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password attempt limiting defaults for protected shares
+const (
+	shareMaxPasswordAttempts = 5
+	sharePasswordWindow      = 15 * time.Minute
+)
+
+// fileShareService implements the FileShareService interface
+type fileShareService struct {
+	repoManager fileDomain.RepositoryManager
+	config      *config.Config
+
+	// Per-share password attempt tracking
+	mu       sync.Mutex
+	attempts map[string]*shareAttemptWindow
+}
+
+type shareAttemptWindow struct {
+	count       int
+	windowStart time.Time
+}
+
+// NewFileShareService creates a new file share service instance
+func NewFileShareService(repoManager fileDomain.RepositoryManager, config *config.Config) FileShareService {
+	return &fileShareService{
+		repoManager: repoManager,
+		config:      config,
+		attempts:    make(map[string]*shareAttemptWindow),
+	}
+}
+
+// ShareFile creates a share for a file. When a password is supplied it is
+// stored as a bcrypt hash and required on every access to the share.
+func (s *fileShareService) ShareFile(ctx context.Context, req *ShareFileRequest) (*ShareFileResponse, error) {
+	file, err := s.repoManager.File().GetByID(ctx, req.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.UserID != req.UserID {
+		return nil, fmt.Errorf("access denied")
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	share := &fileDomain.FileShare{
+		ID:          uuid.New(),
+		FileID:      req.FileID,
+		SharedBy:    req.UserID,
+		SharedWith:  req.SharedWith,
+		ShareToken:  token,
+		Permissions: req.Permissions,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if len(share.Permissions) == 0 {
+		share.Permissions = []string{"read"}
+	}
+
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash share password: %w", err)
+		}
+		share.PasswordHash = string(hash)
+	}
+
+	if err := s.repoManager.FileShare().Create(ctx, share); err != nil {
+		return nil, fmt.Errorf("failed to create share: %w", err)
+	}
+
+	return &ShareFileResponse{
+		Share:      share,
+		ShareToken: token,
+		ShareURL:   fmt.Sprintf("%s/api/v1/shares/%s", s.config.Services.File.BaseURL, token),
+	}, nil
+}
+
+// GetSharedFile resolves a share token. Password-protected shares require the
+// matching password; attempts are rate limited per share to slow guessing.
+func (s *fileShareService) GetSharedFile(ctx context.Context, token, password string) (*SharedFileResponse, error) {
+	share, err := s.repoManager.FileShare().GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("share not found")
+	}
+
+	if share.ExpiresAt != nil && share.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("share has expired")
+	}
+
+	if share.PasswordHash != "" {
+		if !s.allowPasswordAttempt(share.ID.String()) {
+			return nil, fmt.Errorf("too many password attempts, try again later")
+		}
+		if password == "" {
+			return nil, fmt.Errorf("password required")
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)); err != nil {
+			return nil, fmt.Errorf("invalid password")
+		}
+		s.resetPasswordAttempts(share.ID.String())
+	}
+
+	file, err := s.repoManager.File().GetByID(ctx, share.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared file: %w", err)
+	}
+
+	// Record the access for the sharer's usage statistics
+	now := time.Now()
+	share.AccessCount++
+	share.LastAccessed = &now
+	if err := s.repoManager.FileShare().Update(ctx, share); err != nil {
+		fmt.Printf("Failed to record share access: %v\n", err)
+	}
+
+	return &SharedFileResponse{
+		File:        file,
+		Share:       share,
+		Permissions: share.Permissions,
+		CanDownload: containsPermission(share.Permissions, "read"),
+		CanView:     containsPermission(share.Permissions, "read"),
+	}, nil
+}
+
+// RevokeShare revokes a share created by the user
+func (s *fileShareService) RevokeShare(ctx context.Context, shareID uuid.UUID, userID uuid.UUID) error {
+	share, err := s.repoManager.FileShare().GetByID(ctx, shareID)
+	if err != nil {
+		return fmt.Errorf("share not found")
+	}
+	if share.SharedBy != userID {
+		return fmt.Errorf("access denied")
+	}
+	return s.repoManager.FileShare().Delete(ctx, shareID)
+}
+
+// ListSharedFiles lists shares created by the user
+func (s *fileShareService) ListSharedFiles(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error) {
+	return s.repoManager.FileShare().GetBySharedBy(ctx, userID)
+}
+
+// ListSharesWithStats lists the user's shares together with access counts,
+// last-accessed times and expiry status, for auditing outstanding links
+func (s *fileShareService) ListSharesWithStats(ctx context.Context, userID uuid.UUID) ([]*ShareWithStats, error) {
+	shares, err := s.repoManager.FileShare().GetBySharedBy(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+
+	now := time.Now()
+	stats := make([]*ShareWithStats, 0, len(shares))
+	for _, share := range shares {
+		entry := &ShareWithStats{
+			Share:        share,
+			AccessCount:  share.AccessCount,
+			LastAccessed: share.LastAccessed,
+			Expired:      share.ExpiresAt != nil && share.ExpiresAt.Before(now),
+		}
+		if file, err := s.repoManager.File().GetByID(ctx, share.FileID); err == nil {
+			entry.Filename = file.Filename
+		}
+		stats = append(stats, entry)
+	}
+
+	return stats, nil
+}
+
+// ListFilesSharedWithMe lists shares targeting the user
+func (s *fileShareService) ListFilesSharedWithMe(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error) {
+	return s.repoManager.FileShare().GetBySharedWith(ctx, userID)
+}
+
+// UpdateSharePermissions updates the permission list of a share
+func (s *fileShareService) UpdateSharePermissions(ctx context.Context, shareID uuid.UUID, userID uuid.UUID, permissions []string) error {
+	share, err := s.repoManager.FileShare().GetByID(ctx, shareID)
+	if err != nil {
+		return fmt.Errorf("share not found")
+	}
+	if share.SharedBy != userID {
+		return fmt.Errorf("access denied")
+	}
+
+	share.Permissions = permissions
+	share.UpdatedAt = time.Now()
+	return s.repoManager.FileShare().Update(ctx, share)
+}
+
+// RevokeSharesByFile bulk-revokes every share on a file the user owns,
+// instantly cutting off all shared access to it
+func (s *fileShareService) RevokeSharesByFile(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) (int64, error) {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.UserID != userID {
+		return 0, fmt.Errorf("access denied")
+	}
+
+	return s.repoManager.FileShare().DeleteByFileID(ctx, fileID)
+}
+
+// RevokeSharesByRecipient bulk-revokes everything the user shared with one
+// particular recipient
+func (s *fileShareService) RevokeSharesByRecipient(ctx context.Context, userID uuid.UUID, recipientID uuid.UUID) (int64, error) {
+	return s.repoManager.FileShare().DeleteBySharedWith(ctx, userID, recipientID)
+}
+
+// allowPasswordAttempt records a password attempt for the share and reports
+// whether it is still within the allowed budget for the current window
+func (s *fileShareService) allowPasswordAttempt(shareID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window, exists := s.attempts[shareID]
+	if !exists || time.Since(window.windowStart) > sharePasswordWindow {
+		s.attempts[shareID] = &shareAttemptWindow{count: 1, windowStart: time.Now()}
+		return true
+	}
+
+	window.count++
+	return window.count <= shareMaxPasswordAttempts
+}
+
+// resetPasswordAttempts clears the attempt counter after a successful unlock
+func (s *fileShareService) resetPasswordAttempts(shareID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, shareID)
+}
+
+func containsPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// generateShareToken creates a URL-safe random share token
+func generateShareToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
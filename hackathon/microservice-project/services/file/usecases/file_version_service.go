@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
+	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/google/uuid"
+)
+
+// fileVersionService implements the FileVersionService interface
+type fileVersionService struct {
+	repoManager    fileDomain.RepositoryManager
+	storageService StorageService
+	config         *config.Config
+}
+
+// NewFileVersionService creates a new file version service instance
+func NewFileVersionService(
+	repoManager fileDomain.RepositoryManager,
+	storageService StorageService,
+	config *config.Config,
+) FileVersionService {
+	return &fileVersionService{
+		repoManager:    repoManager,
+		storageService: storageService,
+		config:         config,
+	}
+}
+
+// CreateVersion snapshots new content as the next version of the file and
+// enforces the configured max-versions-per-file policy by pruning the oldest
+// unpinned versions (including their stored content).
+func (s *fileVersionService) CreateVersion(ctx context.Context, fileID uuid.UUID, userID uuid.UUID, content io.Reader) (*fileDomain.FileVersion, error) {
+	file, err := s.getOwnedFile(ctx, fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextVersion := 1
+	if latest, err := s.repoManager.FileVersion().GetLatestByFileID(ctx, fileID); err == nil && latest != nil {
+		nextVersion = latest.Version + 1
+	}
+
+	// Hash while storing so the content is only read once
+	hash := sha256.New()
+	versionPath := fmt.Sprintf("%s.v%d", file.Path, nextVersion)
+	if err := s.storageService.Store(ctx, versionPath, io.TeeReader(content, hash), file.MimeType); err != nil {
+		return nil, fmt.Errorf("failed to store version content: %w", err)
+	}
+
+	size, err := s.storageService.GetSize(ctx, versionPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version size: %w", err)
+	}
+
+	version := &fileDomain.FileVersion{
+		ID:        uuid.New(),
+		FileID:    fileID,
+		Version:   nextVersion,
+		Path:      versionPath,
+		Size:      size,
+		Checksum:  fmt.Sprintf("%x", hash.Sum(nil)),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repoManager.FileVersion().Create(ctx, version); err != nil {
+		return nil, fmt.Errorf("failed to create version record: %w", err)
+	}
+
+	// Enforce the retention policy
+	if err := s.pruneOldVersions(ctx, fileID); err != nil {
+		fmt.Printf("Failed to prune versions for file %s: %v\n", fileID, err)
+	}
+
+	return version, nil
+}
+
+// GetVersions lists versions of a file
+func (s *fileVersionService) GetVersions(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) ([]*fileDomain.FileVersion, error) {
+	if _, err := s.getOwnedFile(ctx, fileID, userID); err != nil {
+		return nil, err
+	}
+	return s.repoManager.FileVersion().GetByFileID(ctx, fileID)
+}
+
+// GetVersion retrieves a single version
+func (s *fileVersionService) GetVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) (*fileDomain.FileVersion, error) {
+	version, err := s.repoManager.FileVersion().GetByID(ctx, versionID)
+	if err != nil {
+		return nil, fmt.Errorf("version not found")
+	}
+	if _, err := s.getOwnedFile(ctx, version.FileID, userID); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetVersionContent retrieves the stored content of a version
+func (s *fileVersionService) GetVersionContent(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) (io.ReadCloser, error) {
+	version, err := s.GetVersion(ctx, versionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.storageService.Retrieve(ctx, version.Path)
+}
+
+// DeleteVersion deletes a version and its stored content
+func (s *fileVersionService) DeleteVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) error {
+	version, err := s.GetVersion(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storageService.Delete(ctx, version.Path); err != nil {
+		fmt.Printf("Failed to delete version content %s: %v\n", version.Path, err)
+	}
+	return s.repoManager.FileVersion().Delete(ctx, versionID)
+}
+
+// RestoreVersion copies a version's content back over the file's current path
+func (s *fileVersionService) RestoreVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) error {
+	version, err := s.GetVersion(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+
+	file, err := s.repoManager.File().GetByID(ctx, version.FileID)
+	if err != nil {
+		return fmt.Errorf("failed to get file: %w", err)
+	}
+
+	if err := s.storageService.Copy(ctx, version.Path, file.Path); err != nil {
+		return fmt.Errorf("failed to restore version content: %w", err)
+	}
+
+	file.Size = version.Size
+	file.Checksum = version.Checksum
+	file.UpdatedAt = time.Now()
+	return s.repoManager.File().Update(ctx, file)
+}
+
+// PinVersion marks or unmarks a version as exempt from retention pruning
+func (s *fileVersionService) PinVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID, pinned bool) error {
+	version, err := s.GetVersion(ctx, versionID, userID)
+	if err != nil {
+		return err
+	}
+
+	version.Pinned = pinned
+	return s.repoManager.FileVersion().Update(ctx, version)
+}
+
+// pruneOldVersions removes the oldest unpinned versions beyond the configured
+// per-file limit, deleting their stored content as well
+func (s *fileVersionService) pruneOldVersions(ctx context.Context, fileID uuid.UUID) error {
+	maxVersions := s.config.FileUpload.MaxVersionsPerFile
+	if maxVersions <= 0 {
+		return nil
+	}
+
+	versions, err := s.repoManager.FileVersion().GetByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to list versions: %w", err)
+	}
+
+	unpinned := make([]*fileDomain.FileVersion, 0, len(versions))
+	for _, version := range versions {
+		if !version.Pinned {
+			unpinned = append(unpinned, version)
+		}
+	}
+
+	excess := len(versions) - maxVersions
+	if excess <= 0 {
+		return nil
+	}
+
+	// Oldest first; pinned versions are never pruned
+	sort.Slice(unpinned, func(i, j int) bool {
+		return unpinned[i].Version < unpinned[j].Version
+	})
+	if excess > len(unpinned) {
+		excess = len(unpinned)
+	}
+
+	for _, version := range unpinned[:excess] {
+		if err := s.storageService.Delete(ctx, version.Path); err != nil {
+			fmt.Printf("Failed to delete pruned version content %s: %v\n", version.Path, err)
+		}
+		if err := s.repoManager.FileVersion().Delete(ctx, version.ID); err != nil {
+			return fmt.Errorf("failed to delete version %s: %w", version.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// getOwnedFile loads a file and verifies the user owns it
+func (s *fileVersionService) getOwnedFile(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) (*fileDomain.File, error) {
+	file, err := s.repoManager.File().GetByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.UserID != userID {
+		return nil, fmt.Errorf("access denied")
+	}
+	return file, nil
+}
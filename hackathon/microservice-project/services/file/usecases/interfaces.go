@@ -21,6 +21,23 @@ type FileService interface {
 	GetFileMetadata(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) (*FileMetadata, error)
 	UpdateFileMetadata(ctx context.Context, fileID uuid.UUID, userID uuid.UUID, metadata map[string]interface{}) error
 	GetUserStorageStats(ctx context.Context, userID uuid.UUID) (*StorageStats, error)
+	RegenerateImageVariants(ctx context.Context, fileID uuid.UUID, userID uuid.UUID, dryRun bool) (*RegenerateVariantsResponse, error)
+	GetTagFacets(ctx context.Context, userID uuid.UUID) ([]*fileDomain.TagFacet, error)
+	FindSimilarImages(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) ([]*fileDomain.File, error)
+	BatchGetFiles(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*BatchGetFilesResponse, error)
+	FindOrphans(ctx context.Context, cleanup bool) (*OrphanReport, error)
+	GetStorageGrowth(ctx context.Context, userID uuid.UUID, granularity string, start, end time.Time) (*StorageGrowthResponse, error)
+	CleanupExpiredUploadSessions(ctx context.Context) (*UploadCleanupReport, error)
+	QuarantineFile(ctx context.Context, fileID uuid.UUID, scanDetails string) error
+	ListQuarantinedFiles(ctx context.Context, limit, offset int) ([]*fileDomain.File, error)
+	ReleaseQuarantinedFile(ctx context.Context, fileID uuid.UUID) error
+	DeleteQuarantinedFile(ctx context.Context, fileID uuid.UUID) error
+	GetExpiringSoon(ctx context.Context, userID uuid.UUID, within time.Duration) ([]*fileDomain.File, error)
+	NotifyExpiringFiles(ctx context.Context, within time.Duration) (int, error)
+	CreateSignedUploadURL(ctx context.Context, userID uuid.UUID, req *SignedUploadRequest) (*SignedUploadResponse, error)
+	DirectUpload(ctx context.Context, path string, expires int64, signature string, content io.Reader, contentType string) error
+	CompleteSignedUpload(ctx context.Context, userID uuid.UUID, registrationToken string) (*fileDomain.File, error)
+	BackfillChecksums(ctx context.Context) (*ChecksumBackfillReport, error)
 }
 
 // ChunkedUploadService defines the interface for chunked upload operations
@@ -36,11 +53,14 @@ type ChunkedUploadService interface {
 // FileShareService defines the interface for file sharing operations
 type FileShareService interface {
 	ShareFile(ctx context.Context, req *ShareFileRequest) (*ShareFileResponse, error)
-	GetSharedFile(ctx context.Context, token string) (*SharedFileResponse, error)
+	GetSharedFile(ctx context.Context, token, password string) (*SharedFileResponse, error)
 	RevokeShare(ctx context.Context, shareID uuid.UUID, userID uuid.UUID) error
 	ListSharedFiles(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error)
+	ListSharesWithStats(ctx context.Context, userID uuid.UUID) ([]*ShareWithStats, error)
 	ListFilesSharedWithMe(ctx context.Context, userID uuid.UUID) ([]*fileDomain.FileShare, error)
 	UpdateSharePermissions(ctx context.Context, shareID uuid.UUID, userID uuid.UUID, permissions []string) error
+	RevokeSharesByFile(ctx context.Context, fileID uuid.UUID, userID uuid.UUID) (int64, error)
+	RevokeSharesByRecipient(ctx context.Context, userID uuid.UUID, recipientID uuid.UUID) (int64, error)
 }
 
 // FileVersionService defines the interface for file versioning operations
@@ -51,6 +71,7 @@ type FileVersionService interface {
 	GetVersionContent(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) (io.ReadCloser, error)
 	DeleteVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) error
 	RestoreVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID) error
+	PinVersion(ctx context.Context, versionID uuid.UUID, userID uuid.UUID, pinned bool) error
 }
 
 // StorageService defines the interface for storage operations
@@ -63,6 +84,7 @@ type StorageService interface {
 	Copy(ctx context.Context, srcPath, destPath string) error
 	Move(ctx context.Context, srcPath, destPath string) error
 	GetSize(ctx context.Context, path string) (int64, error)
+	ListKeys(ctx context.Context, prefix string) ([]string, error)
 }
 
 // PermissionService defines the interface for permission checking
@@ -78,6 +100,8 @@ type NotificationService interface {
 	SendFileUploadedNotification(ctx context.Context, userID uuid.UUID, filename string) error
 	SendFileSharedNotification(ctx context.Context, sharedWith uuid.UUID, filename string, sharedBy string) error
 	SendStorageQuotaNotification(ctx context.Context, userID uuid.UUID, usedSpace, totalSpace int64) error
+	SendSecurityAlertNotification(ctx context.Context, userID uuid.UUID, message string) error
+	SendFileExpiryWarningNotification(ctx context.Context, userID uuid.UUID, filename string, expiresAt time.Time) error
 }
 
 // ActivityService defines the interface for logging activities
@@ -88,8 +112,24 @@ type ActivityService interface {
 // ImageProcessingService defines the interface for image processing operations
 type ImageProcessingService interface {
 	GenerateVariants(ctx context.Context, fileID uuid.UUID, originalPath string) error
+	GenerateVariantOnDemand(ctx context.Context, fileID uuid.UUID, originalPath, variantType string) error
 	GetVariants(ctx context.Context, fileID uuid.UUID) ([]*fileDomain.ImageVariant, error)
 	DeleteVariants(ctx context.Context, fileID uuid.UUID) error
+	ComputePerceptualHash(ctx context.Context, content io.Reader) (string, error)
+	RecordVariantServed(variantType string)
+	GetVariantUsageReport() []*VariantUsage
+	RetryFailedVariants(ctx context.Context) (int, error)
+	StartVariantRetrier(ctx context.Context)
+}
+
+// VariantUsage reports demand for one configured image variant with a
+// recommendation based on how often it is actually served
+type VariantUsage struct {
+	VariantType    string `json:"variant_type"`
+	ServedCount    int64  `json:"served_count"`
+	Configured     bool   `json:"configured"`
+	OnDemand       bool   `json:"on_demand"`
+	Recommendation string `json:"recommendation"` // keep, drop, add, make-on-demand
 }
 
 // Request/Response DTOs
@@ -151,6 +191,58 @@ type FileMetadata struct {
 	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
+type BatchGetFilesRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+type BatchGetFilesResponse struct {
+	Files     []*fileDomain.File `json:"files"`
+	Missing   []uuid.UUID        `json:"missing,omitempty"`
+	Forbidden []uuid.UUID        `json:"forbidden,omitempty"`
+}
+
+// StorageGrowthPoint is one bucket of the storage growth time series
+type StorageGrowthPoint struct {
+	Bucket          time.Time `json:"bucket"`
+	BytesAdded      int64     `json:"bytes_added"`
+	BytesRemoved    int64     `json:"bytes_removed"`
+	CumulativeBytes int64     `json:"cumulative_bytes"`
+}
+
+type StorageGrowthResponse struct {
+	Granularity string                `json:"granularity"`
+	Points      []*StorageGrowthPoint `json:"points"`
+}
+
+// UploadCleanupReport summarizes one expired-upload-session cleanup run
+type UploadCleanupReport struct {
+	AbandonedSessions int   `json:"abandoned_sessions"`
+	ReclaimedBytes    int64 `json:"reclaimed_bytes"`
+}
+
+// ChecksumBackfillReport summarizes one checksum backfill run
+type ChecksumBackfillReport struct {
+	Processed      int `json:"processed"`
+	Updated        int `json:"updated"`
+	MissingStorage int `json:"missing_storage"`
+	Failed         int `json:"failed"`
+}
+
+// OrphanReport lists storage objects without DB references and DB rows
+// whose storage object is missing
+type OrphanReport struct {
+	StorageOnly  []string `json:"storage_only"`  // storage keys with no DB reference
+	DatabaseOnly []string `json:"database_only"` // DB paths with no storage object
+	CleanedUp    int      `json:"cleaned_up"`    // storage-only objects deleted (cleanup mode)
+}
+
+type RegenerateVariantsResponse struct {
+	FileID          uuid.UUID `json:"file_id"`
+	ExistingCount   int       `json:"existing_count"`
+	DryRun          bool      `json:"dry_run"`
+	RegenerationRun bool      `json:"regeneration_run"`
+}
+
 type StorageStats struct {
 	UsedSpace  int64   `json:"used_space"`
 	TotalSpace int64   `json:"total_space"`
@@ -211,6 +303,17 @@ type ShareFileRequest struct {
 	Permissions []string   `json:"permissions"`
 	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 	Public      bool       `json:"public"`
+	Password    string     `json:"password,omitempty"` // Optional password protecting the share
+}
+
+// ShareWithStats combines a share with its usage statistics so users can
+// audit their outstanding links
+type ShareWithStats struct {
+	Share        *fileDomain.FileShare `json:"share"`
+	Filename     string                `json:"filename"`
+	AccessCount  int64                 `json:"access_count"`
+	LastAccessed *time.Time            `json:"last_accessed,omitempty"`
+	Expired      bool                  `json:"expired"`
 }
 
 type ShareFileResponse struct {
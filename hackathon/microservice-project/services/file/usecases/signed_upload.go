@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	fileDomain "github.com/elotusteam/microservice-project/services/file/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultSignedUploadTTL bounds how long a pre-signed upload URL stays valid
+const DefaultSignedUploadTTL = 15 * time.Minute
+
+// SignedUploadRequest asks for a pre-signed direct-to-storage upload URL
+type SignedUploadRequest struct {
+	Filename    string `json:"filename" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+// SignedUploadResponse carries the signed URL and the registration token the
+// client presents back once the direct upload finished
+type SignedUploadResponse struct {
+	UploadURL         string    `json:"upload_url"`
+	RegistrationToken string    `json:"registration_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// signedUploadClaims is the payload embedded in the registration token
+type signedUploadClaims struct {
+	UserID      uuid.UUID `json:"user_id"`
+	FileID      uuid.UUID `json:"file_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Path        string    `json:"path"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// signingKey returns the key used to sign upload URLs and tokens
+func (s *fileService) signingKey() string {
+	if key := s.config.Storage.CDN.SigningKey; key != "" {
+		return key
+	}
+	return s.config.Storage.SecretKey
+}
+
+// CreateSignedUploadURL issues a short-lived signed URL for a direct upload
+// to the configured S3-compatible backend, plus a registration token used to
+// finalize the File record after the client confirms the upload. This keeps
+// large-upload bandwidth off the service itself.
+func (s *fileService) CreateSignedUploadURL(ctx context.Context, userID uuid.UUID, req *SignedUploadRequest) (*SignedUploadResponse, error) {
+	if req.Size <= 0 || req.Size > s.config.FileUpload.MaxFileSize {
+		return nil, fmt.Errorf("size must be between 1 and %d bytes", s.config.FileUpload.MaxFileSize)
+	}
+	key := s.signingKey()
+	if key == "" {
+		return nil, fmt.Errorf("signed uploads are not configured (missing signing key)")
+	}
+
+	claims := signedUploadClaims{
+		UserID:      userID,
+		FileID:      uuid.New(),
+		Filename:    s.generateUniqueFilename(req.Filename),
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		ExpiresAt:   time.Now().Add(DefaultSignedUploadTTL),
+	}
+	claims.Path = fmt.Sprintf("users/%s/files/%s", userID, claims.Filename)
+
+	token, err := s.encodeUploadToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signed PUT URL against the file service's direct-upload endpoint,
+	// which verifies the signature and expiry before writing to storage
+	expires := claims.ExpiresAt.Unix()
+	signature := signUploadURL(key, claims.Path, expires)
+	uploadURL := fmt.Sprintf("%s/api/v1/uploads/direct/%s?expires=%d&signature=%s",
+		strings.TrimRight(s.config.Services.File.BaseURL, "/"), claims.Path, expires, signature)
+
+	return &SignedUploadResponse{
+		UploadURL:         uploadURL,
+		RegistrationToken: token,
+		ExpiresAt:         claims.ExpiresAt,
+	}, nil
+}
+
+// CompleteSignedUpload finalizes the File record once the client confirms
+// the direct upload. The registration token binds the record to the signed
+// request, and the object's presence in storage is verified.
+func (s *fileService) CompleteSignedUpload(ctx context.Context, userID uuid.UUID, registrationToken string) (*fileDomain.File, error) {
+	claims, err := s.decodeUploadToken(registrationToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.UserID != userID {
+		return nil, fmt.Errorf("registration token belongs to a different user")
+	}
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, fmt.Errorf("registration token has expired")
+	}
+
+	exists, err := s.storageService.Exists(ctx, claims.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify upload: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("uploaded object not found in storage")
+	}
+
+	size, err := s.storageService.GetSize(ctx, claims.Path)
+	if err != nil {
+		size = claims.Size
+	}
+
+	fileURL, err := s.storageService.GetURL(ctx, claims.Path, 24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate file URL: %w", err)
+	}
+
+	file := &fileDomain.File{
+		ID:           claims.FileID,
+		UserID:       claims.UserID,
+		Filename:     claims.Filename,
+		OriginalName: claims.Filename,
+		MimeType:     claims.ContentType,
+		Size:         size,
+		Path:         claims.Path,
+		URL:          fileURL,
+		Status:       fileDomain.FileStatusReady,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.repoManager.File().Create(ctx, file); err != nil {
+		return nil, fmt.Errorf("failed to save file record: %w", err)
+	}
+
+	return file, nil
+}
+
+// DirectUpload accepts a direct-to-storage upload against a pre-signed URL,
+// verifying the signature and expiry before any bytes are written. This is
+// the server-side check that makes the signed URL actually time-limited.
+func (s *fileService) DirectUpload(ctx context.Context, path string, expires int64, signature string, content io.Reader, contentType string) error {
+	key := s.signingKey()
+	if key == "" {
+		return fmt.Errorf("signed uploads are not configured (missing signing key)")
+	}
+
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("upload URL has expired")
+	}
+
+	expected := signUploadURL(key, path, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid upload signature")
+	}
+
+	if err := s.storageService.Store(ctx, path, content, contentType); err != nil {
+		return fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	return nil
+}
+
+// encodeUploadToken serializes and signs the registration token
+func (s *fileService) encodeUploadToken(claims signedUploadClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode upload token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(s.signingKey()))
+	mac.Write([]byte(encoded))
+	return encoded + "." + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// decodeUploadToken verifies and deserializes a registration token
+func (s *fileService) decodeUploadToken(token string) (*signedUploadClaims, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid registration token")
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.signingKey()))
+	mac.Write([]byte(parts[0]))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(parts[1])) {
+		return nil, fmt.Errorf("invalid registration token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid registration token")
+	}
+
+	var claims signedUploadClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid registration token")
+	}
+	return &claims, nil
+}
+
+// signUploadURL computes the signature protecting a pre-signed upload URL
+func signUploadURL(key, path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(fmt.Sprintf("PUT\n%s\n%d", path, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -14,6 +14,9 @@ import (
 // ServiceContainer holds all the services
 type ServiceContainer struct {
 	FileService            usecases.FileService
+	FileShareService       usecases.FileShareService
+	FileVersionService     usecases.FileVersionService
+	PermissionService      usecases.PermissionService
 	ImageProcessingService usecases.ImageProcessingService
 }
 
@@ -69,8 +72,10 @@ func NewServiceContainer(cfg *config.Config) (*ServiceContainer, error) {
 		cfg,
 	)
 
-	// Create real HTTP client services
-	permissionService := clients.NewPermissionClient(cfg.Services.Auth.BaseURL)
+	// Create real HTTP client services. Permissions are enforced by the file
+	// service's own ACLs; group membership resolution can be wired to the
+	// user service later.
+	permissionService := usecases.NewACLPermissionService(repoManager, nil)
 	notificationService := clients.NewNotificationClient(cfg.Services.Notification.BaseURL)
 	activityService := clients.NewActivityClient(cfg.Services.Analytics.BaseURL)
 
@@ -85,8 +90,17 @@ func NewServiceContainer(cfg *config.Config) (*ServiceContainer, error) {
 		cfg,
 	)
 
+	// Create file share service
+	fileShareService := usecases.NewFileShareService(repoManager, cfg)
+
+	// Create file version service
+	fileVersionService := usecases.NewFileVersionService(repoManager, storageService, cfg)
+
 	return &ServiceContainer{
 		FileService:            fileService,
+		FileShareService:       fileShareService,
+		FileVersionService:     fileVersionService,
+		PermissionService:      permissionService,
 		ImageProcessingService: imageProcessingService,
 	}, nil
 }
@@ -6,13 +6,14 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/gateway/middleware"
 	"github.com/elotusteam/microservice-project/services/gateway/routes"
 	"github.com/elotusteam/microservice-project/shared/config"
+	sharedMiddleware "github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
 )
 
@@ -41,6 +42,14 @@ func main() {
 	router.Use(middleware.RateLimit(cfg))
 	router.Use(middleware.Security(cfg))
 
+	// Return 405 with an Allow header for wrong-method requests
+	sharedMiddleware.ConfigureMethodNotAllowed(router)
+
+	// Cap concurrent in-flight requests per IP to block slow-loris-style
+	// resource exhaustion; configurable via GATEWAY_MAX_CONCURRENT_PER_IP
+	concurrencyLimiter := sharedMiddleware.NewConcurrencyLimiter(getEnvAsInt("GATEWAY_MAX_CONCURRENT_PER_IP", 32))
+	router.Use(concurrencyLimiter.Middleware())
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -63,27 +72,19 @@ func main() {
 		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		log.Printf("API Gateway starting on port %d", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down API Gateway...")
-
-	// Give outstanding requests 30 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("API Gateway forced to shutdown: %v", err)
+	// Run with graceful shutdown handled by the shared helper
+	log.Printf("API Gateway starting on port %d", cfg.Server.Port)
+	if err := sharedServer.RunServer(context.Background(), server, 30*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
+}
 
-	log.Println("API Gateway exited")
+// Helper functions
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
 }
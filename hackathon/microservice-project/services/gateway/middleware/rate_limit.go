@@ -2,39 +2,64 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/elotusteam/microservice-project/shared/config"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter holds the rate limiter for each IP
+// rateLimitWarningsTotal counts requests served in the soft-limit warning
+// phase, before the hard limit starts blocking
+var rateLimitWarningsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_rate_limit_warnings_total",
+	Help: "Requests served with a rate limit warning (soft threshold exceeded)",
+})
+
+func init() {
+	prometheus.MustRegister(rateLimitWarningsTotal)
+}
+
+// ipLimiters holds the soft and hard limiters for one client IP. The soft
+// limiter fires first and only adds a warning; the hard limiter blocks.
+type ipLimiters struct {
+	soft *rate.Limiter
+	hard *rate.Limiter
+}
+
+// RateLimiter holds the rate limiters for each IP
 type RateLimiter struct {
-	limiters map[string]*rate.Limiter
+	limiters map[string]*ipLimiters
 	mu       sync.RWMutex
-	rate     rate.Limit
+	softRate rate.Limit
+	hardRate rate.Limit
 	burst    int
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(r rate.Limit, b int) *RateLimiter {
+// NewRateLimiter creates a new two-tier rate limiter
+func NewRateLimiter(softRate, hardRate rate.Limit, b int) *RateLimiter {
 	return &RateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		rate:     r,
+		limiters: make(map[string]*ipLimiters),
+		softRate: softRate,
+		hardRate: hardRate,
 		burst:    b,
 	}
 }
 
-// GetLimiter returns the rate limiter for the given IP
-func (rl *RateLimiter) GetLimiter(ip string) *rate.Limiter {
+// GetLimiter returns the limiters for the given IP
+func (rl *RateLimiter) GetLimiter(ip string) *ipLimiters {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	limiter, exists := rl.limiters[ip]
 	if !exists {
-		limiter = rate.NewLimiter(rl.rate, rl.burst)
+		limiter = &ipLimiters{
+			soft: rate.NewLimiter(rl.softRate, rl.burst),
+			hard: rate.NewLimiter(rl.hardRate, rl.burst),
+		}
 		rl.limiters[ip] = limiter
 	}
 
@@ -49,32 +74,54 @@ func (rl *RateLimiter) CleanupOldEntries() {
 	// Clear all limiters periodically to prevent memory leaks
 	// In a production environment, you might want to implement a more sophisticated cleanup
 	if len(rl.limiters) > 1000 {
-		rl.limiters = make(map[string]*rate.Limiter)
+		rl.limiters = make(map[string]*ipLimiters)
 	}
 }
 
 var globalRateLimiter *RateLimiter
 var once sync.Once
 
+// Configured rule values surfaced in the X-RateLimit-* response headers
+var (
+	rateLimitHeaderLimit  int
+	rateLimitHeaderWindow time.Duration
+)
+
 // RateLimit middleware implements rate limiting per IP address
 func RateLimit(cfg *config.Config) gin.HandlerFunc {
 	once.Do(func() {
-		// Default rate limit: 100 requests per minute
-		rateLimit := rate.Limit(100.0 / 60.0) // requests per second
+		// Default hard limit: 100 requests per minute
+		hardLimit := rate.Limit(100.0 / 60.0) // requests per second
 		burst := 10
 
 		if cfg.RateLimit.PerIP.Requests > 0 {
 			// Convert requests per window to requests per second
 			windowSeconds := cfg.RateLimit.PerIP.Window.Seconds()
 			if windowSeconds > 0 {
-				rateLimit = rate.Limit(float64(cfg.RateLimit.PerIP.Requests) / windowSeconds)
+				hardLimit = rate.Limit(float64(cfg.RateLimit.PerIP.Requests) / windowSeconds)
 			}
 		}
 		if cfg.RateLimit.PerIP.Burst > 0 {
 			burst = cfg.RateLimit.PerIP.Burst
 		}
 
-		globalRateLimiter = NewRateLimiter(rateLimit, burst)
+		// The soft (warning) threshold defaults to 80% of the hard limit
+		softLimit := hardLimit * 8 / 10
+		if cfg.RateLimit.PerIP.SoftRequests > 0 {
+			windowSeconds := cfg.RateLimit.PerIP.Window.Seconds()
+			if windowSeconds > 0 {
+				softLimit = rate.Limit(float64(cfg.RateLimit.PerIP.SoftRequests) / windowSeconds)
+			}
+		}
+
+		globalRateLimiter = NewRateLimiter(softLimit, hardLimit, burst)
+
+		rateLimitHeaderLimit = cfg.RateLimit.PerIP.Requests
+		rateLimitHeaderWindow = cfg.RateLimit.PerIP.Window
+		if rateLimitHeaderLimit <= 0 {
+			rateLimitHeaderLimit = 100
+			rateLimitHeaderWindow = time.Minute
+		}
 
 		// Start cleanup goroutine
 		go func() {
@@ -90,11 +137,15 @@ func RateLimit(cfg *config.Config) gin.HandlerFunc {
 		// Get client IP
 		clientIP := c.ClientIP()
 
-		// Get rate limiter for this IP
+		// Get rate limiters for this IP
 		limiter := globalRateLimiter.GetLimiter(clientIP)
 
-		// Check if request is allowed
-		if !limiter.Allow() {
+		// Surface the client's rate-limit state on every response so
+		// well-behaved clients can self-throttle
+		setRateLimitHeaders(c, limiter)
+
+		// The hard threshold blocks the request outright
+		if !limiter.hard.Allow() {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "Rate limit exceeded",
 				"message": "Too many requests. Please try again later.",
@@ -103,6 +154,35 @@ func RateLimit(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// Exceeding only the soft threshold serves the request with a
+		// warning so clients can back off before being blocked
+		if !limiter.soft.Allow() {
+			c.Header("X-RateLimit-Warning", "approaching rate limit; reduce request rate")
+			rateLimitWarningsTotal.Inc()
+		}
+
 		c.Next()
 	}
 }
+
+// setRateLimitHeaders sets the standard X-RateLimit-Limit/Remaining/Reset
+// headers computed from the configured rule and the IP's current tokens
+func setRateLimitHeaders(c *gin.Context, limiter *ipLimiters) {
+	remaining := int(limiter.hard.Tokens())
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	// Reset: when the bucket is projected to be full again at the refill rate
+	reset := time.Now()
+	if rate := float64(limiter.hard.Limit()); rate > 0 {
+		missing := float64(globalRateLimiter.burst) - limiter.hard.Tokens()
+		if missing > 0 {
+			reset = reset.Add(time.Duration(missing / rate * float64(time.Second)))
+		}
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(rateLimitHeaderLimit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+}
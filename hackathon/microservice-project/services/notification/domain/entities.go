@@ -74,6 +74,7 @@ type NotificationPreference struct {
 	Enabled         bool             `json:"enabled" db:"enabled"`
 	QuietHoursStart *time.Time       `json:"quiet_hours_start" db:"quiet_hours_start"`
 	QuietHoursEnd   *time.Time       `json:"quiet_hours_end" db:"quiet_hours_end"`
+	Timezone        string           `json:"timezone" db:"timezone"` // IANA name used to interpret quiet hours
 	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
 }
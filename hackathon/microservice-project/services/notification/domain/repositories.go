@@ -18,6 +18,10 @@ type NotificationRepository interface {
 	// GetByUserID retrieves notifications for a user with pagination
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Notification, error)
 
+	// GetByUserIDKeyset retrieves notifications for a user ordered by
+	// (created_at, id) descending, starting after the cursor position
+	GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, beforeCreatedAt time.Time, beforeID uuid.UUID, limit int) ([]*Notification, error)
+
 	// GetUnreadByUserID retrieves unread notifications for a user
 	GetUnreadByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Notification, error)
 
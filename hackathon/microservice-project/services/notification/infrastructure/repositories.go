@@ -0,0 +1,393 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/notification/domain"
+	"github.com/google/uuid"
+)
+
+// MockRepositoryManager implements domain.RepositoryManager with in-memory
+// storage, mirroring the mock repository managers the other services run
+// with until a database backend is wired in
+type MockRepositoryManager struct {
+	mu            sync.RWMutex
+	notifications map[uuid.UUID]*domain.Notification
+	templates     map[uuid.UUID]*domain.NotificationTemplate
+	preferences   map[uuid.UUID]*domain.NotificationPreference
+}
+
+// NewMockRepositoryManager creates a new in-memory repository manager
+func NewMockRepositoryManager() domain.RepositoryManager {
+	return &MockRepositoryManager{
+		notifications: make(map[uuid.UUID]*domain.Notification),
+		templates:     make(map[uuid.UUID]*domain.NotificationTemplate),
+		preferences:   make(map[uuid.UUID]*domain.NotificationPreference),
+	}
+}
+
+// Notification returns the notification repository
+func (m *MockRepositoryManager) Notification() domain.NotificationRepository {
+	return &mockNotificationRepository{manager: m}
+}
+
+// Template returns the notification template repository
+func (m *MockRepositoryManager) Template() domain.NotificationTemplateRepository {
+	return &mockTemplateRepository{manager: m}
+}
+
+// Preference returns the notification preference repository
+func (m *MockRepositoryManager) Preference() domain.NotificationPreferenceRepository {
+	return &mockPreferenceRepository{manager: m}
+}
+
+// BeginTx begins a transaction; the in-memory implementation is not
+// transactional so the manager itself is returned
+func (m *MockRepositoryManager) BeginTx(ctx context.Context) (domain.RepositoryManager, error) {
+	return m, nil
+}
+
+// Commit commits the current transaction (no-op for in-memory storage)
+func (m *MockRepositoryManager) Commit() error { return nil }
+
+// Rollback rolls back the current transaction (no-op for in-memory storage)
+func (m *MockRepositoryManager) Rollback() error { return nil }
+
+// mockNotificationRepository implements domain.NotificationRepository
+type mockNotificationRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockNotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.notifications[notification.ID] = notification
+	return nil
+}
+
+func (r *mockNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	notification, exists := r.manager.notifications[id]
+	if !exists {
+		return nil, fmt.Errorf("notification not found")
+	}
+	return notification, nil
+}
+
+func (r *mockNotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Notification, error) {
+	return r.userNotifications(userID, false, limit, offset)
+}
+
+func (r *mockNotificationRepository) GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, beforeCreatedAt time.Time, beforeID uuid.UUID, limit int) ([]*domain.Notification, error) {
+	all, err := r.userNotifications(userID, false, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var page []*domain.Notification
+	for _, notification := range all {
+		// Skip rows at or after the cursor position in (created_at, id)
+		// descending order; a zero cursor starts from the newest row
+		if !beforeCreatedAt.IsZero() {
+			if notification.CreatedAt.After(beforeCreatedAt) {
+				continue
+			}
+			if notification.CreatedAt.Equal(beforeCreatedAt) && notification.ID.String() >= beforeID.String() {
+				continue
+			}
+		}
+		page = append(page, notification)
+		if limit > 0 && len(page) >= limit {
+			break
+		}
+	}
+	return page, nil
+}
+
+func (r *mockNotificationRepository) GetUnreadByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Notification, error) {
+	return r.userNotifications(userID, true, limit, offset)
+}
+
+func (r *mockNotificationRepository) GetPendingNotifications(ctx context.Context, limit int) ([]*domain.Notification, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var pending []*domain.Notification
+	for _, notification := range r.manager.notifications {
+		if notification.Status == domain.NotificationStatusPending {
+			pending = append(pending, notification)
+			if limit > 0 && len(pending) >= limit {
+				break
+			}
+		}
+	}
+	return pending, nil
+}
+
+func (r *mockNotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.NotificationStatus) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	notification, exists := r.manager.notifications[id]
+	if !exists {
+		return fmt.Errorf("notification not found")
+	}
+	notification.Status = status
+	notification.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *mockNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID, readAt time.Time) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	notification, exists := r.manager.notifications[id]
+	if !exists {
+		return fmt.Errorf("notification not found")
+	}
+	notification.ReadAt = &readAt
+	notification.Status = domain.NotificationStatusRead
+	notification.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *mockNotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID, readAt time.Time) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	for _, notification := range r.manager.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			at := readAt
+			notification.ReadAt = &at
+			notification.Status = domain.NotificationStatusRead
+			notification.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *mockNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.notifications[id]; !exists {
+		return fmt.Errorf("notification not found")
+	}
+	delete(r.manager.notifications, id)
+	return nil
+}
+
+func (r *mockNotificationRepository) GetCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var count int64
+	for _, notification := range r.manager.notifications {
+		if notification.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *mockNotificationRepository) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var count int64
+	for _, notification := range r.manager.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// userNotifications returns a user's notifications ordered by (created_at,
+// id) descending, optionally restricted to unread rows
+func (r *mockNotificationRepository) userNotifications(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*domain.Notification, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+
+	var notifications []*domain.Notification
+	for _, notification := range r.manager.notifications {
+		if notification.UserID != userID {
+			continue
+		}
+		if unreadOnly && notification.ReadAt != nil {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		if !notifications[i].CreatedAt.Equal(notifications[j].CreatedAt) {
+			return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+		}
+		return notifications[i].ID.String() > notifications[j].ID.String()
+	})
+
+	if offset > 0 {
+		if offset >= len(notifications) {
+			return nil, nil
+		}
+		notifications = notifications[offset:]
+	}
+	if limit > 0 && len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+// mockTemplateRepository implements domain.NotificationTemplateRepository
+type mockTemplateRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockTemplateRepository) Create(ctx context.Context, template *domain.NotificationTemplate) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.templates[template.ID] = template
+	return nil
+}
+
+func (r *mockTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.NotificationTemplate, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	template, exists := r.manager.templates[id]
+	if !exists {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, nil
+}
+
+func (r *mockTemplateRepository) GetByName(ctx context.Context, name string) (*domain.NotificationTemplate, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, template := range r.manager.templates {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+	return nil, fmt.Errorf("template not found")
+}
+
+func (r *mockTemplateRepository) GetByType(ctx context.Context, notificationType domain.NotificationType) ([]*domain.NotificationTemplate, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var templates []*domain.NotificationTemplate
+	for _, template := range r.manager.templates {
+		if template.Type == notificationType {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func (r *mockTemplateRepository) GetActive(ctx context.Context) ([]*domain.NotificationTemplate, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var templates []*domain.NotificationTemplate
+	for _, template := range r.manager.templates {
+		if template.IsActive {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func (r *mockTemplateRepository) Update(ctx context.Context, template *domain.NotificationTemplate) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.templates[template.ID]; !exists {
+		return fmt.Errorf("template not found")
+	}
+	r.manager.templates[template.ID] = template
+	return nil
+}
+
+func (r *mockTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.templates[id]; !exists {
+		return fmt.Errorf("template not found")
+	}
+	delete(r.manager.templates, id)
+	return nil
+}
+
+// mockPreferenceRepository implements domain.NotificationPreferenceRepository
+type mockPreferenceRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockPreferenceRepository) Create(ctx context.Context, preference *domain.NotificationPreference) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.preferences[preference.ID] = preference
+	return nil
+}
+
+func (r *mockPreferenceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.NotificationPreference, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var preferences []*domain.NotificationPreference
+	for _, preference := range r.manager.preferences {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (r *mockPreferenceRepository) GetByUserIDAndType(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType) (*domain.NotificationPreference, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, preference := range r.manager.preferences {
+		if preference.UserID == userID && preference.Type == notificationType {
+			return preference, nil
+		}
+	}
+	return nil, fmt.Errorf("preference not found")
+}
+
+func (r *mockPreferenceRepository) Update(ctx context.Context, preference *domain.NotificationPreference) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.preferences[preference.ID]; !exists {
+		return fmt.Errorf("preference not found")
+	}
+	r.manager.preferences[preference.ID] = preference
+	return nil
+}
+
+func (r *mockPreferenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.preferences[id]; !exists {
+		return fmt.Errorf("preference not found")
+	}
+	delete(r.manager.preferences, id)
+	return nil
+}
+
+func (r *mockPreferenceRepository) CreateDefaultPreferences(ctx context.Context, userID uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	now := time.Now()
+	for _, notificationType := range []domain.NotificationType{
+		domain.NotificationTypeEmail,
+		domain.NotificationTypeSMS,
+		domain.NotificationTypePush,
+		domain.NotificationTypeInApp,
+	} {
+		preference := &domain.NotificationPreference{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Type:      notificationType,
+			Enabled:   true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		r.manager.preferences[preference.ID] = preference
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/notification/domain"
+	"github.com/elotusteam/microservice-project/services/notification/usecases"
+	"github.com/google/uuid"
+)
+
+// RepositoryTemplateService implements usecases.NotificationTemplateService
+// on top of the template repository
+type RepositoryTemplateService struct {
+	repoManager domain.RepositoryManager
+}
+
+// NewRepositoryTemplateService creates a repository-backed template service
+func NewRepositoryTemplateService(repoManager domain.RepositoryManager) usecases.NotificationTemplateService {
+	return &RepositoryTemplateService{repoManager: repoManager}
+}
+
+// CreateTemplate creates a new notification template
+func (s *RepositoryTemplateService) CreateTemplate(ctx context.Context, req *usecases.CreateTemplateRequest) (*domain.NotificationTemplate, error) {
+	now := time.Now()
+	template := &domain.NotificationTemplate{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Type:      req.Type,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Variables: req.Variables,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repoManager.Template().Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return template, nil
+}
+
+// GetTemplate retrieves a template by ID
+func (s *RepositoryTemplateService) GetTemplate(ctx context.Context, id uuid.UUID) (*domain.NotificationTemplate, error) {
+	return s.repoManager.Template().GetByID(ctx, id)
+}
+
+// GetTemplateByName retrieves a template by name
+func (s *RepositoryTemplateService) GetTemplateByName(ctx context.Context, name string) (*domain.NotificationTemplate, error) {
+	return s.repoManager.Template().GetByName(ctx, name)
+}
+
+// UpdateTemplate updates a notification template
+func (s *RepositoryTemplateService) UpdateTemplate(ctx context.Context, template *domain.NotificationTemplate) error {
+	template.UpdatedAt = time.Now()
+	return s.repoManager.Template().Update(ctx, template)
+}
+
+// DeleteTemplate deletes a notification template
+func (s *RepositoryTemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	return s.repoManager.Template().Delete(ctx, id)
+}
+
+// RenderTemplate renders a template's subject and body, substituting
+// {{variable}} placeholders with the provided data
+func (s *RepositoryTemplateService) RenderTemplate(ctx context.Context, templateName string, data map[string]interface{}) (string, string, error) {
+	template, err := s.repoManager.Template().GetByName(ctx, templateName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load template: %w", err)
+	}
+
+	subject := template.Subject
+	body := template.Body
+	for key, value := range data {
+		placeholder := "{{" + key + "}}"
+		replacement := fmt.Sprintf("%v", value)
+		subject = strings.ReplaceAll(subject, placeholder, replacement)
+		body = strings.ReplaceAll(body, placeholder, replacement)
+	}
+	return subject, body, nil
+}
+
+// RepositoryPreferenceService implements
+// usecases.NotificationPreferenceService on top of the preference repository
+type RepositoryPreferenceService struct {
+	repoManager domain.RepositoryManager
+}
+
+// NewRepositoryPreferenceService creates a repository-backed preference
+// service
+func NewRepositoryPreferenceService(repoManager domain.RepositoryManager) usecases.NotificationPreferenceService {
+	return &RepositoryPreferenceService{repoManager: repoManager}
+}
+
+// GetPreferences retrieves notification preferences for a user
+func (s *RepositoryPreferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*domain.NotificationPreference, error) {
+	return s.repoManager.Preference().GetByUserID(ctx, userID)
+}
+
+// UpdatePreferences updates notification preferences for a user
+func (s *RepositoryPreferenceService) UpdatePreferences(ctx context.Context, req *usecases.UpdatePreferencesRequest) error {
+	for _, preference := range req.Preferences {
+		preference.UserID = req.UserID
+		preference.UpdatedAt = time.Now()
+		if err := s.repoManager.Preference().Update(ctx, preference); err != nil {
+			// Unknown preferences are created rather than rejected so a
+			// full preference set can be submitted in one request
+			preference.CreatedAt = preference.UpdatedAt
+			if preference.ID == uuid.Nil {
+				preference.ID = uuid.New()
+			}
+			if createErr := s.repoManager.Preference().Create(ctx, preference); createErr != nil {
+				return fmt.Errorf("failed to update preference: %w", createErr)
+			}
+		}
+	}
+	return nil
+}
+
+// CreateDefaultPreferences creates default preferences for a new user
+func (s *RepositoryPreferenceService) CreateDefaultPreferences(ctx context.Context, userID uuid.UUID) error {
+	return s.repoManager.Preference().CreateDefaultPreferences(ctx, userID)
+}
+
+// CanSendNotification checks if a notification can be sent based on user
+// preferences. Users without an explicit preference accept all types.
+func (s *RepositoryPreferenceService) CanSendNotification(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType) (bool, error) {
+	preference, err := s.repoManager.Preference().GetByUserIDAndType(ctx, userID, notificationType)
+	if err != nil {
+		return true, nil
+	}
+	return preference.Enabled, nil
+}
+
+// GetChannelOrder retrieves the user's ordered channel preference list used
+// for delivery fallback: enabled channels in the default fallback order
+func (s *RepositoryPreferenceService) GetChannelOrder(ctx context.Context, userID uuid.UUID) ([]domain.NotificationType, error) {
+	preferences, err := s.repoManager.Preference().GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[domain.NotificationType]bool, len(preferences))
+	for _, preference := range preferences {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	var order []domain.NotificationType
+	for _, channel := range usecases.DefaultChannelFallbackOrder {
+		if isEnabled, configured := enabled[channel]; !configured || isEnabled {
+			order = append(order, channel)
+		}
+	}
+	return order, nil
+}
+
+// LogEmailService implements usecases.EmailService by logging sends, the
+// stand-in for a real provider integration
+type LogEmailService struct{}
+
+// NewLogEmailService creates a new logging email service
+func NewLogEmailService() usecases.EmailService {
+	return &LogEmailService{}
+}
+
+// SendEmail sends an email
+func (s *LogEmailService) SendEmail(ctx context.Context, to, subject, body string) error {
+	fmt.Printf("[EMAIL] to=%s subject=%s\n", to, subject)
+	return nil
+}
+
+// SendBulkEmail sends emails to multiple recipients
+func (s *LogEmailService) SendBulkEmail(ctx context.Context, recipients []string, subject, body string) error {
+	for _, recipient := range recipients {
+		if err := s.SendEmail(ctx, recipient, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogSMSService implements usecases.SMSService by logging sends
+type LogSMSService struct{}
+
+// NewLogSMSService creates a new logging SMS service
+func NewLogSMSService() usecases.SMSService {
+	return &LogSMSService{}
+}
+
+// SendSMS sends an SMS
+func (s *LogSMSService) SendSMS(ctx context.Context, to, message string) error {
+	fmt.Printf("[SMS] to=%s\n", to)
+	return nil
+}
+
+// SendBulkSMS sends SMS to multiple recipients
+func (s *LogSMSService) SendBulkSMS(ctx context.Context, recipients []string, message string) error {
+	for _, recipient := range recipients {
+		if err := s.SendSMS(ctx, recipient, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogPushService implements usecases.PushService by logging sends
+type LogPushService struct{}
+
+// NewLogPushService creates a new logging push service
+func NewLogPushService() usecases.PushService {
+	return &LogPushService{}
+}
+
+// SendPushNotification sends a push notification
+func (s *LogPushService) SendPushNotification(ctx context.Context, deviceToken, title, body string, data map[string]interface{}) error {
+	fmt.Printf("[PUSH] device=%s title=%s\n", deviceToken, title)
+	return nil
+}
+
+// SendBulkPushNotification sends push notifications to multiple devices
+func (s *LogPushService) SendBulkPushNotification(ctx context.Context, deviceTokens []string, title, body string, data map[string]interface{}) error {
+	for _, deviceToken := range deviceTokens {
+		if err := s.SendPushNotification(ctx, deviceToken, title, body, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogActivityService implements usecases.ActivityService by logging activity
+type LogActivityService struct{}
+
+// NewLogActivityService creates a new logging activity service
+func NewLogActivityService() usecases.ActivityService {
+	return &LogActivityService{}
+}
+
+// LogActivity logs a notification activity
+func (s *LogActivityService) LogActivity(ctx context.Context, userID uuid.UUID, action, details string) error {
+	fmt.Printf("[ACTIVITY] user=%s action=%s details=%s\n", userID, action, details)
+	return nil
+}
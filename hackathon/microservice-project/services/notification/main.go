@@ -5,18 +5,30 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	"github.com/elotusteam/microservice-project/services/notification/domain"
+	"github.com/elotusteam/microservice-project/services/notification/infrastructure"
 	"github.com/elotusteam/microservice-project/services/notification/usecases"
 	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// notificationService and its supporting services back the HTTP handlers;
+// set in main before routes are registered
+var (
+	notificationService usecases.NotificationService
+	templateService     usecases.NotificationTemplateService
+	preferenceService   usecases.NotificationPreferenceService
+)
+
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -24,6 +36,22 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Wire the notification service: in-memory repositories plus the
+	// logging delivery channels
+	repoManager := infrastructure.NewMockRepositoryManager()
+	templateService = infrastructure.NewRepositoryTemplateService(repoManager)
+	preferenceService = infrastructure.NewRepositoryPreferenceService(repoManager)
+	notificationService = usecases.NewNotificationService(
+		repoManager,
+		templateService,
+		preferenceService,
+		infrastructure.NewLogEmailService(),
+		infrastructure.NewLogSMSService(),
+		infrastructure.NewLogPushService(),
+		infrastructure.NewLogActivityService(),
+		cfg,
+	)
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -36,6 +64,24 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// Return 405 with an Allow header for wrong-method requests
+	middleware.ConfigureMethodNotAllowed(router)
+
+	// Per-user WebSocket connection registry; exposes the active count
+	wsRegistry := usecases.NewWSConnectionRegistry()
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "notification_ws_connections_active",
+			Help: "Active WebSocket connections across all users",
+		},
+		func() float64 { return float64(wsRegistry.TotalCount()) },
+	))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Live notification stream; connections are tracked (and capped per
+	// user) by the registry
+	router.GET("/ws", notificationsWS(wsRegistry))
+
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -90,32 +136,14 @@ func main() {
 		IdleTimeout:       120 * time.Second,
 	}
 
-	// Start server in a goroutine
-	go func() {
-		log.Printf("Notification service starting on port %d", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down notification service...")
-
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	// Run with graceful shutdown handled by the shared helper
+	log.Printf("Notification service starting on port %d", cfg.Server.Port)
+	if err := sharedServer.RunServer(context.Background(), server, 30*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
-
-	log.Println("Notification service stopped")
 }
 
-// Handler functions (simplified implementations)
+// Handler functions
 func sendNotification(c *gin.Context) {
 	var req usecases.SendNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -123,11 +151,10 @@ func sendNotification(c *gin.Context) {
 		return
 	}
 
-	// Mock response for now
-	response := &usecases.SendNotificationResponse{
-		NotificationID: uuid.New(),
-		Status:         "sent",
-		Message:        "Notification sent successfully",
+	response, err := notificationService.SendNotification(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -142,44 +169,68 @@ func sendBulkNotifications(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if len(req.UserIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids is required"})
+		return
+	}
+
+	summary, err := notificationService.SendBulkNotifications(c.Request.Context(), req.UserIDs, &req.Notification)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Bulk notifications sent successfully"})
+	c.JSON(http.StatusOK, summary)
 }
 
 func getNotifications(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
-	// Mock response
-	response := &usecases.GetNotificationsResponse{
-		Notifications: []*domain.Notification{},
-		Total:         0,
-		UnreadCount:   0,
+	// Keyset paging params; the service clamps limit to the configured max
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+
+	response, err := notificationService.GetNotifications(c.Request.Context(), &usecases.GetNotificationsRequest{
+		UserID: userID,
+		Unread: c.Query("unread") == "true",
+		Limit:  limit,
+		Cursor: c.Query("cursor"),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func getNotificationByID(c *gin.Context) {
-	notificationID := c.Param("id")
-	userID := c.Query("user_id")
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
 
-	if userID == "" {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
-	// Mock response
-	c.JSON(http.StatusOK, gin.H{
-		"id":      notificationID,
-		"user_id": userID,
-		"title":   "Sample Notification",
-		"message": "This is a sample notification",
-		"status":  "sent",
-	})
+	notification, err := notificationService.GetNotificationByID(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
 }
 
 func markAsRead(c *gin.Context) {
@@ -189,39 +240,68 @@ func markAsRead(c *gin.Context) {
 		return
 	}
 
+	if err := notificationService.MarkAsRead(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read"})
 }
 
 func deleteNotification(c *gin.Context) {
-	notificationID := c.Param("id")
-	userID := c.Query("user_id")
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
 
-	if userID == "" {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
+	if err := notificationService.DeleteNotification(c.Request.Context(), userID, notificationID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Notification %s deleted", notificationID)})
 }
 
 func getUnreadCount(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"unread_count": 0})
+	count, err := notificationService.GetUnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
 }
 
 func getPreferences(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"preferences": []*domain.NotificationPreference{}})
+	preferences, err := preferenceService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if preferences == nil {
+		preferences = []*domain.NotificationPreference{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
 }
 
 func updatePreferences(c *gin.Context) {
@@ -231,6 +311,11 @@ func updatePreferences(c *gin.Context) {
 		return
 	}
 
+	if err := preferenceService.UpdatePreferences(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Preferences updated successfully"})
 }
 
@@ -241,26 +326,49 @@ func createTemplate(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":      uuid.New(),
-		"name":    req.Name,
-		"type":    req.Type,
-		"message": "Template created successfully",
-	})
+	template, err := templateService.CreateTemplate(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
 }
 
 func getTemplate(c *gin.Context) {
-	templateID := c.Param("id")
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":   templateID,
-		"name": "Sample Template",
-		"type": "email",
-	})
+	template, err := templateService.GetTemplate(c.Request.Context(), templateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
 }
 
 func updateTemplate(c *gin.Context) {
-	templateID := c.Param("id")
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var template domain.NotificationTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template.ID = templateID
+
+	if err := templateService.UpdateTemplate(c.Request.Context(), &template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":      templateID,
@@ -269,7 +377,16 @@ func updateTemplate(c *gin.Context) {
 }
 
 func deleteTemplate(c *gin.Context) {
-	templateID := c.Param("id")
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	if err := templateService.DeleteTemplate(c.Request.Context(), templateID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": fmt.Sprintf("Template %s deleted", templateID),
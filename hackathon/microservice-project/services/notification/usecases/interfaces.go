@@ -26,12 +26,14 @@ type SendNotificationResponse struct {
 	Message        string    `json:"message"`
 }
 
-// GetNotificationsRequest represents a request to get notifications
+// GetNotificationsRequest represents a request to get notifications.
+// Cursor is an opaque keyset position from a previous page's NextCursor.
 type GetNotificationsRequest struct {
 	UserID uuid.UUID `json:"user_id" validate:"required"`
 	Unread bool      `json:"unread,omitempty"`
 	Limit  int       `json:"limit,omitempty" validate:"min=1,max=100"`
 	Offset int       `json:"offset,omitempty" validate:"min=0"`
+	Cursor string    `json:"cursor,omitempty"`
 }
 
 // GetNotificationsResponse represents the response with notifications
@@ -39,6 +41,7 @@ type GetNotificationsResponse struct {
 	Notifications []*domain.Notification `json:"notifications"`
 	Total         int64                  `json:"total"`
 	UnreadCount   int64                  `json:"unread_count"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
 }
 
 // MarkAsReadRequest represents a request to mark notifications as read
@@ -63,13 +66,22 @@ type CreateTemplateRequest struct {
 	Variables []string                `json:"variables,omitempty"`
 }
 
+// BulkNotificationSummary reports per-recipient outcomes of a bulk send
+type BulkNotificationSummary struct {
+	Total      int `json:"total"`
+	Sent       int `json:"sent"`
+	Suppressed int `json:"suppressed"` // skipped by user preferences
+	Failed     int `json:"failed"`
+}
+
 // NotificationService defines the interface for notification business logic
 type NotificationService interface {
 	// SendNotification sends a notification to a user
 	SendNotification(ctx context.Context, req *SendNotificationRequest) (*SendNotificationResponse, error)
 
-	// SendBulkNotifications sends notifications to multiple users
-	SendBulkNotifications(ctx context.Context, userIDs []uuid.UUID, req *SendNotificationRequest) error
+	// SendBulkNotifications sends notifications to multiple users and
+	// returns a per-outcome summary
+	SendBulkNotifications(ctx context.Context, userIDs []uuid.UUID, req *SendNotificationRequest) (*BulkNotificationSummary, error)
 
 	// GetNotifications retrieves notifications for a user
 	GetNotifications(ctx context.Context, req *GetNotificationsRequest) (*GetNotificationsResponse, error)
@@ -124,6 +136,10 @@ type NotificationPreferenceService interface {
 
 	// CanSendNotification checks if a notification can be sent based on user preferences
 	CanSendNotification(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType) (bool, error)
+
+	// GetChannelOrder retrieves the user's ordered channel preference list
+	// used for delivery fallback
+	GetChannelOrder(ctx context.Context, userID uuid.UUID) ([]domain.NotificationType, error)
 }
 
 // EmailService defines the interface for email operations
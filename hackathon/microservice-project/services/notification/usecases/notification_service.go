@@ -3,6 +3,8 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/elotusteam/microservice-project/services/notification/domain"
@@ -80,6 +82,10 @@ func (s *notificationService) SendNotification(ctx context.Context, req *SendNot
 
 	if req.ScheduledAt != nil {
 		notification.ScheduledAt = req.ScheduledAt
+	} else if deferUntil := s.quietHoursEnd(ctx, notification); deferUntil != nil {
+		// Defer non-critical notifications until the user's quiet hours end;
+		// the pending-notification scheduler delivers them at window end
+		notification.ScheduledAt = deferUntil
 	}
 
 	// Save notification to database
@@ -88,6 +94,14 @@ func (s *notificationService) SendNotification(ctx context.Context, req *SendNot
 		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
 
+	if req.ScheduledAt == nil && notification.ScheduledAt != nil {
+		return &SendNotificationResponse{
+			NotificationID: notification.ID,
+			Status:         "deferred",
+			Message:        fmt.Sprintf("Notification deferred until quiet hours end at %s", notification.ScheduledAt.Format(time.RFC3339)),
+		}, nil
+	}
+
 	// If not scheduled, send immediately
 	if req.ScheduledAt == nil {
 		err = s.sendNotificationNow(ctx, notification)
@@ -112,34 +126,119 @@ func (s *notificationService) SendNotification(ctx context.Context, req *SendNot
 	}, nil
 }
 
-// SendBulkNotifications sends notifications to multiple users
-func (s *notificationService) SendBulkNotifications(ctx context.Context, userIDs []uuid.UUID, req *SendNotificationRequest) error {
+// SendBulkNotifications sends notifications to multiple users through a
+// bounded worker pool, tracking the outcome per recipient. Batches larger
+// than the configured maximum are rejected outright.
+func (s *notificationService) SendBulkNotifications(ctx context.Context, userIDs []uuid.UUID, req *SendNotificationRequest) (*BulkNotificationSummary, error) {
+	maxBatch := s.config.Notification.MaxBulkBatchSize
+	if maxBatch <= 0 {
+		maxBatch = 1000
+	}
+	if len(userIDs) > maxBatch {
+		return nil, fmt.Errorf("bulk batch of %d exceeds maximum of %d recipients", len(userIDs), maxBatch)
+	}
+
+	workers := s.config.Notification.BulkWorkers
+	if workers <= 0 {
+		workers = 8
+	}
+	if workers > len(userIDs) {
+		workers = len(userIDs)
+	}
+
+	summary := &BulkNotificationSummary{Total: len(userIDs)}
+	if summary.Total == 0 {
+		return summary, nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan uuid.UUID)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for userID := range jobs {
+				bulkReq := *req
+				bulkReq.UserID = userID
+
+				response, err := s.SendNotification(ctx, &bulkReq)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					summary.Failed++
+					fmt.Printf("Failed to send notification to user %s: %v\n", userID, err)
+				case response.Status == "skipped":
+					summary.Suppressed++
+				default:
+					summary.Sent++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
 	for _, userID := range userIDs {
-		bulkReq := *req
-		bulkReq.UserID = userID
+		jobs <- userID
+	}
+	close(jobs)
+	wg.Wait()
 
-		_, err := s.SendNotification(ctx, &bulkReq)
-		if err != nil {
-			// Log error but continue with other users
-			fmt.Printf("Failed to send notification to user %s: %v\n", userID, err)
-		}
+	return summary, nil
+}
+
+// encodeNotificationCursor builds the opaque keyset cursor for a page
+func encodeNotificationCursor(notification *domain.Notification) string {
+	return fmt.Sprintf("%s|%s", notification.CreatedAt.UTC().Format(time.RFC3339Nano), notification.ID)
+}
+
+// decodeNotificationCursor parses a cursor back into its keyset position
+func decodeNotificationCursor(cursor string) (time.Time, uuid.UUID, error) {
+	parts := strings.SplitN(cursor, "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
 	}
-	return nil
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor")
+	}
+	return createdAt, id, nil
 }
 
-// GetNotifications retrieves notifications for a user
+// GetNotifications retrieves notifications for a user, paged by a stable
+// (created_at, id) keyset so large histories page efficiently. Page size is
+// clamped to the configured maximum.
 func (s *notificationService) GetNotifications(ctx context.Context, req *GetNotificationsRequest) (*GetNotificationsResponse, error) {
 	var notifications []*domain.Notification
 	var err error
 
-	if req.Limit == 0 {
-		req.Limit = 20
+	if req.Limit <= 0 {
+		req.Limit = s.config.Notification.DefaultPageSize
+		if req.Limit <= 0 {
+			req.Limit = 20
+		}
+	}
+	if maxPage := s.config.Notification.MaxPageSize; maxPage > 0 && req.Limit > maxPage {
+		req.Limit = maxPage
 	}
 
-	if req.Unread {
+	switch {
+	case req.Unread:
 		notifications, err = s.repoManager.Notification().GetUnreadByUserID(ctx, req.UserID, req.Limit, req.Offset)
-	} else {
-		notifications, err = s.repoManager.Notification().GetByUserID(ctx, req.UserID, req.Limit, req.Offset)
+	case req.Cursor != "":
+		beforeCreatedAt, beforeID, cursorErr := decodeNotificationCursor(req.Cursor)
+		if cursorErr != nil {
+			return nil, cursorErr
+		}
+		notifications, err = s.repoManager.Notification().GetByUserIDKeyset(ctx, req.UserID, beforeCreatedAt, beforeID, req.Limit)
+	default:
+		notifications, err = s.repoManager.Notification().GetByUserIDKeyset(ctx, req.UserID, time.Time{}, uuid.Nil, req.Limit)
 	}
 
 	if err != nil {
@@ -158,11 +257,16 @@ func (s *notificationService) GetNotifications(ctx context.Context, req *GetNoti
 		return nil, fmt.Errorf("failed to get unread count: %w", err)
 	}
 
-	return &GetNotificationsResponse{
+	response := &GetNotificationsResponse{
 		Notifications: notifications,
 		Total:         total,
 		UnreadCount:   unreadCount,
-	}, nil
+	}
+	if !req.Unread && len(notifications) == req.Limit {
+		response.NextCursor = encodeNotificationCursor(notifications[len(notifications)-1])
+	}
+
+	return response, nil
 }
 
 // GetNotificationByID retrieves a specific notification
@@ -270,9 +374,148 @@ func (s *notificationService) ProcessPendingNotifications(ctx context.Context) e
 	return nil
 }
 
-// sendNotificationNow sends a notification immediately based on its type
+// DefaultChannelFallbackOrder is the channel order used when a user has no
+// configured channel preference list.
+var DefaultChannelFallbackOrder = []domain.NotificationType{
+	domain.NotificationTypePush,
+	domain.NotificationTypeEmail,
+	domain.NotificationTypeSMS,
+	domain.NotificationTypeInApp,
+}
+
+// sendNotificationNow sends a notification immediately. The preferred channel
+// (the notification's type) is attempted first, then the remaining channels in
+// the user's preference order until one succeeds. Security alerts are fanned
+// out to every channel regardless of which ones succeed.
 func (s *notificationService) sendNotificationNow(ctx context.Context, notification *domain.Notification) error {
-	switch notification.Type {
+	channels := s.channelFallbackOrder(ctx, notification)
+
+	var delivered []domain.NotificationType
+	var lastErr error
+
+	for _, channel := range channels {
+		if err := s.deliverViaChannel(ctx, channel, notification); err != nil {
+			lastErr = err
+			fmt.Printf("Failed to deliver notification %s via %s: %v\n", notification.ID, channel, err)
+			continue
+		}
+
+		delivered = append(delivered, channel)
+		if !s.isSecurityAlert(notification) {
+			break
+		}
+	}
+
+	if len(delivered) == 0 {
+		if lastErr != nil {
+			return fmt.Errorf("all delivery channels failed: %w", lastErr)
+		}
+		return fmt.Errorf("no delivery channels available")
+	}
+
+	// Record which channel(s) ultimately delivered the notification
+	if notification.Data == nil {
+		notification.Data = make(map[string]interface{})
+	}
+	notification.Data["delivered_channel"] = string(delivered[0])
+	if len(delivered) > 1 {
+		channelNames := make([]string, len(delivered))
+		for i, channel := range delivered {
+			channelNames[i] = string(channel)
+		}
+		notification.Data["delivered_channels"] = channelNames
+	}
+
+	now := time.Now()
+	notification.Status = domain.NotificationStatusSent
+	notification.SentAt = &now
+	return s.repoManager.Notification().UpdateStatus(ctx, notification.ID, domain.NotificationStatusSent)
+}
+
+// channelFallbackOrder returns the channels to attempt, starting with the
+// notification's own type followed by the user's configured preference order
+// (or the default order when none is configured).
+func (s *notificationService) channelFallbackOrder(ctx context.Context, notification *domain.Notification) []domain.NotificationType {
+	preferred, err := s.preferenceService.GetChannelOrder(ctx, notification.UserID)
+	if err != nil || len(preferred) == 0 {
+		preferred = DefaultChannelFallbackOrder
+	}
+
+	channels := make([]domain.NotificationType, 0, len(preferred)+1)
+	seen := make(map[domain.NotificationType]bool)
+
+	for _, channel := range append([]domain.NotificationType{notification.Type}, preferred...) {
+		if seen[channel] {
+			continue
+		}
+		seen[channel] = true
+		channels = append(channels, channel)
+	}
+
+	return channels
+}
+
+// quietHoursEnd returns when the user's quiet hours end if the notification
+// should be deferred, or nil when it can be delivered now. Security alerts
+// and urgent notifications ignore quiet hours.
+func (s *notificationService) quietHoursEnd(ctx context.Context, notification *domain.Notification) *time.Time {
+	if s.isSecurityAlert(notification) || notification.Priority == domain.NotificationPriorityUrgent {
+		return nil
+	}
+
+	preferences, err := s.preferenceService.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		return nil
+	}
+
+	for _, pref := range preferences {
+		if pref.Type != notification.Type || pref.QuietHoursStart == nil || pref.QuietHoursEnd == nil {
+			continue
+		}
+
+		location := time.UTC
+		if pref.Timezone != "" {
+			if loc, err := time.LoadLocation(pref.Timezone); err == nil {
+				location = loc
+			}
+		}
+
+		now := time.Now().In(location)
+		start := timeOfDayOn(now, pref.QuietHoursStart.In(location))
+		end := timeOfDayOn(now, pref.QuietHoursEnd.In(location))
+
+		if end.Before(start) {
+			// Window spans midnight (e.g. 22:00-07:00)
+			if now.After(start) {
+				end = end.AddDate(0, 0, 1)
+			} else {
+				start = start.AddDate(0, 0, -1)
+			}
+		}
+
+		if !now.Before(start) && now.Before(end) {
+			return &end
+		}
+	}
+
+	return nil
+}
+
+// timeOfDayOn projects the clock time of t onto the date of day
+func timeOfDayOn(day, t time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location())
+}
+
+// isSecurityAlert reports whether the notification is a security alert, which
+// must be fanned out to every channel.
+func (s *notificationService) isSecurityAlert(notification *domain.Notification) bool {
+	category, _ := notification.Data["category"].(string)
+	return category == "security_alert"
+}
+
+// deliverViaChannel delivers a notification through a single channel
+func (s *notificationService) deliverViaChannel(ctx context.Context, channel domain.NotificationType, notification *domain.Notification) error {
+	switch channel {
 	case domain.NotificationTypeEmail:
 		return s.sendEmailNotification(ctx, notification)
 	case domain.NotificationTypeSMS:
@@ -282,7 +525,7 @@ func (s *notificationService) sendNotificationNow(ctx context.Context, notificat
 	case domain.NotificationTypeInApp:
 		return s.sendInAppNotification(ctx, notification)
 	default:
-		return fmt.Errorf("unsupported notification type: %s", notification.Type)
+		return fmt.Errorf("unsupported notification type: %s", channel)
 	}
 }
 
@@ -292,16 +535,7 @@ func (s *notificationService) sendEmailNotification(ctx context.Context, notific
 	// In a real implementation, you'd get the user's email from the user service
 	email := "user@example.com"
 
-	err := s.emailService.SendEmail(ctx, email, notification.Title, notification.Message)
-	if err != nil {
-		return err
-	}
-
-	// Update notification status
-	now := time.Now()
-	notification.Status = domain.NotificationStatusSent
-	notification.SentAt = &now
-	return s.repoManager.Notification().UpdateStatus(ctx, notification.ID, domain.NotificationStatusSent)
+	return s.emailService.SendEmail(ctx, email, notification.Title, notification.Message)
 }
 
 // sendSMSNotification sends an SMS notification
@@ -309,16 +543,7 @@ func (s *notificationService) sendSMSNotification(ctx context.Context, notificat
 	// For now, we'll use a placeholder phone number
 	phone := "+1234567890"
 
-	err := s.smsService.SendSMS(ctx, phone, notification.Message)
-	if err != nil {
-		return err
-	}
-
-	// Update notification status
-	now := time.Now()
-	notification.Status = domain.NotificationStatusSent
-	notification.SentAt = &now
-	return s.repoManager.Notification().UpdateStatus(ctx, notification.ID, domain.NotificationStatusSent)
+	return s.smsService.SendSMS(ctx, phone, notification.Message)
 }
 
 // sendPushNotification sends a push notification
@@ -326,23 +551,12 @@ func (s *notificationService) sendPushNotification(ctx context.Context, notifica
 	// For now, we'll use a placeholder device token
 	deviceToken := "device_token_placeholder"
 
-	err := s.pushService.SendPushNotification(ctx, deviceToken, notification.Title, notification.Message, notification.Data)
-	if err != nil {
-		return err
-	}
-
-	// Update notification status
-	now := time.Now()
-	notification.Status = domain.NotificationStatusSent
-	notification.SentAt = &now
-	return s.repoManager.Notification().UpdateStatus(ctx, notification.ID, domain.NotificationStatusSent)
+	return s.pushService.SendPushNotification(ctx, deviceToken, notification.Title, notification.Message, notification.Data)
 }
 
 // sendInAppNotification sends an in-app notification
 func (s *notificationService) sendInAppNotification(ctx context.Context, notification *domain.Notification) error {
-	// In-app notifications are just stored in the database and marked as sent
-	now := time.Now()
-	notification.Status = domain.NotificationStatusSent
-	notification.SentAt = &now
-	return s.repoManager.Notification().UpdateStatus(ctx, notification.ID, domain.NotificationStatusSent)
+	// In-app notifications are already stored in the database; nothing to
+	// deliver externally
+	return nil
 }
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxWSConnectionsPerUser caps how many WebSocket connections one
+// user (tabs/devices) may hold at once. Override with
+// NOTIFICATION_WS_MAX_CONNECTIONS_PER_USER.
+const DefaultMaxWSConnectionsPerUser = 5
+
+func maxWSConnectionsPerUser() int {
+	if value := os.Getenv("NOTIFICATION_WS_MAX_CONNECTIONS_PER_USER"); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxWSConnectionsPerUser
+}
+
+// wsConnection is one registered WebSocket connection
+type wsConnection struct {
+	id       string
+	userID   uuid.UUID
+	openedAt time.Time
+	closeFn  func()
+}
+
+// WSConnectionRegistry enforces the per-user WebSocket connection cap. When
+// a user exceeds it, the oldest connection is closed so the newest device
+// wins, keeping resource usage bounded and fair.
+type WSConnectionRegistry struct {
+	mu       sync.Mutex
+	maxPer   int
+	byUser   map[uuid.UUID][]*wsConnection
+	totalNow int
+}
+
+// NewWSConnectionRegistry creates a registry with the configured cap
+func NewWSConnectionRegistry() *WSConnectionRegistry {
+	return &WSConnectionRegistry{
+		maxPer: maxWSConnectionsPerUser(),
+		byUser: make(map[uuid.UUID][]*wsConnection),
+	}
+}
+
+// Register adds a connection for the user; closeFn is invoked if the
+// connection is later evicted. Returns the connection ID.
+func (r *WSConnectionRegistry) Register(userID uuid.UUID, closeFn func()) string {
+	connection := &wsConnection{
+		id:       uuid.New().String(),
+		userID:   userID,
+		openedAt: time.Now(),
+		closeFn:  closeFn,
+	}
+
+	var evicted *wsConnection
+
+	r.mu.Lock()
+	connections := append(r.byUser[userID], connection)
+	if len(connections) > r.maxPer {
+		// Evict the oldest connection (it is first in registration order)
+		evicted = connections[0]
+		connections = connections[1:]
+	} else {
+		r.totalNow++
+	}
+	r.byUser[userID] = connections
+	r.mu.Unlock()
+
+	if evicted != nil && evicted.closeFn != nil {
+		evicted.closeFn()
+	}
+
+	return connection.id
+}
+
+// Unregister removes a closed connection
+func (r *WSConnectionRegistry) Unregister(userID uuid.UUID, connectionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	connections := r.byUser[userID]
+	for i, connection := range connections {
+		if connection.id == connectionID {
+			r.byUser[userID] = append(connections[:i], connections[i+1:]...)
+			r.totalNow--
+			break
+		}
+	}
+	if len(r.byUser[userID]) == 0 {
+		delete(r.byUser, userID)
+	}
+}
+
+// UserCount returns the user's active connection count
+func (r *WSConnectionRegistry) UserCount(userID uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.byUser[userID])
+}
+
+// TotalCount returns the total active connection count (for metrics)
+func (r *WSConnectionRegistry) TotalCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.totalNow
+}
+
+// CheckLimit reports whether the user may open another connection without an
+// eviction, for reject-instead-of-evict policies
+func (r *WSConnectionRegistry) CheckLimit(userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.byUser[userID]) >= r.maxPer {
+		return fmt.Errorf("connection limit of %d reached for user %s", r.maxPer, userID)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/notification/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// wsAcceptGUID is the fixed GUID from RFC 6455 used to derive the
+// Sec-WebSocket-Accept handshake response
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes (RFC 6455 §5.2)
+const (
+	wsOpcodeText  = 0x1
+	wsOpcodeClose = 0x8
+	wsOpcodePing  = 0x9
+	wsOpcodePong  = 0xA
+)
+
+// wsUnreadPushInterval is how often the unread count is pushed to connected
+// clients
+const wsUnreadPushInterval = 15 * time.Second
+
+// notificationsWS upgrades the connection to a WebSocket, registers it in
+// the per-user registry (evicting the user's oldest connection beyond the
+// cap) and streams unread-count updates until the client disconnects
+func notificationsWS(registry *usecases.WSConnectionRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Query("user_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+			return
+		}
+
+		if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "WebSocket upgrade required"})
+			return
+		}
+		key := c.GetHeader("Sec-WebSocket-Key")
+		if key == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Sec-WebSocket-Key required"})
+			return
+		}
+
+		hijacker, ok := c.Writer.(http.Hijacker)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Connection cannot be upgraded"})
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Upgrade failed"})
+			return
+		}
+
+		sum := sha1.Sum([]byte(key + wsAcceptGUID))
+		response := "HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+		if _, err := rw.WriteString(response); err != nil {
+			conn.Close()
+			return
+		}
+		if err := rw.Flush(); err != nil {
+			conn.Close()
+			return
+		}
+
+		connectionID := registry.Register(userID, func() { conn.Close() })
+		go serveWSConnection(conn, rw, registry, userID, connectionID)
+	}
+}
+
+// serveWSConnection pushes unread-count updates and answers control frames
+// until the connection drops or the registry evicts it
+func serveWSConnection(conn net.Conn, rw *bufio.ReadWriter, registry *usecases.WSConnectionRegistry, userID uuid.UUID, connectionID string) {
+	defer func() {
+		registry.Unregister(userID, connectionID)
+		conn.Close()
+	}()
+
+	// Reader: respond to pings, exit on close frames or connection errors
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			opcode, payload, err := readWSFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+			switch opcode {
+			case wsOpcodeClose:
+				writeWSFrame(conn, wsOpcodeClose, payload)
+				return
+			case wsOpcodePing:
+				if err := writeWSFrame(conn, wsOpcodePong, payload); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(wsUnreadPushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			count, err := notificationService.GetUnreadCount(context.Background(), userID)
+			if err != nil {
+				continue
+			}
+			message := fmt.Sprintf(`{"type":"unread_count","unread_count":%d}`, count)
+			if err := writeWSFrame(conn, wsOpcodeText, []byte(message)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readWSFrame reads one client frame, unmasking the payload (client frames
+// are always masked per RFC 6455 §5.1)
+func readWSFrame(r *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(r, extended); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(r, extended); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+	if length > 1<<20 {
+		return 0, nil, fmt.Errorf("frame too large: %d bytes", length)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+// writeWSFrame writes one unmasked server frame
+func writeWSFrame(conn net.Conn, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, byte(length>>8), byte(length))
+	default:
+		header = append(header, 127)
+		extended := make([]byte, 8)
+		binary.BigEndian.PutUint64(extended, uint64(length))
+		header = append(header, extended...)
+	}
+
+	if _, err := conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
@@ -37,6 +37,7 @@ const (
 	UserStatusSuspended UserStatus = "suspended"
 	UserStatusBanned    UserStatus = "banned"
 	UserStatusPending   UserStatus = "pending"
+	UserStatusDeleted   UserStatus = "deleted"
 )
 
 // UserRole represents the role of a user
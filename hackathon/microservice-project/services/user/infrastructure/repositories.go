@@ -0,0 +1,732 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	userDomain "github.com/elotusteam/microservice-project/services/user/domain"
+	"github.com/google/uuid"
+)
+
+// MockRepositoryManager implements userDomain.RepositoryManager with
+// in-memory storage for development and testing, mirroring the auth
+// service's mock repositories.
+type MockRepositoryManager struct {
+	mu          sync.RWMutex
+	users       map[uuid.UUID]*userDomain.User
+	profiles    map[uuid.UUID]*userDomain.UserProfile
+	groups      map[uuid.UUID]*userDomain.UserGroup
+	memberships map[uuid.UUID]*userDomain.UserGroupMembership
+	sessions    map[uuid.UUID]*userDomain.UserSession
+	connections map[uuid.UUID]*userDomain.UserConnection
+}
+
+// NewMockRepositoryManager creates a new in-memory repository manager
+func NewMockRepositoryManager() userDomain.RepositoryManager {
+	return &MockRepositoryManager{
+		users:       make(map[uuid.UUID]*userDomain.User),
+		profiles:    make(map[uuid.UUID]*userDomain.UserProfile),
+		groups:      make(map[uuid.UUID]*userDomain.UserGroup),
+		memberships: make(map[uuid.UUID]*userDomain.UserGroupMembership),
+		sessions:    make(map[uuid.UUID]*userDomain.UserSession),
+		connections: make(map[uuid.UUID]*userDomain.UserConnection),
+	}
+}
+
+// User returns the user repository
+func (m *MockRepositoryManager) User() userDomain.UserRepository {
+	return &mockUserRepository{manager: m}
+}
+
+// UserProfile returns the user profile repository
+func (m *MockRepositoryManager) UserProfile() userDomain.UserProfileRepository {
+	return &mockUserProfileRepository{manager: m}
+}
+
+// UserGroup returns the user group repository
+func (m *MockRepositoryManager) UserGroup() userDomain.UserGroupRepository {
+	return &mockUserGroupRepository{manager: m}
+}
+
+// UserGroupMembership returns the group membership repository
+func (m *MockRepositoryManager) UserGroupMembership() userDomain.UserGroupMembershipRepository {
+	return &mockUserGroupMembershipRepository{manager: m}
+}
+
+// UserSession returns the user session repository
+func (m *MockRepositoryManager) UserSession() userDomain.UserSessionRepository {
+	return &mockUserSessionRepository{manager: m}
+}
+
+// UserConnection returns the user connection repository
+func (m *MockRepositoryManager) UserConnection() userDomain.UserConnectionRepository {
+	return &mockUserConnectionRepository{manager: m}
+}
+
+// BeginTx returns the manager itself: the in-memory store applies writes
+// directly, so Commit and Rollback are no-ops
+func (m *MockRepositoryManager) BeginTx(ctx context.Context) (userDomain.RepositoryManager, error) {
+	return m, nil
+}
+
+// Commit commits the transaction (no-op for the in-memory store)
+func (m *MockRepositoryManager) Commit() error { return nil }
+
+// Rollback rolls back the transaction (no-op for the in-memory store)
+func (m *MockRepositoryManager) Rollback() error { return nil }
+
+// mockUserRepository implements userDomain.UserRepository
+type mockUserRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserRepository) Create(ctx context.Context, user *userDomain.User) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.users[user.ID] = user
+	return nil
+}
+
+func (r *mockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	user, exists := r.manager.users[id]
+	if !exists {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+func (r *mockUserRepository) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, user := range r.manager.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *mockUserRepository) GetByUsername(ctx context.Context, username string) (*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, user := range r.manager.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *mockUserRepository) Update(ctx context.Context, user *userDomain.User) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if _, exists := r.manager.users[user.ID]; !exists {
+		return fmt.Errorf("user not found")
+	}
+	r.manager.users[user.ID] = user
+	return nil
+}
+
+func (r *mockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.users, id)
+	return nil
+}
+
+func (r *mockUserRepository) List(ctx context.Context, limit, offset int) ([]*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var users []*userDomain.User
+	for _, user := range r.manager.users {
+		users = append(users, user)
+	}
+	return sliceWindow(users, limit, offset), nil
+}
+
+func (r *mockUserRepository) Search(ctx context.Context, query string, limit, offset int) ([]*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var users []*userDomain.User
+	for _, user := range r.manager.users {
+		if strings.Contains(user.Username, query) || strings.Contains(user.Email, query) {
+			users = append(users, user)
+		}
+	}
+	return sliceWindow(users, limit, offset), nil
+}
+
+func (r *mockUserRepository) GetByRole(ctx context.Context, role userDomain.UserRole, limit, offset int) ([]*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var users []*userDomain.User
+	for _, user := range r.manager.users {
+		if user.Role == role {
+			users = append(users, user)
+		}
+	}
+	return sliceWindow(users, limit, offset), nil
+}
+
+func (r *mockUserRepository) GetByStatus(ctx context.Context, status userDomain.UserStatus, limit, offset int) ([]*userDomain.User, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var users []*userDomain.User
+	for _, user := range r.manager.users {
+		if user.Status == status {
+			users = append(users, user)
+		}
+	}
+	return sliceWindow(users, limit, offset), nil
+}
+
+func (r *mockUserRepository) Count(ctx context.Context) (int64, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	return int64(len(r.manager.users)), nil
+}
+
+func (r *mockUserRepository) CountByStatus(ctx context.Context, status userDomain.UserStatus) (int64, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var count int64
+	for _, user := range r.manager.users {
+		if user.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *mockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func (r *mockUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	_, err := r.GetByUsername(ctx, username)
+	return err == nil, nil
+}
+
+func (r *mockUserRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if user, exists := r.manager.users[userID]; exists {
+		now := time.Now()
+		user.LastLoginAt = &now
+	}
+	return nil
+}
+
+func (r *mockUserRepository) UpdateEmailVerification(ctx context.Context, userID uuid.UUID, verified bool) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if user, exists := r.manager.users[userID]; exists {
+		user.EmailVerified = verified
+	}
+	return nil
+}
+
+// mockUserProfileRepository implements userDomain.UserProfileRepository
+type mockUserProfileRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserProfileRepository) Create(ctx context.Context, profile *userDomain.UserProfile) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.profiles[profile.UserID] = profile
+	return nil
+}
+
+func (r *mockUserProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	profile, exists := r.manager.profiles[userID]
+	if !exists {
+		return nil, fmt.Errorf("profile not found")
+	}
+	return profile, nil
+}
+
+func (r *mockUserProfileRepository) Update(ctx context.Context, profile *userDomain.UserProfile) error {
+	return r.Create(ctx, profile)
+}
+
+func (r *mockUserProfileRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.profiles, userID)
+	return nil
+}
+
+func (r *mockUserProfileRepository) UpdatePreferences(ctx context.Context, userID uuid.UUID, preferences userDomain.UserPreferences) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if profile, exists := r.manager.profiles[userID]; exists {
+		profile.Preferences = preferences
+	}
+	return nil
+}
+
+// mockUserGroupRepository implements userDomain.UserGroupRepository
+type mockUserGroupRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserGroupRepository) Create(ctx context.Context, group *userDomain.UserGroup) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.groups[group.ID] = group
+	return nil
+}
+
+func (r *mockUserGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	group, exists := r.manager.groups[id]
+	if !exists {
+		return nil, fmt.Errorf("group not found")
+	}
+	return group, nil
+}
+
+func (r *mockUserGroupRepository) GetByName(ctx context.Context, name string) (*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, group := range r.manager.groups {
+		if group.Name == name {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("group not found")
+}
+
+func (r *mockUserGroupRepository) Update(ctx context.Context, group *userDomain.UserGroup) error {
+	return r.Create(ctx, group)
+}
+
+func (r *mockUserGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.groups, id)
+	return nil
+}
+
+func (r *mockUserGroupRepository) List(ctx context.Context, limit, offset int) ([]*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var groups []*userDomain.UserGroup
+	for _, group := range r.manager.groups {
+		groups = append(groups, group)
+	}
+	return sliceWindow(groups, limit, offset), nil
+}
+
+func (r *mockUserGroupRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID) ([]*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var groups []*userDomain.UserGroup
+	for _, group := range r.manager.groups {
+		if group.OwnerID == ownerID {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *mockUserGroupRepository) GetPublicGroups(ctx context.Context, limit, offset int) ([]*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var groups []*userDomain.UserGroup
+	for _, group := range r.manager.groups {
+		if group.IsPublic {
+			groups = append(groups, group)
+		}
+	}
+	return sliceWindow(groups, limit, offset), nil
+}
+
+func (r *mockUserGroupRepository) Search(ctx context.Context, query string, limit, offset int) ([]*userDomain.UserGroup, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var groups []*userDomain.UserGroup
+	for _, group := range r.manager.groups {
+		if strings.Contains(group.Name, query) {
+			groups = append(groups, group)
+		}
+	}
+	return sliceWindow(groups, limit, offset), nil
+}
+
+func (r *mockUserGroupRepository) UpdateMemberCount(ctx context.Context, groupID uuid.UUID, count int) error {
+	return nil
+}
+
+// mockUserGroupMembershipRepository implements userDomain.UserGroupMembershipRepository
+type mockUserGroupMembershipRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserGroupMembershipRepository) Create(ctx context.Context, membership *userDomain.UserGroupMembership) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.memberships[membership.ID] = membership
+	return nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	membership, exists := r.manager.memberships[id]
+	if !exists {
+		return nil, fmt.Errorf("membership not found")
+	}
+	return membership, nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByUserAndGroup(ctx context.Context, userID, groupID uuid.UUID) (*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, membership := range r.manager.memberships {
+		if membership.UserID == userID && membership.GroupID == groupID {
+			return membership, nil
+		}
+	}
+	return nil, fmt.Errorf("membership not found")
+}
+
+func (r *mockUserGroupMembershipRepository) Update(ctx context.Context, membership *userDomain.UserGroupMembership) error {
+	return r.Create(ctx, membership)
+}
+
+func (r *mockUserGroupMembershipRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.memberships, id)
+	return nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var memberships []*userDomain.UserGroupMembership
+	for _, membership := range r.manager.memberships {
+		if membership.UserID == userID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByGroupID(ctx context.Context, groupID uuid.UUID, limit, offset int) ([]*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var memberships []*userDomain.UserGroupMembership
+	for _, membership := range r.manager.memberships {
+		if membership.GroupID == groupID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return sliceWindow(memberships, limit, offset), nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByGroupIDAndRole(ctx context.Context, groupID uuid.UUID, role userDomain.GroupMemberRole) ([]*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var memberships []*userDomain.UserGroupMembership
+	for _, membership := range r.manager.memberships {
+		if membership.GroupID == groupID && membership.Role == role {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *mockUserGroupMembershipRepository) GetByGroupIDAndStatus(ctx context.Context, groupID uuid.UUID, status userDomain.MembershipStatus) ([]*userDomain.UserGroupMembership, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var memberships []*userDomain.UserGroupMembership
+	for _, membership := range r.manager.memberships {
+		if membership.GroupID == groupID && membership.Status == status {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *mockUserGroupMembershipRepository) CountByGroupID(ctx context.Context, groupID uuid.UUID) (int64, error) {
+	memberships, _ := r.GetByGroupID(ctx, groupID, 0, 0)
+	return int64(len(memberships)), nil
+}
+
+func (r *mockUserGroupMembershipRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	memberships, _ := r.GetByUserID(ctx, userID)
+	return int64(len(memberships)), nil
+}
+
+func (r *mockUserGroupMembershipRepository) ExistsByUserAndGroup(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
+	_, err := r.GetByUserAndGroup(ctx, userID, groupID)
+	return err == nil, nil
+}
+
+// mockUserSessionRepository implements userDomain.UserSessionRepository
+type mockUserSessionRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserSessionRepository) Create(ctx context.Context, session *userDomain.UserSession) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.sessions[session.ID] = session
+	return nil
+}
+
+func (r *mockUserSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.UserSession, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	session, exists := r.manager.sessions[id]
+	if !exists {
+		return nil, fmt.Errorf("session not found")
+	}
+	return session, nil
+}
+
+func (r *mockUserSessionRepository) GetByToken(ctx context.Context, token string) (*userDomain.UserSession, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, session := range r.manager.sessions {
+		if session.Token == token {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("session not found")
+}
+
+func (r *mockUserSessionRepository) Update(ctx context.Context, session *userDomain.UserSession) error {
+	return r.Create(ctx, session)
+}
+
+func (r *mockUserSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.sessions, id)
+	return nil
+}
+
+func (r *mockUserSessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*userDomain.UserSession, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var sessions []*userDomain.UserSession
+	for _, session := range r.manager.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *mockUserSessionRepository) GetActiveSessions(ctx context.Context, userID uuid.UUID) ([]*userDomain.UserSession, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	now := time.Now()
+	var sessions []*userDomain.UserSession
+	for _, session := range r.manager.sessions {
+		if session.UserID == userID && session.IsActive && session.ExpiresAt.After(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *mockUserSessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	for id, session := range r.manager.sessions {
+		if session.UserID == userID {
+			delete(r.manager.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (r *mockUserSessionRepository) DeleteExpiredSessions(ctx context.Context) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	now := time.Now()
+	for id, session := range r.manager.sessions {
+		if session.ExpiresAt.Before(now) {
+			delete(r.manager.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (r *mockUserSessionRepository) UpdateLastUsed(ctx context.Context, sessionID uuid.UUID) error {
+	return nil
+}
+
+func (r *mockUserSessionRepository) DeactivateSession(ctx context.Context, sessionID uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	if session, exists := r.manager.sessions[sessionID]; exists {
+		session.IsActive = false
+	}
+	return nil
+}
+
+// mockUserConnectionRepository implements userDomain.UserConnectionRepository
+type mockUserConnectionRepository struct {
+	manager *MockRepositoryManager
+}
+
+func (r *mockUserConnectionRepository) Create(ctx context.Context, connection *userDomain.UserConnection) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	r.manager.connections[connection.ID] = connection
+	return nil
+}
+
+func (r *mockUserConnectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	connection, exists := r.manager.connections[id]
+	if !exists {
+		return nil, fmt.Errorf("connection not found")
+	}
+	return connection, nil
+}
+
+func (r *mockUserConnectionRepository) GetByUsers(ctx context.Context, requesterID, addresseeID uuid.UUID) (*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, connection := range r.manager.connections {
+		if connection.RequesterID == requesterID && connection.AddresseeID == addresseeID {
+			return connection, nil
+		}
+	}
+	return nil, fmt.Errorf("connection not found")
+}
+
+func (r *mockUserConnectionRepository) Update(ctx context.Context, connection *userDomain.UserConnection) error {
+	return r.Create(ctx, connection)
+}
+
+func (r *mockUserConnectionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	delete(r.manager.connections, id)
+	return nil
+}
+
+func (r *mockUserConnectionRepository) GetByRequesterID(ctx context.Context, requesterID uuid.UUID, connectionType userDomain.ConnectionType) ([]*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var connections []*userDomain.UserConnection
+	for _, connection := range r.manager.connections {
+		if connection.RequesterID == requesterID && connection.Type == connectionType {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (r *mockUserConnectionRepository) GetByAddresseeID(ctx context.Context, addresseeID uuid.UUID, connectionType userDomain.ConnectionType) ([]*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var connections []*userDomain.UserConnection
+	for _, connection := range r.manager.connections {
+		if connection.AddresseeID == addresseeID && connection.Type == connectionType {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (r *mockUserConnectionRepository) GetFriends(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var connections []*userDomain.UserConnection
+	for _, connection := range r.manager.connections {
+		if connection.Type == userDomain.ConnectionTypeFriend && connection.Status == userDomain.ConnectionStatusAccepted &&
+			(connection.RequesterID == userID || connection.AddresseeID == userID) {
+			connections = append(connections, connection)
+		}
+	}
+	return sliceWindow(connections, limit, offset), nil
+}
+
+func (r *mockUserConnectionRepository) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*userDomain.UserConnection, error) {
+	connections, err := r.GetByAddresseeID(ctx, userID, userDomain.ConnectionTypeFollower)
+	if err != nil {
+		return nil, err
+	}
+	return sliceWindow(connections, limit, offset), nil
+}
+
+func (r *mockUserConnectionRepository) GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*userDomain.UserConnection, error) {
+	connections, err := r.GetByRequesterID(ctx, userID, userDomain.ConnectionTypeFollower)
+	if err != nil {
+		return nil, err
+	}
+	return sliceWindow(connections, limit, offset), nil
+}
+
+func (r *mockUserConnectionRepository) GetPendingRequests(ctx context.Context, userID uuid.UUID, connectionType userDomain.ConnectionType) ([]*userDomain.UserConnection, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	var connections []*userDomain.UserConnection
+	for _, connection := range r.manager.connections {
+		if connection.AddresseeID == userID && connection.Type == connectionType && connection.Status == userDomain.ConnectionStatusPending {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (r *mockUserConnectionRepository) CountFriends(ctx context.Context, userID uuid.UUID) (int64, error) {
+	friends, _ := r.GetFriends(ctx, userID, 0, 0)
+	return int64(len(friends)), nil
+}
+
+func (r *mockUserConnectionRepository) CountFollowers(ctx context.Context, userID uuid.UUID) (int64, error) {
+	followers, _ := r.GetFollowers(ctx, userID, 0, 0)
+	return int64(len(followers)), nil
+}
+
+func (r *mockUserConnectionRepository) CountFollowing(ctx context.Context, userID uuid.UUID) (int64, error) {
+	following, _ := r.GetFollowing(ctx, userID, 0, 0)
+	return int64(len(following)), nil
+}
+
+func (r *mockUserConnectionRepository) ExistsByUsers(ctx context.Context, requesterID, addresseeID uuid.UUID, connectionType userDomain.ConnectionType) (bool, error) {
+	r.manager.mu.RLock()
+	defer r.manager.mu.RUnlock()
+	for _, connection := range r.manager.connections {
+		if connection.RequesterID == requesterID && connection.AddresseeID == addresseeID && connection.Type == connectionType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sliceWindow applies limit/offset to an in-memory result set; limit <= 0
+// returns everything from offset onward
+func sliceWindow[T any](items []T, limit, offset int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,87 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/user/usecases"
+	"github.com/elotusteam/microservice-project/shared/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptPasswordService implements usecases.PasswordService with bcrypt
+// hashing and in-memory reset token storage
+type BcryptPasswordService struct {
+	mu          sync.Mutex
+	resetTokens map[string]resetToken
+}
+
+type resetToken struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+// NewBcryptPasswordService creates a new bcrypt-backed password service
+func NewBcryptPasswordService() usecases.PasswordService {
+	return &BcryptPasswordService{
+		resetTokens: make(map[string]resetToken),
+	}
+}
+
+// HashPassword hashes a password with bcrypt
+func (s *BcryptPasswordService) HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("password hashing failed: %w", err)
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword compares a password against its hash
+func (s *BcryptPasswordService) VerifyPassword(hashedPassword, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+}
+
+// ValidatePasswordStrength applies the baseline strength rules
+func (s *BcryptPasswordService) ValidatePasswordStrength(password string) []string {
+	var violations []string
+	if len(password) < 8 {
+		violations = append(violations, "password must be at least 8 characters")
+	}
+	if score, guidance := utils.PasswordStrengthScore(password); score < 2 {
+		violations = append(violations, guidance...)
+	}
+	return violations
+}
+
+// GenerateResetToken issues a reset token for the user
+func (s *BcryptPasswordService) GenerateResetToken(userID uuid.UUID) (string, error) {
+	token := utils.GenerateID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetTokens[token] = resetToken{userID: userID, expiresAt: time.Now().Add(time.Hour)}
+	return token, nil
+}
+
+// ValidateResetToken resolves a reset token to its user
+func (s *BcryptPasswordService) ValidateResetToken(token string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.resetTokens[token]
+	if !exists || time.Now().After(entry.expiresAt) {
+		return uuid.Nil, fmt.Errorf("invalid or expired reset token")
+	}
+	return entry.userID, nil
+}
+
+// InvalidateResetToken burns a reset token
+func (s *BcryptPasswordService) InvalidateResetToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.resetTokens, token)
+	return nil
+}
@@ -6,11 +6,18 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
+	"sync"
 	"time"
 
+	"github.com/elotusteam/microservice-project/services/user/infrastructure"
+	"github.com/elotusteam/microservice-project/services/user/usecases"
+	"github.com/elotusteam/microservice-project/shared/authrpc"
+	"github.com/elotusteam/microservice-project/shared/config"
+	"github.com/google/uuid"
+
+	"github.com/elotusteam/microservice-project/shared/middleware"
+	sharedServer "github.com/elotusteam/microservice-project/shared/server"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -61,11 +68,57 @@ func prometheusMiddleware() gin.HandlerFunc {
 	}
 }
 
+// authTokenValidator validates bearer tokens against the auth service over
+// gRPC (AUTH_GRPC_ADDR), failing closed when the auth service is unreachable
+func authTokenValidator() middleware.TokenValidator {
+	target := getEnv("AUTH_GRPC_ADDR", "localhost:9081")
+	var (
+		clientOnce sync.Once
+		client     *authrpc.Client
+		dialErr    error
+	)
+
+	return func(ctx context.Context, token string) (string, string, error) {
+		clientOnce.Do(func() {
+			client, dialErr = authrpc.Dial(target)
+		})
+		if dialErr != nil {
+			return "", "", fmt.Errorf("auth service unavailable: %w", dialErr)
+		}
+
+		resp, err := client.ValidateToken(ctx, token)
+		if err != nil {
+			return "", "", err
+		}
+		if !resp.Valid {
+			return "", "", fmt.Errorf("invalid token: %s", resp.Error)
+		}
+		return resp.UserID, resp.Role, nil
+	}
+}
+
 func main() {
 	// Load configuration from environment
 	host := getEnv("SERVER_HOST", "localhost")
 	port := getEnvAsInt("SERVER_PORT", 8083)
 
+	// Load shared configuration and wire the user service
+	sharedCfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load shared config: %v", err)
+	}
+	userService := usecases.NewUserService(
+		infrastructure.NewMockRepositoryManager(),
+		infrastructure.NewBcryptPasswordService(),
+		nil, // notification service pending a real provider
+		nil, // activity service pending wiring to analytics
+		sharedCfg,
+	)
+
+	// Caller identity comes from validated tokens, never from headers
+	requireAuth := middleware.RequireAuth(authTokenValidator())
+	requireAdmin := middleware.RequireRole(authTokenValidator(), "admin")
+
 	// Set Gin mode to debug for development
 	gin.SetMode(gin.DebugMode)
 
@@ -75,6 +128,9 @@ func main() {
 	// Add Prometheus middleware
 	router.Use(prometheusMiddleware())
 
+	// Return 405 with an Allow header for wrong-method requests
+	middleware.ConfigureMethodNotAllowed(router)
+
 	// Metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -111,6 +167,63 @@ func main() {
 			users.GET("/", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "List users endpoint - implementation pending"})
 			})
+			users.DELETE("/me", requireAuth, func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user identity"})
+					return
+				}
+
+				response, err := userService.DeleteOwnAccount(c.Request.Context(), userID)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				userActionsTotal.WithLabelValues("self_delete").Inc()
+				c.JSON(http.StatusOK, response)
+			})
+			users.POST("/merge", requireAdmin, func(c *gin.Context) {
+				var req struct {
+					SourceUserID uuid.UUID `json:"source_user_id" binding:"required"`
+					TargetUserID uuid.UUID `json:"target_user_id" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				if err := userService.MergeUsers(c.Request.Context(), req.SourceUserID, req.TargetUserID); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				userActionsTotal.WithLabelValues("merge").Inc()
+				c.JSON(http.StatusOK, gin.H{"message": "Accounts merged"})
+			})
+			users.POST("/purge-deleted", requireAdmin, func(c *gin.Context) {
+				purged, err := userService.PurgeExpiredDeletedAccounts(c.Request.Context())
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"purged": purged})
+			})
+			users.POST("/me/reactivate", requireAuth, func(c *gin.Context) {
+				userID, err := uuid.Parse(middleware.AuthenticatedUserID(c))
+				if err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user identity"})
+					return
+				}
+
+				if err := userService.ReactivateAccount(c.Request.Context(), userID); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				userActionsTotal.WithLabelValues("reactivate").Inc()
+				c.JSON(http.StatusOK, gin.H{"message": "Account reactivated"})
+			})
 		}
 
 		// Profile routes
@@ -135,28 +248,11 @@ func main() {
 		IdleTimeout:       120 * time.Second,
 	}
 
-	// Graceful shutdown
-	go func() {
-		log.Printf("User service starting on %s:%d", host, port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	// The context is used to inform the server it has 5 seconds to finish
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	// Run with graceful shutdown handled by the shared helper
+	log.Printf("User service starting on %s:%d", host, port)
+	if err := sharedServer.RunServer(context.Background(), server, 5*time.Second); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
-
-	log.Println("Server exiting")
 }
 
 // Helper functions
@@ -22,6 +22,10 @@ type UserService interface {
 	UpdateUserStatus(ctx context.Context, userID uuid.UUID, status userDomain.UserStatus) error
 	VerifyEmail(ctx context.Context, userID uuid.UUID) error
 	GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error)
+	DeleteOwnAccount(ctx context.Context, userID uuid.UUID) (*DeleteAccountResponse, error)
+	MergeUsers(ctx context.Context, sourceID, targetID uuid.UUID) error
+	ReactivateAccount(ctx context.Context, userID uuid.UUID) error
+	PurgeExpiredDeletedAccounts(ctx context.Context) (int, error)
 }
 
 // UserProfileService defines the interface for user profile operations
@@ -169,6 +173,13 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=8"`
 }
 
+type DeleteAccountResponse struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+	GracePeriod string    `json:"grace_period"`
+}
+
 type UserStats struct {
 	TotalFiles     int64 `json:"total_files"`
 	TotalStorage   int64 `json:"total_storage"`
@@ -431,6 +431,216 @@ func (s *userService) GetUserStats(ctx context.Context, userID uuid.UUID) (*User
 	return stats, nil
 }
 
+// MergeUsers reassigns everything owned by the source user to the target
+// (sessions, group memberships), deactivates the source account and records
+// an audit entry. The whole merge runs inside one transaction and is rolled
+// back if any step fails, so a half-merged account can never persist.
+func (s *userService) MergeUsers(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	if sourceID == targetID {
+		return fmt.Errorf("source and target users must differ")
+	}
+
+	source, err := s.repos.User().GetByID(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get source user: %w", err)
+	}
+	if _, err := s.repos.User().GetByID(ctx, targetID); err != nil {
+		return fmt.Errorf("failed to get target user: %w", err)
+	}
+
+	tx, err := s.repos.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin merge transaction: %w", err)
+	}
+
+	merge := func() error {
+		// Reassign sessions
+		sessions, err := tx.UserSession().GetByUserID(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to list source sessions: %w", err)
+		}
+		for _, session := range sessions {
+			session.UserID = targetID
+			if err := tx.UserSession().Update(ctx, session); err != nil {
+				return fmt.Errorf("failed to reassign session %s: %w", session.ID, err)
+			}
+		}
+
+		// Reassign group memberships, skipping groups the target already joined
+		memberships, err := tx.UserGroupMembership().GetByUserID(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to list source memberships: %w", err)
+		}
+		for _, membership := range memberships {
+			exists, err := tx.UserGroupMembership().ExistsByUserAndGroup(ctx, targetID, membership.GroupID)
+			if err != nil {
+				return fmt.Errorf("failed to check target membership: %w", err)
+			}
+			if exists {
+				if err := tx.UserGroupMembership().Delete(ctx, membership.ID); err != nil {
+					return fmt.Errorf("failed to drop duplicate membership: %w", err)
+				}
+				continue
+			}
+			membership.UserID = targetID
+			if err := tx.UserGroupMembership().Update(ctx, membership); err != nil {
+				return fmt.Errorf("failed to reassign membership %s: %w", membership.ID, err)
+			}
+		}
+
+		// Deactivate the source account
+		now := time.Now()
+		source.Status = userDomain.UserStatusInactive
+		source.DeletedAt = &now
+		source.UpdatedAt = now
+		if err := tx.User().Update(ctx, source); err != nil {
+			return fmt.Errorf("failed to deactivate source user: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := merge(); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			fmt.Printf("Failed to roll back merge: %v\n", rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	// Audit entry
+	if s.activitySvc != nil {
+		_ = s.activitySvc.LogActivity(ctx, targetID, "users_merged", "user", &targetID, map[string]interface{}{
+			"source_user_id": sourceID,
+			"target_user_id": targetID,
+		}, "", "")
+	}
+
+	return nil
+}
+
+// DeleteOwnAccount marks the user's account as deleted, revokes all sessions
+// and schedules permanent erasure after the configured grace period. The
+// account can be reactivated during the grace window via ReactivateAccount.
+func (s *userService) DeleteOwnAccount(ctx context.Context, userID uuid.UUID) (*DeleteAccountResponse, error) {
+	if !s.config.AccountDeletion.Enabled {
+		return nil, fmt.Errorf("account deletion is disabled")
+	}
+
+	user, err := s.repos.User().GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.Status == userDomain.UserStatusDeleted {
+		return nil, fmt.Errorf("account is already deleted")
+	}
+
+	// Mark the account as deleted
+	now := time.Now()
+	user.Status = userDomain.UserStatusDeleted
+	user.DeletedAt = &now
+	user.UpdatedAt = now
+
+	err = s.repos.User().Update(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	// Revoke all sessions
+	if err := s.repos.UserSession().DeleteByUserID(ctx, userID); err != nil {
+		fmt.Printf("Failed to revoke sessions for user %s: %v\n", userID, err)
+	}
+
+	gracePeriod := s.config.AccountDeletion.GracePeriod
+
+	// Log activity
+	if s.activitySvc != nil {
+		_ = s.activitySvc.LogActivity(ctx, userID, "account_deleted", "user", &userID, map[string]interface{}{
+			"purge_at": now.Add(gracePeriod),
+		}, "", "")
+	}
+
+	return &DeleteAccountResponse{
+		UserID:      userID,
+		DeletedAt:   now,
+		PurgeAt:     now.Add(gracePeriod),
+		GracePeriod: gracePeriod.String(),
+	}, nil
+}
+
+// ReactivateAccount restores an account deleted within the grace period.
+func (s *userService) ReactivateAccount(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.repos.User().GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.Status != userDomain.UserStatusDeleted || user.DeletedAt == nil {
+		return fmt.Errorf("account is not pending deletion")
+	}
+
+	if time.Since(*user.DeletedAt) > s.config.AccountDeletion.GracePeriod {
+		return fmt.Errorf("grace period has expired")
+	}
+
+	user.Status = userDomain.UserStatusActive
+	user.DeletedAt = nil
+	user.UpdatedAt = time.Now()
+
+	err = s.repos.User().Update(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to reactivate account: %w", err)
+	}
+
+	// Log activity
+	if s.activitySvc != nil {
+		_ = s.activitySvc.LogActivity(ctx, userID, "account_reactivated", "user", &userID, map[string]interface{}{}, "", "")
+	}
+
+	return nil
+}
+
+// PurgeExpiredDeletedAccounts permanently erases accounts whose grace period
+// has elapsed and returns the number of accounts purged. It is intended to be
+// run periodically by a background job.
+func (s *userService) PurgeExpiredDeletedAccounts(ctx context.Context) (int, error) {
+	purged := 0
+	offset := 0
+	const batchSize = 100
+
+	for {
+		users, err := s.repos.User().GetByStatus(ctx, userDomain.UserStatusDeleted, batchSize, offset)
+		if err != nil {
+			return purged, fmt.Errorf("failed to list deleted users: %w", err)
+		}
+		if len(users) == 0 {
+			return purged, nil
+		}
+
+		for _, user := range users {
+			if user.DeletedAt == nil || time.Since(*user.DeletedAt) < s.config.AccountDeletion.GracePeriod {
+				offset++
+				continue
+			}
+
+			if err := s.repos.User().Delete(ctx, user.ID); err != nil {
+				fmt.Printf("Failed to purge user %s: %v\n", user.ID, err)
+				offset++
+				continue
+			}
+			purged++
+		}
+
+		if len(users) < batchSize {
+			return purged, nil
+		}
+	}
+}
+
 // Helper functions
 
 func (s *userService) validateCreateUserRequest(req *CreateUserRequest) error {
@@ -47,6 +47,9 @@ type Config struct {
 	// Image processing configuration
 	ImageProcessing ImageProcessingConfig `json:"image_processing"`
 
+	// Account deletion configuration
+	AccountDeletion AccountDeletionConfig `json:"account_deletion"`
+
 	// Notification configuration
 	Notification NotificationConfig `json:"notification"`
 
@@ -118,10 +121,30 @@ type CacheConfig struct {
 	IdleTimeout  time.Duration `json:"idle_timeout"`
 	DefaultTTL   time.Duration `json:"default_ttl"`
 
+	// FailurePolicy controls behavior when the cache backend errors:
+	// "fail-open" serves the request with a warning, "fail-closed" denies.
+	// FailurePolicies overrides the default per operation (e.g. "rate_limit",
+	// "lockout", "revocation").
+	FailurePolicy   string            `json:"failure_policy"`
+	FailurePolicies map[string]string `json:"failure_policies"`
+
 	// Cluster configuration for Redis Cluster
 	Cluster CacheClusterConfig `json:"cluster"`
 }
 
+// FailurePolicyFor returns the effective cache failure policy for an
+// operation, defaulting to fail-open so a cache outage degrades gracefully
+// unless an operator deliberately chose otherwise.
+func (c *CacheConfig) FailurePolicyFor(operation string) string {
+	if policy, ok := c.FailurePolicies[operation]; ok && policy != "" {
+		return policy
+	}
+	if c.FailurePolicy != "" {
+		return c.FailurePolicy
+	}
+	return "fail-open"
+}
+
 // CacheClusterConfig holds cache cluster configuration
 type CacheClusterConfig struct {
 	Enabled   bool     `json:"enabled"`
@@ -141,10 +164,25 @@ type StorageConfig struct {
 	// Local storage configuration
 	LocalPath string `json:"local_path"`
 
+	// QuarantinePath is the storage prefix infected files are moved to
+	QuarantinePath string `json:"quarantine_path"`
+
+	// Path sharding configuration
+	Sharding PathShardingConfig `json:"sharding"`
+
 	// CDN configuration
 	CDN CDNConfig `json:"cdn"`
 }
 
+// PathShardingConfig holds storage path sharding configuration.
+// When enabled, files are spread across shard subdirectories derived from
+// their checksum to avoid directory/prefix hot-spotting.
+type PathShardingConfig struct {
+	Enabled       bool `json:"enabled"`
+	Levels        int  `json:"levels"`          // number of shard subdirectories
+	CharsPerLevel int  `json:"chars_per_level"` // hex chars consumed per level
+}
+
 // CDNConfig holds CDN configuration
 type CDNConfig struct {
 	Enabled    bool          `json:"enabled"`
@@ -298,6 +336,9 @@ type SecurityConfig struct {
 	// JWT configuration
 	JWT JWTConfig `json:"jwt"`
 
+	// Session configuration
+	Session SessionConfig `json:"session"`
+
 	// Password configuration
 	Password PasswordConfig `json:"password"`
 
@@ -306,6 +347,53 @@ type SecurityConfig struct {
 
 	// API key configuration
 	APIKey APIKeyConfig `json:"api_key"`
+
+	// OAuth social login configuration
+	OAuth OAuthConfig `json:"oauth"`
+
+	// Account lockout configuration
+	Lockout LockoutConfig `json:"lockout"`
+
+	// CAPTCHA configuration
+	Captcha CaptchaConfig `json:"captcha"`
+}
+
+// CaptchaConfig holds CAPTCHA challenge configuration. After the failed
+// attempt threshold, login requires a CAPTCHA token; registration requires
+// one whenever CAPTCHA is enabled.
+type CaptchaConfig struct {
+	Enabled                 bool   `json:"enabled"`
+	Provider                string `json:"provider"` // recaptcha, hcaptcha, turnstile
+	Secret                  string `json:"-"`        // Hidden from JSON
+	FailedAttemptsThreshold int    `json:"failed_attempts_threshold"`
+}
+
+// LockoutConfig holds account lockout policy configuration. With exponential
+// growth enabled, each successive lockout doubles the duration up to the
+// maximum.
+type LockoutConfig struct {
+	MaxAttempts       int           `json:"max_attempts"`
+	AttemptWindow     time.Duration `json:"attempt_window"`
+	BaseLockDuration  time.Duration `json:"base_lock_duration"`
+	ExponentialGrowth bool          `json:"exponential_growth"`
+	MaxLockDuration   time.Duration `json:"max_lock_duration"`
+}
+
+// OAuthConfig holds OAuth2/OIDC social login configuration
+type OAuthConfig struct {
+	Enabled     bool                           `json:"enabled"`
+	RedirectURL string                         `json:"redirect_url"`
+	Providers   map[string]OAuthProviderConfig `json:"providers"`
+}
+
+// OAuthProviderConfig holds one OAuth2/OIDC provider's endpoints and client
+type OAuthProviderConfig struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"-"` // Hidden from JSON
+	AuthURL      string   `json:"auth_url"`
+	TokenURL     string   `json:"token_url"`
+	UserInfoURL  string   `json:"user_info_url"`
+	Scopes       []string `json:"scopes"`
 }
 
 // JWTConfig holds JWT configuration
@@ -318,6 +406,26 @@ type JWTConfig struct {
 	Algorithm       string        `json:"algorithm"`
 	PublicKeyPath   string        `json:"public_key_path"`
 	PrivateKeyPath  string        `json:"private_key_path"`
+
+	// RefreshTokenBinding binds refresh tokens to a client fingerprint.
+	// Optional because some clients cannot maintain a stable fingerprint.
+	RefreshTokenBinding bool `json:"refresh_token_binding"`
+
+	// RefreshTokenCookie delivers refresh tokens via a secure HTTP-only
+	// cookie instead of the JSON body, for SPA clients. Mobile clients keep
+	// using the body when disabled.
+	RefreshTokenCookie     bool   `json:"refresh_token_cookie"`
+	RefreshTokenCookieName string `json:"refresh_token_cookie_name"`
+}
+
+// SessionConfig holds session lifetime configuration. With sliding extension
+// enabled, each validated request pushes the session's expiry forward by
+// SlidingExtension, capped at SlidingMaxLifetime from session creation. This
+// is independent of refresh-token rotation, which still governs token renewal.
+type SessionConfig struct {
+	SlidingEnabled     bool          `json:"sliding_enabled"`
+	SlidingExtension   time.Duration `json:"sliding_extension"`
+	SlidingMaxLifetime time.Duration `json:"sliding_max_lifetime"`
 }
 
 // PasswordConfig holds password configuration
@@ -328,6 +436,9 @@ type PasswordConfig struct {
 	RequireNumbers      bool          `json:"require_numbers"`
 	RequireSymbols      bool          `json:"require_symbols"`
 	RequireSpecialChars bool          `json:"require_special_chars"`
+	MinStrengthScore    int           `json:"min_strength_score"` // 0-4 scale; 0 disables scoring
+	HistoryCount        int           `json:"history_count"`      // reject reuse of the last N passwords; 0 disables
+	MaxAge              time.Duration `json:"max_age"`            // force a reset after this age; 0 disables
 	BcryptCost          int           `json:"bcrypt_cost"`
 	ResetTokenTTL       time.Duration `json:"reset_token_ttl"`
 }
@@ -365,23 +476,38 @@ type RateLimitConfig struct {
 	API map[string]RateLimitRule `json:"api"`
 }
 
-// RateLimitRule holds rate limit rule configuration
+// RateLimitRule holds rate limit rule configuration. SoftRequests is an
+// optional lower warning threshold: exceeding it adds a warning header while
+// still serving the request, and only the hard Requests threshold blocks.
 type RateLimitRule struct {
-	Requests int           `json:"requests"`
-	Window   time.Duration `json:"window"`
-	Burst    int           `json:"burst"`
+	Requests     int           `json:"requests"`
+	SoftRequests int           `json:"soft_requests"`
+	Window       time.Duration `json:"window"`
+	Burst        int           `json:"burst"`
 }
 
 // FileUploadConfig holds file upload configuration
 type FileUploadConfig struct {
-	MaxFileSize       int64         `json:"max_file_size"`  // bytes
-	MaxTotalSize      int64         `json:"max_total_size"` // bytes per user
-	AllowedMimeTypes  []string      `json:"allowed_mime_types"`
-	AllowedExtensions []string      `json:"allowed_extensions"`
-	ChunkSize         int64         `json:"chunk_size"` // bytes
-	UploadTimeout     time.Duration `json:"upload_timeout"`
-	TempDir           string        `json:"temp_dir"`
-	VirusScanEnabled  bool          `json:"virus_scan_enabled"`
+	MaxFileSize         int64         `json:"max_file_size"`  // bytes
+	MaxTotalSize        int64         `json:"max_total_size"` // bytes per user
+	AllowedMimeTypes    []string      `json:"allowed_mime_types"`
+	AllowedExtensions   []string      `json:"allowed_extensions"`
+	ChunkSize           int64         `json:"chunk_size"` // bytes
+	UploadTimeout       time.Duration `json:"upload_timeout"`
+	TempDir             string        `json:"temp_dir"`
+	VirusScanEnabled    bool          `json:"virus_scan_enabled"`
+	MaxVersionsPerFile  int           `json:"max_versions_per_file"`  // 0 disables version pruning
+	MaxTagFacets        int           `json:"max_tag_facets"`         // cap on tags returned in search facets
+	MaxCollisionRetries int           `json:"max_collision_retries"`  // storage path regeneration attempts on collision
+	UploadSessionMaxAge time.Duration `json:"upload_session_max_age"` // resumable upload sessions older than this are reclaimed
+}
+
+// AccountDeletionConfig holds account self-deletion configuration.
+// Deleted accounts are kept for the grace period and can be reactivated
+// before permanent erasure runs.
+type AccountDeletionConfig struct {
+	Enabled     bool          `json:"enabled"`
+	GracePeriod time.Duration `json:"grace_period"`
 }
 
 // ImageProcessingConfig holds image processing configuration
@@ -395,6 +521,14 @@ type ImageProcessingConfig struct {
 	Watermark  WatermarkConfig   `json:"watermark"`
 	Workers    int               `json:"workers"`
 	QueueSize  int               `json:"queue_size"`
+
+	// SimilarityMaxDistance is the maximum pHash Hamming distance for two
+	// images to count as near-duplicates
+	SimilarityMaxDistance int `json:"similarity_max_distance"`
+
+	// OnDemandVariants lists variant types generated only when first
+	// requested instead of eagerly at upload time
+	OnDemandVariants []string `json:"on_demand_variants"`
 }
 
 // ThumbnailConfig holds thumbnail configuration
@@ -419,6 +553,16 @@ type WatermarkConfig struct {
 type NotificationConfig struct {
 	Enabled bool `json:"enabled"`
 
+	// MaxBulkBatchSize caps how many recipients one bulk send may target
+	MaxBulkBatchSize int `json:"max_bulk_batch_size"`
+
+	// BulkWorkers bounds concurrent deliveries within a bulk send
+	BulkWorkers int `json:"bulk_workers"`
+
+	// DefaultPageSize and MaxPageSize bound notification listing pages
+	DefaultPageSize int `json:"default_page_size"`
+	MaxPageSize     int `json:"max_page_size"`
+
 	// Email configuration
 	Email EmailConfig `json:"email"`
 
@@ -589,54 +733,173 @@ func LoadConfig() (*Config, error) {
 
 	// Load cache configuration
 	config.Cache = CacheConfig{
-		Driver:       getEnv("CACHE_DRIVER", "redis"),
-		Host:         getEnv("CACHE_HOST", "localhost"),
-		Port:         getEnvInt("CACHE_PORT", 6379),
-		Password:     getEnv("CACHE_PASSWORD", ""),
-		Database:     getEnvInt("CACHE_DATABASE", 0),
-		PoolSize:     getEnvInt("CACHE_POOL_SIZE", 10),
-		MinIdleConns: getEnvInt("CACHE_MIN_IDLE_CONNS", 2),
-		DialTimeout:  getEnvDuration("CACHE_DIAL_TIMEOUT", 5*time.Second),
-		ReadTimeout:  getEnvDuration("CACHE_READ_TIMEOUT", 3*time.Second),
-		WriteTimeout: getEnvDuration("CACHE_WRITE_TIMEOUT", 3*time.Second),
-		IdleTimeout:  getEnvDuration("CACHE_IDLE_TIMEOUT", 5*time.Minute),
-		DefaultTTL:   getEnvDuration("CACHE_DEFAULT_TTL", 1*time.Hour),
+		Driver:        getEnv("CACHE_DRIVER", "redis"),
+		Host:          getEnv("CACHE_HOST", "localhost"),
+		Port:          getEnvInt("CACHE_PORT", 6379),
+		Password:      getEnv("CACHE_PASSWORD", ""),
+		Database:      getEnvInt("CACHE_DATABASE", 0),
+		PoolSize:      getEnvInt("CACHE_POOL_SIZE", 10),
+		MinIdleConns:  getEnvInt("CACHE_MIN_IDLE_CONNS", 2),
+		DialTimeout:   getEnvDuration("CACHE_DIAL_TIMEOUT", 5*time.Second),
+		ReadTimeout:   getEnvDuration("CACHE_READ_TIMEOUT", 3*time.Second),
+		WriteTimeout:  getEnvDuration("CACHE_WRITE_TIMEOUT", 3*time.Second),
+		IdleTimeout:   getEnvDuration("CACHE_IDLE_TIMEOUT", 5*time.Minute),
+		DefaultTTL:    getEnvDuration("CACHE_DEFAULT_TTL", 1*time.Hour),
+		FailurePolicy: getEnv("CACHE_FAILURE_POLICY", "fail-open"),
+	}
+
+	// Per-operation overrides, e.g. "rate_limit:fail-closed,lockout:fail-open"
+	config.Cache.FailurePolicies = make(map[string]string)
+	for _, entry := range getEnvSlice("CACHE_FAILURE_POLICIES", nil) {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) == 2 && parts[0] != "" {
+			config.Cache.FailurePolicies[parts[0]] = parts[1]
+		}
 	}
 
 	// Load storage configuration
 	config.Storage = StorageConfig{
-		Driver:    getEnv("STORAGE_DRIVER", "local"),
-		Bucket:    getEnv("STORAGE_BUCKET", ""),
-		Region:    getEnv("STORAGE_REGION", ""),
-		Endpoint:  getEnv("STORAGE_ENDPOINT", ""),
-		AccessKey: getEnv("STORAGE_ACCESS_KEY", ""),
-		SecretKey: getEnv("STORAGE_SECRET_KEY", ""),
-		UseSSL:    getEnvBool("STORAGE_USE_SSL", true),
-		LocalPath: getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		Driver:         getEnv("STORAGE_DRIVER", "local"),
+		Bucket:         getEnv("STORAGE_BUCKET", ""),
+		Region:         getEnv("STORAGE_REGION", ""),
+		Endpoint:       getEnv("STORAGE_ENDPOINT", ""),
+		AccessKey:      getEnv("STORAGE_ACCESS_KEY", ""),
+		SecretKey:      getEnv("STORAGE_SECRET_KEY", ""),
+		UseSSL:         getEnvBool("STORAGE_USE_SSL", true),
+		LocalPath:      getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		QuarantinePath: getEnv("STORAGE_QUARANTINE_PATH", "quarantine"),
+		Sharding: PathShardingConfig{
+			Enabled:       getEnvBool("STORAGE_SHARDING_ENABLED", false),
+			Levels:        getEnvInt("STORAGE_SHARDING_LEVELS", 2),
+			CharsPerLevel: getEnvInt("STORAGE_SHARDING_CHARS_PER_LEVEL", 2),
+		},
 	}
 
 	// Load JWT configuration
 	config.Security.JWT = JWTConfig{
-		SecretKey:       getEnv("JWT_SECRET_KEY", "your-secret-key"),
-		Issuer:          getEnv("JWT_ISSUER", "fileserver"),
-		Audience:        getEnv("JWT_AUDIENCE", "fileserver-users"),
-		AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
-		RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
-		Algorithm:       getEnv("JWT_ALGORITHM", "HS256"),
-		PublicKeyPath:   getEnv("JWT_PUBLIC_KEY_PATH", ""),
-		PrivateKeyPath:  getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		SecretKey:              getEnv("JWT_SECRET_KEY", "your-secret-key"),
+		Issuer:                 getEnv("JWT_ISSUER", "fileserver"),
+		Audience:               getEnv("JWT_AUDIENCE", "fileserver-users"),
+		AccessTokenTTL:         getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:        getEnvDuration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
+		Algorithm:              getEnv("JWT_ALGORITHM", "HS256"),
+		PublicKeyPath:          getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		PrivateKeyPath:         getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		RefreshTokenBinding:    getEnvBool("JWT_REFRESH_TOKEN_BINDING", false),
+		RefreshTokenCookie:     getEnvBool("JWT_REFRESH_TOKEN_COOKIE", false),
+		RefreshTokenCookieName: getEnv("JWT_REFRESH_TOKEN_COOKIE_NAME", "refresh_token"),
+	}
+
+	// Load API key configuration
+	config.Security.APIKey = APIKeyConfig{
+		Enabled:    getEnvBool("API_KEY_ENABLED", false),
+		HeaderName: getEnv("API_KEY_HEADER_NAME", "X-API-Key"),
+		Prefix:     getEnv("API_KEY_PREFIX", "ak_"),
+		Length:     getEnvInt("API_KEY_LENGTH", 32),
+		TTL:        getEnvDuration("API_KEY_TTL", 0),
+	}
+
+	// Load OAuth configuration. Google and GitHub ship preconfigured
+	// endpoints; a generic OIDC provider can be added via its issuer URLs.
+	config.Security.OAuth = OAuthConfig{
+		Enabled:     getEnvBool("OAUTH_ENABLED", false),
+		RedirectURL: getEnv("OAUTH_REDIRECT_URL", ""),
+		Providers: map[string]OAuthProviderConfig{
+			"google": {
+				ClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+				AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+				TokenURL:     "https://oauth2.googleapis.com/token",
+				UserInfoURL:  "https://openidconnect.googleapis.com/v1/userinfo",
+				Scopes:       []string{"openid", "email", "profile"},
+			},
+			"github": {
+				ClientID:     getEnv("OAUTH_GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_GITHUB_CLIENT_SECRET", ""),
+				AuthURL:      "https://github.com/login/oauth/authorize",
+				TokenURL:     "https://github.com/login/oauth/access_token",
+				UserInfoURL:  "https://api.github.com/user",
+				Scopes:       []string{"user:email"},
+			},
+			"oidc": {
+				ClientID:     getEnv("OAUTH_OIDC_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_OIDC_CLIENT_SECRET", ""),
+				AuthURL:      getEnv("OAUTH_OIDC_AUTH_URL", ""),
+				TokenURL:     getEnv("OAUTH_OIDC_TOKEN_URL", ""),
+				UserInfoURL:  getEnv("OAUTH_OIDC_USER_INFO_URL", ""),
+				Scopes:       getEnvSlice("OAUTH_OIDC_SCOPES", []string{"openid", "email", "profile"}),
+			},
+		},
+	}
+
+	// Load CAPTCHA configuration
+	config.Security.Captcha = CaptchaConfig{
+		Enabled:                 getEnvBool("CAPTCHA_ENABLED", false),
+		Provider:                getEnv("CAPTCHA_PROVIDER", "recaptcha"),
+		Secret:                  getEnv("CAPTCHA_SECRET", ""),
+		FailedAttemptsThreshold: getEnvInt("CAPTCHA_FAILED_ATTEMPTS_THRESHOLD", 3),
+	}
+
+	// Load lockout configuration
+	config.Security.Lockout = LockoutConfig{
+		MaxAttempts:       getEnvInt("LOCKOUT_MAX_ATTEMPTS", 5),
+		AttemptWindow:     getEnvDuration("LOCKOUT_ATTEMPT_WINDOW", 15*time.Minute),
+		BaseLockDuration:  getEnvDuration("LOCKOUT_BASE_DURATION", 30*time.Minute),
+		ExponentialGrowth: getEnvBool("LOCKOUT_EXPONENTIAL_GROWTH", true),
+		MaxLockDuration:   getEnvDuration("LOCKOUT_MAX_DURATION", 24*time.Hour),
+	}
+
+	// Load session configuration
+	config.Security.Session = SessionConfig{
+		SlidingEnabled:     getEnvBool("SESSION_SLIDING_ENABLED", false),
+		SlidingExtension:   getEnvDuration("SESSION_SLIDING_EXTENSION", 30*time.Minute),
+		SlidingMaxLifetime: getEnvDuration("SESSION_SLIDING_MAX_LIFETIME", 24*time.Hour),
+	}
+
+	// Load password configuration
+	config.Security.Password = PasswordConfig{
+		MinLength:           getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		RequireUppercase:    getEnvBool("PASSWORD_REQUIRE_UPPERCASE", false),
+		RequireLowercase:    getEnvBool("PASSWORD_REQUIRE_LOWERCASE", false),
+		RequireNumbers:      getEnvBool("PASSWORD_REQUIRE_NUMBERS", false),
+		RequireSpecialChars: getEnvBool("PASSWORD_REQUIRE_SPECIAL_CHARS", false),
+		MinStrengthScore:    getEnvInt("PASSWORD_MIN_STRENGTH_SCORE", 2),
+		HistoryCount:        getEnvInt("PASSWORD_HISTORY_COUNT", 5),
+		MaxAge:              getEnvDuration("PASSWORD_MAX_AGE", 0),
+		BcryptCost:          getEnvInt("PASSWORD_BCRYPT_COST", 0),
+		ResetTokenTTL:       getEnvDuration("PASSWORD_RESET_TOKEN_TTL", 1*time.Hour),
 	}
 
 	// Load file upload configuration
 	config.FileUpload = FileUploadConfig{
-		MaxFileSize:       getEnvInt64("FILE_UPLOAD_MAX_FILE_SIZE", 100*1024*1024),   // 100MB
-		MaxTotalSize:      getEnvInt64("FILE_UPLOAD_MAX_TOTAL_SIZE", 1024*1024*1024), // 1GB
-		AllowedMimeTypes:  getEnvSlice("FILE_UPLOAD_ALLOWED_MIME_TYPES", []string{"image/*", "application/pdf", "text/*"}),
-		AllowedExtensions: getEnvSlice("FILE_UPLOAD_ALLOWED_EXTENSIONS", []string{".jpg", ".jpeg", ".png", ".gif", ".pdf", ".txt", ".doc", ".docx"}),
-		ChunkSize:         getEnvInt64("FILE_UPLOAD_CHUNK_SIZE", 5*1024*1024), // 5MB
-		UploadTimeout:     getEnvDuration("FILE_UPLOAD_TIMEOUT", 10*time.Minute),
-		TempDir:           getEnv("FILE_UPLOAD_TEMP_DIR", "/tmp"),
-		VirusScanEnabled:  getEnvBool("FILE_UPLOAD_VIRUS_SCAN_ENABLED", false),
+		MaxFileSize:         getEnvInt64("FILE_UPLOAD_MAX_FILE_SIZE", 100*1024*1024),   // 100MB
+		MaxTotalSize:        getEnvInt64("FILE_UPLOAD_MAX_TOTAL_SIZE", 1024*1024*1024), // 1GB
+		AllowedMimeTypes:    getEnvSlice("FILE_UPLOAD_ALLOWED_MIME_TYPES", []string{"image/*", "application/pdf", "text/*"}),
+		AllowedExtensions:   getEnvSlice("FILE_UPLOAD_ALLOWED_EXTENSIONS", []string{".jpg", ".jpeg", ".png", ".gif", ".pdf", ".txt", ".doc", ".docx"}),
+		ChunkSize:           getEnvInt64("FILE_UPLOAD_CHUNK_SIZE", 5*1024*1024), // 5MB
+		UploadTimeout:       getEnvDuration("FILE_UPLOAD_TIMEOUT", 10*time.Minute),
+		TempDir:             getEnv("FILE_UPLOAD_TEMP_DIR", "/tmp"),
+		VirusScanEnabled:    getEnvBool("FILE_UPLOAD_VIRUS_SCAN_ENABLED", false),
+		MaxVersionsPerFile:  getEnvInt("FILE_UPLOAD_MAX_VERSIONS_PER_FILE", 10),
+		MaxTagFacets:        getEnvInt("FILE_SEARCH_MAX_TAG_FACETS", 50),
+		MaxCollisionRetries: getEnvInt("FILE_UPLOAD_MAX_COLLISION_RETRIES", 3),
+		UploadSessionMaxAge: getEnvDuration("FILE_UPLOAD_SESSION_MAX_AGE", 24*time.Hour),
+	}
+
+	// Load image processing configuration
+	config.ImageProcessing.OnDemandVariants = getEnvSlice("IMAGE_PROCESSING_ON_DEMAND_VARIANTS", nil)
+
+	// Load notification configuration
+	config.Notification.Enabled = getEnvBool("NOTIFICATION_ENABLED", true)
+	config.Notification.MaxBulkBatchSize = getEnvInt("NOTIFICATION_MAX_BULK_BATCH_SIZE", 1000)
+	config.Notification.BulkWorkers = getEnvInt("NOTIFICATION_BULK_WORKERS", 8)
+	config.Notification.DefaultPageSize = getEnvInt("NOTIFICATION_DEFAULT_PAGE_SIZE", 20)
+	config.Notification.MaxPageSize = getEnvInt("NOTIFICATION_MAX_PAGE_SIZE", 100)
+
+	// Load account deletion configuration
+	config.AccountDeletion = AccountDeletionConfig{
+		Enabled:     getEnvBool("ACCOUNT_DELETION_ENABLED", true),
+		GracePeriod: getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
 	}
 
 	// Load services configuration
@@ -754,18 +1017,108 @@ func (c *CacheConfig) GetConnectionString() string {
 	}
 }
 
-// Validate validates the configuration
+// validDatabaseDrivers are the database drivers the services support
+var validDatabaseDrivers = map[string]bool{"postgres": true, "mysql": true, "sqlite3": true}
+
+// validCacheDrivers are the cache backends the services support
+var validCacheDrivers = map[string]bool{"redis": true, "memcached": true, "in-memory": true}
+
+// validStorageDrivers are the storage backends the services support
+var validStorageDrivers = map[string]bool{"s3": true, "gcs": true, "azure": true, "local": true}
+
+// Validate validates the configuration, collecting every problem found
+// rather than failing on the first so operators can fix a misconfigured
+// deployment in one pass. Environment-specific rules (e.g. required secrets)
+// only apply in production.
 func (c *Config) Validate() error {
-	if c.Security.JWT.SecretKey == "" || c.Security.JWT.SecretKey == "your-secret-key" {
-		return fmt.Errorf("JWT secret key must be set and not use default value")
+	var problems []string
+
+	isProduction := c.Environment == "production"
+
+	// Server
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("server port %d is out of range", c.Server.Port))
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		problems = append(problems, "server timeouts must not be negative")
+	}
+	if c.Server.TLSEnabled {
+		if c.Server.TLSCertFile == "" || c.Server.TLSKeyFile == "" {
+			problems = append(problems, "TLS is enabled but cert file or key file is missing")
+		}
+	} else if c.Server.TLSCertFile != "" || c.Server.TLSKeyFile != "" {
+		problems = append(problems, "TLS cert/key configured but TLS is disabled")
+	}
+	if c.Server.CORSEnabled {
+		for _, origin := range c.Server.CORSOrigins {
+			if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+				problems = append(problems, fmt.Sprintf("CORS origin %q must be * or an http(s) URL", origin))
+			}
+		}
+		if isProduction {
+			for _, origin := range c.Server.CORSOrigins {
+				if origin == "*" {
+					problems = append(problems, "wildcard CORS origin is not allowed in production")
+					break
+				}
+			}
+		}
 	}
 
-	if c.Database.Password == "" && c.Environment == "production" {
-		return fmt.Errorf("database password must be set in production")
+	// Database
+	if !validDatabaseDrivers[c.Database.Driver] {
+		problems = append(problems, fmt.Sprintf("unknown database driver %q", c.Database.Driver))
+	}
+	if c.Database.MaxOpenConnections < 0 || c.Database.MaxIdleConnections < 0 {
+		problems = append(problems, "database connection pool sizes must not be negative")
+	}
+	if c.Database.ConnectionTimeout <= 0 || c.Database.QueryTimeout <= 0 {
+		problems = append(problems, "database timeouts must be positive")
+	}
+	if isProduction && c.Database.Password == "" {
+		problems = append(problems, "database password must be set in production")
 	}
 
+	// Cache
+	if c.Cache.Driver != "" && !validCacheDrivers[c.Cache.Driver] {
+		problems = append(problems, fmt.Sprintf("unknown cache driver %q", c.Cache.Driver))
+	}
+
+	// Storage
+	if c.Storage.Driver != "" && !validStorageDrivers[c.Storage.Driver] {
+		problems = append(problems, fmt.Sprintf("unknown storage driver %q", c.Storage.Driver))
+	}
+	if c.Storage.Driver == "local" && c.Storage.LocalPath == "" {
+		problems = append(problems, "local storage driver requires a local path")
+	}
+	if c.Storage.Driver == "s3" && c.Storage.Bucket == "" {
+		problems = append(problems, "s3 storage driver requires a bucket")
+	}
+
+	// Security
+	if c.Security.JWT.SecretKey == "" || c.Security.JWT.SecretKey == "your-secret-key" {
+		problems = append(problems, "JWT secret key must be set and not use the default value")
+	}
+	if c.Security.JWT.AccessTokenTTL <= 0 || c.Security.JWT.RefreshTokenTTL <= 0 {
+		problems = append(problems, "JWT token TTLs must be positive")
+	}
+	if c.Security.JWT.AccessTokenTTL >= c.Security.JWT.RefreshTokenTTL {
+		problems = append(problems, "access token TTL must be shorter than refresh token TTL")
+	}
+
+	// File upload
 	if c.FileUpload.MaxFileSize <= 0 {
-		return fmt.Errorf("max file size must be greater than 0")
+		problems = append(problems, "max file size must be greater than 0")
+	}
+	if c.FileUpload.MaxTotalSize > 0 && c.FileUpload.MaxTotalSize < c.FileUpload.MaxFileSize {
+		problems = append(problems, "max total size must not be smaller than max file size")
+	}
+	if c.FileUpload.ChunkSize <= 0 {
+		problems = append(problems, "upload chunk size must be positive")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
 	}
 
 	return nil
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Repository operation metrics shared by the instrumented repository
+// decorators in each service. Labels identify the repository and method so
+// slow or failing queries show up without changing call sites.
+var (
+	repositoryOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "repository_operations_total",
+			Help: "Total number of repository operations",
+		},
+		[]string{"repository", "operation", "status"},
+	)
+	repositoryOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "repository_operation_duration_seconds",
+			Help:    "Duration of repository operations in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"repository", "operation"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(repositoryOperationsTotal)
+	prometheus.MustRegister(repositoryOperationDuration)
+}
+
+// ObserveRepositoryOperation records one repository call. Intended use:
+//
+//	defer func(start time.Time) {
+//		data.ObserveRepositoryOperation("file", "Create", start, err)
+//	}(time.Now())
+func ObserveRepositoryOperation(repository, operation string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	repositoryOperationsTotal.WithLabelValues(repository, operation, status).Inc()
+	repositoryOperationDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,128 @@
+package data
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSlowQueryThreshold is the duration above which a query is sampled.
+// Override with the SLOW_QUERY_THRESHOLD environment variable.
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// SlowQueryStats aggregates the samples of one normalized query
+type SlowQueryStats struct {
+	Query         string        `json:"query"`
+	Count         int64         `json:"count"`
+	MaxDuration   time.Duration `json:"max_duration"`
+	TotalDuration time.Duration `json:"total_duration"`
+	AvgDuration   time.Duration `json:"avg_duration"`
+	LastSeen      time.Time     `json:"last_seen"`
+}
+
+// SlowQueryRecorder aggregates slow-query samples by normalized query text
+// so raw slow-query logs become queryable diagnostics.
+type SlowQueryRecorder struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	stats     map[string]*SlowQueryStats
+}
+
+// NewSlowQueryRecorder creates a recorder with the given threshold
+func NewSlowQueryRecorder(threshold time.Duration) *SlowQueryRecorder {
+	if threshold <= 0 {
+		threshold = DefaultSlowQueryThreshold
+	}
+	return &SlowQueryRecorder{
+		threshold: threshold,
+		stats:     make(map[string]*SlowQueryStats),
+	}
+}
+
+// DefaultSlowQueryRecorder is the process-wide recorder the database
+// wrappers report into
+var DefaultSlowQueryRecorder = NewSlowQueryRecorder(slowQueryThresholdFromEnv())
+
+func slowQueryThresholdFromEnv() time.Duration {
+	if value := os.Getenv("SLOW_QUERY_THRESHOLD"); value != "" {
+		if threshold, err := time.ParseDuration(value); err == nil && threshold > 0 {
+			return threshold
+		}
+	}
+	return DefaultSlowQueryThreshold
+}
+
+var (
+	whitespacePattern   = regexp.MustCompile(`\s+`)
+	numberPattern       = regexp.MustCompile(`\b\d+\b`)
+	stringLiteralRegexp = regexp.MustCompile(`'[^']*'`)
+)
+
+// normalizeQuery collapses whitespace and masks literals so variations of
+// the same query aggregate together
+func normalizeQuery(query string) string {
+	normalized := strings.TrimSpace(whitespacePattern.ReplaceAllString(query, " "))
+	normalized = stringLiteralRegexp.ReplaceAllString(normalized, "'?'")
+	normalized = numberPattern.ReplaceAllString(normalized, "?")
+	return normalized
+}
+
+// Record samples a query execution; fast queries are ignored
+func (r *SlowQueryRecorder) Record(query string, duration time.Duration) {
+	if duration < r.threshold {
+		return
+	}
+
+	normalized := normalizeQuery(query)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, exists := r.stats[normalized]
+	if !exists {
+		stats = &SlowQueryStats{Query: normalized}
+		r.stats[normalized] = stats
+	}
+
+	stats.Count++
+	stats.TotalDuration += duration
+	stats.AvgDuration = time.Duration(int64(stats.TotalDuration) / stats.Count)
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+	stats.LastSeen = time.Now()
+}
+
+// Top returns the slowest queries by max duration, most severe first
+func (r *SlowQueryRecorder) Top(limit int) []*SlowQueryStats {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	r.mu.Lock()
+	results := make([]*SlowQueryStats, 0, len(r.stats))
+	for _, stats := range r.stats {
+		copied := *stats
+		results = append(results, &copied)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].MaxDuration > results[j].MaxDuration
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// Reset clears all aggregated samples
+func (r *SlowQueryRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = make(map[string]*SlowQueryStats)
+}
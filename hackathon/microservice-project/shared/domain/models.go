@@ -13,6 +13,7 @@ type User struct {
 	FirstName    string                 `json:"first_name" db:"first_name"`
 	LastName     string                 `json:"last_name" db:"last_name"`
 	Role         UserRole               `json:"role" db:"role"`
+	OrgID        string                 `json:"org_id,omitempty" db:"org_id"`
 	Status       UserStatus             `json:"status" db:"status"`
 	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time              `json:"updated_at" db:"updated_at"`
@@ -252,15 +253,17 @@ type PasswordResetToken struct {
 
 // Session represents user sessions
 type Session struct {
-	ID         string        `json:"id" db:"id"`
-	UserID     string        `json:"user_id" db:"user_id"`
-	TokenID    string        `json:"token_id" db:"token_id"`
-	IPAddress  string        `json:"ip_address" db:"ip_address"`
-	UserAgent  string        `json:"user_agent" db:"user_agent"`
-	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
-	ExpiresAt  time.Time     `json:"expires_at" db:"expires_at"`
-	LastUsedAt time.Time     `json:"last_used_at" db:"last_used_at"`
-	Status     SessionStatus `json:"status" db:"status"`
+	ID          string        `json:"id" db:"id"`
+	UserID      string        `json:"user_id" db:"user_id"`
+	TokenID     string        `json:"token_id" db:"token_id"`
+	IPAddress   string        `json:"ip_address" db:"ip_address"`
+	UserAgent   string        `json:"user_agent" db:"user_agent"`
+	Fingerprint string        `json:"-" db:"fingerprint"`             // Hashed client fingerprint for refresh-token binding
+	Country     string        `json:"country,omitempty" db:"country"` // From geolocation enrichment of the IP
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time     `json:"expires_at" db:"expires_at"`
+	LastUsedAt  time.Time     `json:"last_used_at" db:"last_used_at"`
+	Status      SessionStatus `json:"status" db:"status"`
 }
 
 // SessionStatus represents session status
@@ -15,27 +15,29 @@ import (
 
 // featureFlagManager implements the FeatureFlagManager interface
 type featureFlagManager struct {
-	config     *FeatureFlagConfig
-	repository FeatureFlagRepository
-	cache      FeatureFlagCache
-	analytics  FeatureFlagAnalytics
-	evaluator  FeatureFlagEvaluator
-	storage    *flagStorage
-	mu         sync.RWMutex
-	running    bool
-	stopCh     chan struct{}
+	config        *FeatureFlagConfig
+	repository    FeatureFlagRepository
+	cache         FeatureFlagCache
+	analytics     FeatureFlagAnalytics
+	evaluator     FeatureFlagEvaluator
+	storage       *flagStorage
+	userOverrides *overrideStore
+	mu            sync.RWMutex
+	running       bool
+	stopCh        chan struct{}
 }
 
 // NewFeatureFlagManager creates a new feature flag manager
 func NewFeatureFlagManager(config *FeatureFlagConfig, repository FeatureFlagRepository, cache FeatureFlagCache, analytics FeatureFlagAnalytics) FeatureFlagManager {
 	return &featureFlagManager{
-		config:     config,
-		repository: repository,
-		cache:      cache,
-		analytics:  analytics,
-		evaluator:  NewDefaultEvaluator(),
-		storage:    newFlagStorage(),
-		stopCh:     make(chan struct{}),
+		config:        config,
+		repository:    repository,
+		cache:         cache,
+		analytics:     analytics,
+		evaluator:     NewDefaultEvaluator(),
+		storage:       newFlagStorage(),
+		userOverrides: newOverrideStore(),
+		stopCh:        make(chan struct{}),
 	}
 }
 
@@ -171,6 +173,11 @@ func (m *featureFlagManager) EvaluateFlag(ctx context.Context, flagID string, us
 		}, nil
 	}
 
+	// Per-user overrides win over rollout and targeting rules
+	if override := m.evaluateOverride(flagID, userContext); override != nil {
+		return override, nil
+	}
+
 	// Evaluate using the evaluator
 	result, err := m.evaluator.Evaluate(ctx, flag, userContext)
 	if err != nil {
@@ -0,0 +1,141 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// overrideStore holds per-user flag overrides. Overrides take precedence
+// over rollout and targeting rules, letting teams force-enable (or disable)
+// a flag for specific users such as QA or beta testers.
+type overrideStore struct {
+	mu        sync.RWMutex
+	overrides map[string]map[string]bool // flagID -> userID -> enabled
+}
+
+func newOverrideStore() *overrideStore {
+	return &overrideStore{
+		overrides: make(map[string]map[string]bool),
+	}
+}
+
+func (s *overrideStore) set(flagID, userID string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.overrides[flagID] == nil {
+		s.overrides[flagID] = make(map[string]bool)
+	}
+	s.overrides[flagID][userID] = enabled
+}
+
+func (s *overrideStore) remove(flagID, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.overrides[flagID], userID)
+	if len(s.overrides[flagID]) == 0 {
+		delete(s.overrides, flagID)
+	}
+}
+
+func (s *overrideStore) get(flagID, userID string) (enabled, exists bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	enabled, exists = s.overrides[flagID][userID]
+	return enabled, exists
+}
+
+func (s *overrideStore) list(flagID string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]bool, len(s.overrides[flagID]))
+	for userID, enabled := range s.overrides[flagID] {
+		result[userID] = enabled
+	}
+	return result
+}
+
+// SetUserOverride forces a flag on or off for one user, taking precedence
+// over rollout and targeting rules
+func (m *featureFlagManager) SetUserOverride(ctx context.Context, flagID, userID string, enabled bool) error {
+	if err := ValidateFlagID(flagID); err != nil {
+		return err
+	}
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	m.userOverrides.set(flagID, userID, enabled)
+	return nil
+}
+
+// RemoveUserOverride clears a user's override for a flag
+func (m *featureFlagManager) RemoveUserOverride(flagID, userID string) {
+	m.userOverrides.remove(flagID, userID)
+}
+
+// GetUserOverrides lists the overrides configured for a flag
+func (m *featureFlagManager) GetUserOverrides(flagID string) map[string]bool {
+	return m.userOverrides.list(flagID)
+}
+
+// evaluateOverride returns the override result for the user, if one exists
+func (m *featureFlagManager) evaluateOverride(flagID string, userContext *UserContext) *EvaluationResult {
+	if userContext == nil || userContext.UserID == "" {
+		return nil
+	}
+
+	enabled, exists := m.userOverrides.get(flagID, userContext.UserID)
+	if !exists {
+		return nil
+	}
+
+	return &EvaluationResult{
+		FlagID:    flagID,
+		Enabled:   enabled,
+		Reason:    "user override",
+		Timestamp: time.Now(),
+	}
+}
+
+// RegisterOverrideAdminRoutes exposes per-user override management on an
+// admin route group:
+//
+//	PUT    /flags/:id/overrides/:user_id  {"enabled": bool}
+//	DELETE /flags/:id/overrides/:user_id
+//	GET    /flags/:id/overrides
+func RegisterOverrideAdminRoutes(group *gin.RouterGroup, manager FeatureFlagManager) {
+	m, ok := manager.(*featureFlagManager)
+	if !ok {
+		return
+	}
+
+	group.PUT("/flags/:id/overrides/:user_id", func(c *gin.Context) {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := m.SetUserOverride(c.Request.Context(), c.Param("id"), c.Param("user_id"), req.Enabled); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"flag_id": c.Param("id"), "user_id": c.Param("user_id"), "enabled": req.Enabled})
+	})
+
+	group.DELETE("/flags/:id/overrides/:user_id", func(c *gin.Context) {
+		m.RemoveUserOverride(c.Param("id"), c.Param("user_id"))
+		c.JSON(http.StatusOK, gin.H{"message": "Override removed"})
+	})
+
+	group.GET("/flags/:id/overrides", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"flag_id": c.Param("id"), "overrides": m.GetUserOverrides(c.Param("id"))})
+	})
+}
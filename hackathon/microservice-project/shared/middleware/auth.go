@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys under which the validated caller identity is stored
+const (
+	AuthUserIDKey   = "auth_user_id"
+	AuthUserRoleKey = "auth_user_role"
+)
+
+// TokenValidator validates a bearer token and returns the authenticated
+// user's ID and role. Services back it with the auth service's gRPC
+// ValidateToken endpoint (shared/authrpc); the auth service itself validates
+// locally. Client-supplied headers must never be trusted for this.
+type TokenValidator func(ctx context.Context, token string) (userID, role string, err error)
+
+// RequireAuth authenticates the bearer token and stores the validated user
+// ID and role in the gin context for handlers to consume
+func RequireAuth(validate TokenValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
+			return
+		}
+
+		userID, role, err := validate(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		c.Set(AuthUserIDKey, userID)
+		c.Set(AuthUserRoleKey, role)
+		c.Next()
+	}
+}
+
+// RequireRole authenticates the bearer token and additionally requires the
+// validated role to match (e.g. "admin" for admin surfaces)
+func RequireRole(validate TokenValidator, role string) gin.HandlerFunc {
+	authenticate := RequireAuth(validate)
+	return func(c *gin.Context) {
+		authenticate(c)
+		if c.IsAborted() {
+			return
+		}
+		if c.GetString(AuthUserRoleKey) != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+			return
+		}
+	}
+}
+
+// AuthenticatedUserID returns the validated caller ID set by RequireAuth
+func AuthenticatedUserID(c *gin.Context) string {
+	return c.GetString(AuthUserIDKey)
+}
+
+// bearerToken extracts the token from the Authorization header
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if strings.HasPrefix(header, prefix) {
+		return strings.TrimPrefix(header, prefix)
+	}
+	return ""
+}
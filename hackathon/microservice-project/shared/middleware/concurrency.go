@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimiter tracks in-flight requests per client IP. Unlike
+// requests-per-window rate limiting, this bounds how many requests one IP
+// may have open at once, protecting handlers from slow-loris-style
+// exhaustion by slow concurrent requests.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	maxPerIP int
+}
+
+// NewConcurrencyLimiter creates a limiter allowing maxPerIP concurrent
+// requests per client IP
+func NewConcurrencyLimiter(maxPerIP int) *ConcurrencyLimiter {
+	if maxPerIP <= 0 {
+		maxPerIP = 32
+	}
+	return &ConcurrencyLimiter{
+		inFlight: make(map[string]int),
+		maxPerIP: maxPerIP,
+	}
+}
+
+// acquire reserves an in-flight slot for the IP, reporting whether the IP is
+// still under its cap
+func (l *ConcurrencyLimiter) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[ip] >= l.maxPerIP {
+		return false
+	}
+	l.inFlight[ip]++
+	return true
+}
+
+// release frees the IP's in-flight slot
+func (l *ConcurrencyLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight[ip]--
+	if l.inFlight[ip] <= 0 {
+		delete(l.inFlight, ip)
+	}
+}
+
+// Middleware rejects requests with 429 when the client IP already has the
+// maximum number of requests in flight, releasing the slot when the request
+// completes
+func (l *ConcurrencyLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+
+		if !l.acquire(ip) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many concurrent requests",
+				"message": "Wait for in-flight requests to complete before sending more.",
+			})
+			return
+		}
+		defer l.release(ip)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseFieldsParam parses the ?fields= query param and validates every
+// requested field against the resource's allowlist. Returns nil when no
+// filtering was requested.
+func ParseFieldsParam(c *gin.Context, allowed map[string]bool) ([]string, error) {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(raw, ",")
+	for i, field := range fields {
+		fields[i] = strings.TrimSpace(field)
+		if !allowed[fields[i]] {
+			return nil, fmt.Errorf("field %q is not selectable for this resource", fields[i])
+		}
+	}
+
+	return fields, nil
+}
+
+// FilterFields reduces a JSON-serializable object (or list of objects) to
+// only the requested fields, producing sparse responses for
+// bandwidth-sensitive clients. With no fields requested, the payload is
+// returned unchanged.
+func FilterFields(payload interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return payload, nil
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	// List of objects
+	var list []map[string]interface{}
+	if err := json.Unmarshal(encoded, &list); err == nil {
+		for i, item := range list {
+			list[i] = filterObject(item, keep)
+		}
+		return list, nil
+	}
+
+	// Single object
+	var object map[string]interface{}
+	if err := json.Unmarshal(encoded, &object); err == nil {
+		return filterObject(object, keep), nil
+	}
+
+	return payload, nil
+}
+
+func filterObject(object map[string]interface{}, keep map[string]bool) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(keep))
+	for key, value := range object {
+		if keep[key] {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
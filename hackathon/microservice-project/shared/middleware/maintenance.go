@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceMode is a runtime-toggleable read-only switch. While enabled,
+// write requests (POST/PUT/PATCH/DELETE) are rejected with 503 so operators
+// can run maintenance while reads keep working.
+type MaintenanceMode struct {
+	enabled atomic.Bool
+}
+
+// NewMaintenanceMode creates a maintenance mode switch with an initial state
+func NewMaintenanceMode(enabled bool) *MaintenanceMode {
+	m := &MaintenanceMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether read-only mode is active
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set toggles read-only mode at runtime
+func (m *MaintenanceMode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Middleware rejects write requests with 503 while maintenance mode is
+// enabled. Admin routes stay writable so the mode can be toggled back off.
+func (m *MaintenanceMode) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.Enabled() && isWriteMethod(c.Request.Method) && !strings.HasPrefix(c.Request.URL.Path, "/admin") {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Service is in maintenance mode; writes are temporarily disabled",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
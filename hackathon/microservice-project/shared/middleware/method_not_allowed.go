@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigureMethodNotAllowed enables gin's method-not-allowed handling and
+// installs a handler that returns 405 with an Allow header listing the
+// methods registered for the path, instead of letting wrong-method requests
+// fall through to 404.
+func ConfigureMethodNotAllowed(router *gin.Engine) {
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(func(c *gin.Context) {
+		allowed := allowedMethods(router, c.Request.URL.Path)
+		if len(allowed) > 0 {
+			c.Header("Allow", strings.Join(allowed, ", "))
+		}
+		c.JSON(http.StatusMethodNotAllowed, gin.H{
+			"error":   "Method not allowed",
+			"allowed": allowed,
+		})
+	})
+}
+
+// allowedMethods lists the HTTP methods registered for routes matching path
+func allowedMethods(router *gin.Engine, path string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+
+	for _, route := range router.Routes() {
+		if routeMatches(route.Path, path) && !seen[route.Method] {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
+	}
+
+	return methods
+}
+
+// routeMatches reports whether a gin route pattern matches a concrete path.
+// ":param" segments match any single segment and "*param" matches the rest.
+func routeMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(pathSegments)
+}
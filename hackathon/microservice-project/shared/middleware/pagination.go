@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaginationGuardConfig configures the pagination guard middleware. Routes
+// are assigned a maximum `limit` based on their cost class: expensive routes
+// (heavy per-row work such as report rows) get a low cap independent of the
+// generic default.
+type PaginationGuardConfig struct {
+	// DefaultMaxLimit applies to routes without an explicit cost class
+	DefaultMaxLimit int
+
+	// RouteMaxLimits maps gin route patterns (e.g. "/api/v1/reports") to the
+	// maximum limit allowed on that route
+	RouteMaxLimits map[string]int
+}
+
+// DefaultPaginationGuardConfig returns a guard config with a conservative
+// generic cap and no per-route overrides
+func DefaultPaginationGuardConfig() PaginationGuardConfig {
+	return PaginationGuardConfig{
+		DefaultMaxLimit: 100,
+		RouteMaxLimits:  make(map[string]int),
+	}
+}
+
+// PaginationGuard validates limit/offset query parameters before handlers
+// run. Requests exceeding the route's cap (or with malformed values) are
+// rejected with 400 instead of being silently clamped, so abusive clients
+// get an explicit signal.
+func PaginationGuard(config PaginationGuardConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxLimit := config.DefaultMaxLimit
+		if routeMax, ok := config.RouteMaxLimits[c.FullPath()]; ok {
+			maxLimit = routeMax
+		}
+
+		if limitStr := c.Query("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+				return
+			}
+			if maxLimit > 0 && limit > maxLimit {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("limit %d exceeds the maximum of %d for this endpoint", limit, maxLimit),
+				})
+				return
+			}
+		}
+
+		if offsetStr := c.Query("offset"); offsetStr != "" {
+			offset, err := strconv.Atoi(offsetStr)
+			if err != nil || offset < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceTokenValidator validates a machine token and its scope; backed by
+// the auth service's client-credentials subsystem (locally or over gRPC).
+type ServiceTokenValidator func(ctx context.Context, token, requiredScope string) error
+
+// RequireServiceToken guards internal endpoints with client-credentials
+// machine tokens, verifying the audience/scope claims via the validator
+func RequireServiceToken(validate ServiceTokenValidator, requiredScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		const prefix = "Bearer "
+		if !strings.HasPrefix(header, prefix) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Service token required"})
+			return
+		}
+
+		if err := validate(c.Request.Context(), strings.TrimPrefix(header, prefix), requiredScope); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseTimeRange parses and validates the start_date/end_date query params
+// shared by analytics-style endpoints. Values may be RFC3339 timestamps,
+// plain dates (2006-01-02) or relative forms ("now", "now-7d", "now-36h").
+// When both params are omitted the range defaults to the last defaultRange.
+// Inverted ranges and ranges longer than maxRange are rejected.
+func ParseTimeRange(c *gin.Context, defaultRange, maxRange time.Duration) (time.Time, time.Time, error) {
+	startStr := c.Query("start_date")
+	endStr := c.Query("end_date")
+
+	if startStr == "" && endStr == "" {
+		end := time.Now()
+		return end.Add(-defaultRange), end, nil
+	}
+	if startStr == "" || endStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("both start_date and end_date are required")
+	}
+
+	start, err := ParseTimeValue(startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date: %v", err)
+	}
+	end, err := ParseTimeValue(endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date: %v", err)
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date must be after start_date")
+	}
+	if maxRange > 0 && end.Sub(start) > maxRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range exceeds maximum of %s", maxRange)
+	}
+
+	return start, end, nil
+}
+
+// ParseTimeValue parses one time value in RFC3339, plain-date or relative
+// ("now-7d") form
+func ParseTimeValue(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+	if strings.HasPrefix(value, "now-") {
+		offset, err := parseRelativeOffset(strings.TrimPrefix(value, "now-"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Now().Add(-offset), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+// parseRelativeOffset parses offsets like "7d", "36h" or "90m". Days are
+// handled here because time.ParseDuration does not support them.
+func parseRelativeOffset(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid day offset %q", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	offset, err := time.ParseDuration(value)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	return offset, nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownHook is a cleanup function run during graceful shutdown (e.g. flush
+// an event buffer or drain in-flight uploads). Hooks receive the shutdown
+// context and should respect its deadline.
+type ShutdownHook func(ctx context.Context) error
+
+// RunServer starts the HTTP server, waits for SIGINT/SIGTERM (or ctx
+// cancellation), runs the registered shutdown hooks in order, then shuts the
+// server down within the given timeout. It replaces the signal-handling and
+// shutdown boilerplate previously duplicated across services.
+func RunServer(ctx context.Context, server *http.Server, shutdownTimeout time.Duration, onShutdown ...ShutdownHook) error {
+	errChan := make(chan error, 1)
+
+	go func() {
+		log.Printf("Server starting on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+
+	// Wait for a termination signal, context cancellation or startup failure
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	select {
+	case err := <-errChan:
+		return err
+	case sig := <-quit:
+		log.Printf("Received signal %s, shutting down...", sig)
+	case <-ctx.Done():
+		log.Println("Context cancelled, shutting down...")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Run shutdown hooks in registration order before stopping the server
+	for _, hook := range onShutdown {
+		if err := hook(shutdownCtx); err != nil {
+			log.Printf("Shutdown hook failed: %v", err)
+		}
+	}
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	log.Println("Server exited")
+	return nil
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// commonPasswords are frequently used passwords (and bases of them) that
+// should score 0 regardless of the character classes they contain.
+var commonPasswords = map[string]bool{
+	"password": true, "passw0rd": true, "letmein": true, "welcome": true,
+	"qwerty": true, "abc123": true, "monkey": true, "dragon": true,
+	"iloveyou": true, "admin": true, "login": true, "master": true,
+	"sunshine": true, "princess": true, "football": true, "baseball": true,
+	"superman": true, "trustno1": true, "123456": true, "12345678": true,
+}
+
+// keyboardSequences are runs that add little entropy despite their length
+var keyboardSequences = []string{
+	"qwertyuiop", "asdfghjkl", "zxcvbnm",
+	"0123456789", "9876543210", "abcdefghijklmnopqrstuvwxyz",
+}
+
+// PasswordStrengthScore estimates password strength on a 0-4 scale
+// (zxcvbn-style) and returns guidance for weak passwords. It is intended to
+// complement, not replace, rule-based character-class checks: compliant but
+// guessable passwords like "Password1!" score low here.
+func PasswordStrengthScore(password string) (int, []string) {
+	var guidance []string
+
+	if password == "" {
+		return 0, []string{"password is empty"}
+	}
+
+	normalized := normalizeForStrength(password)
+
+	// Common passwords (optionally with trivial decorations) score 0
+	if commonPasswords[normalized] {
+		return 0, []string{"password is a commonly used password"}
+	}
+	for base := range commonPasswords {
+		if strings.Contains(normalized, base) && len(normalized) <= len(base)+4 {
+			return 0, []string{fmt.Sprintf("password is based on the common password %q", base)}
+		}
+	}
+
+	// Estimate entropy from character pool and effective length
+	poolSize := 0
+	if HasLowercase(password) {
+		poolSize += 26
+	}
+	if HasUppercase(password) {
+		poolSize += 26
+	}
+	if HasDigit(password) {
+		poolSize += 10
+	}
+	if HasSpecialChar(password) {
+		poolSize += 33
+	}
+
+	effectiveLength := float64(len(password))
+
+	// Penalize repeated characters ("aaaa") and keyboard/alphabet sequences
+	if repeats := countRepeatedRuns(password); repeats > 0 {
+		effectiveLength -= float64(repeats)
+		guidance = append(guidance, "avoid repeated characters")
+	}
+	lower := strings.ToLower(password)
+	for _, sequence := range keyboardSequences {
+		for length := len(sequence); length >= 4; length-- {
+			for start := 0; start+length <= len(sequence); start++ {
+				if strings.Contains(lower, sequence[start:start+length]) {
+					effectiveLength -= float64(length - 1)
+					guidance = append(guidance, "avoid keyboard or alphabet sequences")
+					goto sequenceDone
+				}
+			}
+		}
+	}
+sequenceDone:
+
+	if effectiveLength < 1 {
+		effectiveLength = 1
+	}
+
+	entropy := effectiveLength * math.Log2(float64(maxInt(poolSize, 2)))
+
+	score := 0
+	switch {
+	case entropy >= 80:
+		score = 4
+	case entropy >= 60:
+		score = 3
+	case entropy >= 40:
+		score = 2
+	case entropy >= 25:
+		score = 1
+	}
+
+	if score < 2 {
+		guidance = append(guidance, "use a longer passphrase or mix unrelated words")
+	}
+
+	return score, guidance
+}
+
+// normalizeForStrength lowercases and strips trivial digit/symbol decorations
+// so "Password1!" normalizes to "password"
+func normalizeForStrength(password string) string {
+	normalized := strings.ToLower(password)
+	normalized = strings.TrimRight(normalized, "0123456789!@#$%^&*().?")
+	// Undo common leetspeak substitutions
+	replacer := strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+	return replacer.Replace(normalized)
+}
+
+// countRepeatedRuns counts extra characters in runs of three or more
+func countRepeatedRuns(password string) int {
+	extra := 0
+	runLength := 1
+	for i := 1; i < len(password); i++ {
+		if password[i] == password[i-1] {
+			runLength++
+			if runLength >= 3 {
+				extra++
+			}
+		} else {
+			runLength = 1
+		}
+	}
+	return extra
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
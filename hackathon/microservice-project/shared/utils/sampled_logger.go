@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"log"
+	"sync"
+)
+
+// SampledLogger rate-limits repetitive warnings: for each key it logs the
+// first N occurrences and then only one in every M, with a running count.
+// During an outage this keeps a failure that fires on every request from
+// producing millions of identical log lines.
+type SampledLogger struct {
+	firstN     int64
+	sampleRate int64
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewSampledLogger creates a sampled logger that logs the first firstN
+// occurrences per key and then one in every sampleRate
+func NewSampledLogger(firstN, sampleRate int64) *SampledLogger {
+	if firstN <= 0 {
+		firstN = 5
+	}
+	if sampleRate <= 0 {
+		sampleRate = 100
+	}
+	return &SampledLogger{
+		firstN:     firstN,
+		sampleRate: sampleRate,
+		counts:     make(map[string]int64),
+	}
+}
+
+// Warnf logs a formatted warning for the key, subject to sampling. Sampled
+// lines carry the occurrence count so readers can see the true volume.
+func (l *SampledLogger) Warnf(key, format string, args ...interface{}) {
+	l.mu.Lock()
+	l.counts[key]++
+	count := l.counts[key]
+	l.mu.Unlock()
+
+	if count <= l.firstN {
+		log.Printf("WARN "+format, args...)
+		return
+	}
+	if count%l.sampleRate == 0 {
+		args = append(args, count)
+		log.Printf("WARN "+format+" (occurred %d times, sampled)", args...)
+	}
+}
+
+// Reset clears the occurrence counters (e.g. after an incident resolves)
+func (l *SampledLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts = make(map[string]int64)
+}
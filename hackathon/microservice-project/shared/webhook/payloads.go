@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elotusteam/microservice-project/shared/domain"
+)
+
+// SchemaVersion identifies the webhook payload schema version. New fields may
+// be added within a version; breaking changes require a new version so that
+// existing consumers keep working.
+type SchemaVersion string
+
+const (
+	// SchemaVersionV1 is the initial webhook payload schema version
+	SchemaVersionV1 SchemaVersion = "v1"
+)
+
+// Envelope is the wire format for delivered webhooks. The schema version is
+// part of both the delivered payload and the signature so consumers can
+// dispatch on it and verify integrity.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	Version   SchemaVersion   `json:"version"`
+	Timestamp time.Time       `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+	Signature string          `json:"signature,omitempty"`
+}
+
+// FileUploadedPayloadV1 is the v1 payload for file.uploaded events
+type FileUploadedPayloadV1 struct {
+	Version     SchemaVersion `json:"version"`
+	FileID      string        `json:"file_id"`
+	OwnerID     string        `json:"owner_id"`
+	Filename    string        `json:"filename"`
+	ContentType string        `json:"content_type"`
+	Size        int64         `json:"size"`
+	Checksum    string        `json:"checksum"`
+	UploadedAt  time.Time     `json:"uploaded_at"`
+}
+
+// FileDeletedPayloadV1 is the v1 payload for file.deleted events
+type FileDeletedPayloadV1 struct {
+	Version   SchemaVersion `json:"version"`
+	FileID    string        `json:"file_id"`
+	OwnerID   string        `json:"owner_id"`
+	Filename  string        `json:"filename"`
+	DeletedAt time.Time     `json:"deleted_at"`
+}
+
+// FileSharedPayloadV1 is the v1 payload for file.shared events
+type FileSharedPayloadV1 struct {
+	Version    SchemaVersion `json:"version"`
+	FileID     string        `json:"file_id"`
+	SharedBy   string        `json:"shared_by"`
+	SharedWith string        `json:"shared_with,omitempty"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+	SharedAt   time.Time     `json:"shared_at"`
+}
+
+// UserCreatedPayloadV1 is the v1 payload for user.created events
+type UserCreatedPayloadV1 struct {
+	Version   SchemaVersion `json:"version"`
+	UserID    string        `json:"user_id"`
+	Username  string        `json:"username"`
+	Email     string        `json:"email"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// UserDeletedPayloadV1 is the v1 payload for user.deleted events
+type UserDeletedPayloadV1 struct {
+	Version   SchemaVersion `json:"version"`
+	UserID    string        `json:"user_id"`
+	DeletedAt time.Time     `json:"deleted_at"`
+}
+
+// PermissionGrantedPayloadV1 is the v1 payload for permission.granted events
+type PermissionGrantedPayloadV1 struct {
+	Version    SchemaVersion `json:"version"`
+	FileID     string        `json:"file_id"`
+	UserID     string        `json:"user_id,omitempty"`
+	GroupID    string        `json:"group_id,omitempty"`
+	Permission string        `json:"permission"`
+	GrantedBy  string        `json:"granted_by"`
+	GrantedAt  time.Time     `json:"granted_at"`
+}
+
+// PermissionRevokedPayloadV1 is the v1 payload for permission.revoked events
+type PermissionRevokedPayloadV1 struct {
+	Version    SchemaVersion `json:"version"`
+	FileID     string        `json:"file_id"`
+	UserID     string        `json:"user_id,omitempty"`
+	GroupID    string        `json:"group_id,omitempty"`
+	Permission string        `json:"permission"`
+	RevokedBy  string        `json:"revoked_by"`
+	RevokedAt  time.Time     `json:"revoked_at"`
+}
+
+// PayloadFactory creates an empty payload struct for decoding
+type PayloadFactory func() interface{}
+
+// Registry maps event type and schema version to payload factories so
+// producers and consumers share one source of truth for payload contracts.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]PayloadFactory
+}
+
+// NewRegistry creates an empty payload registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]PayloadFactory),
+	}
+}
+
+// Register registers a payload factory for an event type and schema version
+func (r *Registry) Register(eventType string, version SchemaVersion, factory PayloadFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[registryKey(eventType, version)] = factory
+}
+
+// Resolve returns a new empty payload for the event type and schema version
+func (r *Registry) Resolve(eventType string, version SchemaVersion) (interface{}, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[registryKey(eventType, version)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no payload registered for event %s version %s", eventType, version)
+	}
+	return factory(), nil
+}
+
+func registryKey(eventType string, version SchemaVersion) string {
+	return fmt.Sprintf("%s@%s", eventType, version)
+}
+
+// DefaultRegistry holds the v1 payload contracts for all supported events
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(domain.EventTypeFileUploaded, SchemaVersionV1, func() interface{} { return &FileUploadedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypeFileDeleted, SchemaVersionV1, func() interface{} { return &FileDeletedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypeFileShared, SchemaVersionV1, func() interface{} { return &FileSharedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypeUserCreated, SchemaVersionV1, func() interface{} { return &UserCreatedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypeUserDeleted, SchemaVersionV1, func() interface{} { return &UserDeletedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypePermissionGranted, SchemaVersionV1, func() interface{} { return &PermissionGrantedPayloadV1{} })
+	DefaultRegistry.Register(domain.EventTypePermissionRevoked, SchemaVersionV1, func() interface{} { return &PermissionRevokedPayloadV1{} })
+}
+
+// NewEnvelope wraps a payload in a delivery envelope and signs it. The
+// signature covers the event type, schema version and payload bytes.
+func NewEnvelope(id, eventType string, version SchemaVersion, payload interface{}, signingKey string) (*Envelope, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	envelope := &Envelope{
+		ID:        id,
+		Event:     eventType,
+		Version:   version,
+		Timestamp: time.Now(),
+		Payload:   payloadBytes,
+	}
+	envelope.Signature = Sign(envelope, signingKey)
+
+	return envelope, nil
+}
+
+// Sign computes the HMAC-SHA256 signature for an envelope
+func Sign(envelope *Envelope, signingKey string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(envelope.Event))
+	mac.Write([]byte(envelope.Version))
+	mac.Write(envelope.Payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks an envelope's signature against the signing key
+func VerifySignature(envelope *Envelope, signingKey string) bool {
+	expected := Sign(envelope, signingKey)
+	return hmac.Equal([]byte(expected), []byte(envelope.Signature))
+}
+
+// DecodePayload decodes an envelope's payload using the registry contracts
+func DecodePayload(registry *Registry, envelope *Envelope) (interface{}, error) {
+	payload, err := registry.Resolve(envelope.Event, envelope.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return payload, nil
+}
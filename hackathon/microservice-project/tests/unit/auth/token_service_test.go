@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/usecases"
+	"github.com/elotusteam/microservice-project/shared/config"
+	sharedDomain "github.com/elotusteam/microservice-project/shared/domain"
+)
+
+func tokenTestConfig(algorithm string) *config.Config {
+	cfg := &config.Config{}
+	cfg.Security.JWT = config.JWTConfig{
+		SecretKey:       "unit-test-secret",
+		Issuer:          "fileserver",
+		Audience:        "fileserver-users",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+		Algorithm:       algorithm,
+	}
+	return cfg
+}
+
+func tokenTestUser() *sharedDomain.User {
+	return &sharedDomain.User{
+		ID:       "user-1",
+		Username: "alice",
+		Email:    "alice@example.com",
+		Role:     sharedDomain.UserRoleUser,
+		Status:   sharedDomain.UserStatusActive,
+	}
+}
+
+// TestRS256SignAndValidate verifies the asymmetric signing round trip
+func TestRS256SignAndValidate(t *testing.T) {
+	service, err := usecases.NewTokenService(tokenTestConfig("RS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	token, err := service.GenerateAccessToken(ctx, tokenTestUser())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	claims, err := service.ValidateAccessToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken failed: %v", err)
+	}
+	if claims.UserID != "user-1" {
+		t.Errorf("expected user-1, got %s", claims.UserID)
+	}
+}
+
+// TestRS256RejectsHS256Tokens verifies that an attacker cannot downgrade
+// verification by presenting an HS256 token when RS256 is configured
+func TestRS256RejectsHS256Tokens(t *testing.T) {
+	ctx := context.Background()
+
+	// Sign a token with the HS256 service using the same shared secret
+	hsService, err := usecases.NewTokenService(tokenTestConfig("HS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService(HS256) failed: %v", err)
+	}
+	hsToken, err := hsService.GenerateAccessToken(ctx, tokenTestUser())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	rsService, err := usecases.NewTokenService(tokenTestConfig("RS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService(RS256) failed: %v", err)
+	}
+
+	if _, err := rsService.ValidateAccessToken(ctx, hsToken); err == nil {
+		t.Fatal("RS256 service accepted an HS256 token (algorithm confusion)")
+	} else if !strings.Contains(err.Error(), "disallowed algorithm") {
+		t.Errorf("expected a disallowed-algorithm error, got: %v", err)
+	}
+}
+
+// TestKeyRotationKeepsOutstandingTokensValid verifies that rotating the
+// signing key does not invalidate tokens issued before the rotation
+func TestKeyRotationKeepsOutstandingTokensValid(t *testing.T) {
+	service, err := usecases.NewTokenService(tokenTestConfig("RS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	oldToken, err := service.GenerateAccessToken(ctx, tokenTestUser())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	if _, err := service.RotateSigningKey(ctx); err != nil {
+		t.Fatalf("RotateSigningKey failed: %v", err)
+	}
+
+	// The pre-rotation token must still verify via the retained public key
+	if _, err := service.ValidateAccessToken(ctx, oldToken); err != nil {
+		t.Errorf("pre-rotation token failed validation after rotation: %v", err)
+	}
+
+	// Tokens signed by the new key must verify too
+	newToken, err := service.GenerateAccessToken(ctx, tokenTestUser())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken after rotation failed: %v", err)
+	}
+	if _, err := service.ValidateAccessToken(ctx, newToken); err != nil {
+		t.Errorf("post-rotation token failed validation: %v", err)
+	}
+}
+
+// TestJWKSContainsRotatedKeys verifies the published key set covers both the
+// active and rotated-out verification keys
+func TestJWKSContainsRotatedKeys(t *testing.T) {
+	service, err := usecases.NewTokenService(tokenTestConfig("RS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := service.RotateSigningKey(ctx); err != nil {
+		t.Fatalf("RotateSigningKey failed: %v", err)
+	}
+
+	jwks, err := service.JWKS(ctx)
+	if err != nil {
+		t.Fatalf("JWKS failed: %v", err)
+	}
+
+	keys, ok := jwks["keys"].([]map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected JWKS shape: %T", jwks["keys"])
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected 2 keys after one rotation, got %d", len(keys))
+	}
+	for _, key := range keys {
+		if key["alg"] != "RS256" || key["kty"] != "RSA" || key["kid"] == "" {
+			t.Errorf("malformed JWK entry: %v", key)
+		}
+	}
+}
+
+// TestHS256StillWorksWhenConfigured verifies the symmetric path is intact
+func TestHS256StillWorksWhenConfigured(t *testing.T) {
+	service, err := usecases.NewTokenService(tokenTestConfig("HS256"))
+	if err != nil {
+		t.Fatalf("NewTokenService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	token, err := service.GenerateAccessToken(ctx, tokenTestUser())
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+	if _, err := service.ValidateAccessToken(ctx, token); err != nil {
+		t.Errorf("HS256 validation failed: %v", err)
+	}
+
+	if _, err := service.JWKS(ctx); err == nil {
+		t.Error("JWKS should be unavailable without RS256")
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elotusteam/microservice-project/services/auth/infrastructure"
+)
+
+// simulateLongQuery blocks like a slow database query until the transaction's
+// context is cancelled or the query "completes"
+func simulateLongQuery(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+		return nil
+	}
+}
+
+// TestTransactionContextPropagation verifies that the context a transaction
+// is started with is propagated to queries issued inside it, so cancelling a
+// request aborts in-flight work instead of letting it run to completion.
+func TestTransactionContextPropagation(t *testing.T) {
+	repoManager := infrastructure.NewMockRepositoryManager()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err := repoManager.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+
+	if tx.Context() != ctx {
+		t.Fatal("transaction context should be the context passed to BeginTransaction")
+	}
+
+	// Cancel the request context and verify a long query aborts promptly
+	cancel()
+
+	start := time.Now()
+	err = simulateLongQuery(tx.Context(), 5*time.Second)
+	elapsed := time.Since(start)
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("cancelled query took %s to abort; expected immediate return", elapsed)
+	}
+
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		t.Errorf("rollback after cancellation failed: %v", rollbackErr)
+	}
+}
+
+// TestTransactionContextDeadline verifies that per-request deadlines survive
+// the transaction boundary
+func TestTransactionContextDeadline(t *testing.T) {
+	repoManager := infrastructure.NewMockRepositoryManager()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	tx, err := repoManager.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+
+	if _, ok := tx.Context().Deadline(); !ok {
+		t.Fatal("transaction context should carry the request deadline")
+	}
+
+	if err := simulateLongQuery(tx.Context(), 5*time.Second); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// findLengthImplementations maps implementation names to functions so the
+// corpus tests and benchmarks cover all four strategies uniformly.
+var findLengthImplementations = []struct {
+	name string
+	fn   func([]int, []int) int
+}{
+	{"BruteForce", findLengthBruteForce},
+	{"DP", findLength},
+	{"OptimizedDP", findLengthOptimized},
+	{"RollingHash", findLengthRollingHash},
+}
+
+// benchmarkDistributions generates inputs with different value distributions,
+// which stress the implementations differently (e.g. binary values create
+// long runs of matches, distinct values almost none).
+var benchmarkDistributions = []struct {
+	name string
+	gen  func(rng *rand.Rand, size int) []int
+}{
+	{"Binary", func(rng *rand.Rand, size int) []int {
+		return randomSlice(rng, size, 2)
+	}},
+	{"SmallAlphabet", func(rng *rand.Rand, size int) []int {
+		return randomSlice(rng, size, 10)
+	}},
+	{"Distinct", func(rng *rand.Rand, size int) []int {
+		return randomSlice(rng, size, 1<<30)
+	}},
+	{"Identical", func(rng *rand.Rand, size int) []int {
+		nums := make([]int, size)
+		for i := range nums {
+			nums[i] = i % 10
+		}
+		return nums
+	}},
+}
+
+func randomSlice(rng *rand.Rand, size, alphabet int) []int {
+	nums := make([]int, size)
+	for i := range nums {
+		nums[i] = rng.Intn(alphabet)
+	}
+	return nums
+}
+
+// TestFindLengthRandomizedCorpus asserts that all four implementations agree
+// on a large randomized corpus across sizes and value distributions, using the
+// brute force solution as the reference.
+func TestFindLengthRandomizedCorpus(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	sizes := []int{1, 5, 20, 75, 150}
+	alphabets := []int{2, 5, 100}
+
+	for _, size := range sizes {
+		for _, alphabet := range alphabets {
+			name := fmt.Sprintf("size=%d/alphabet=%d", size, alphabet)
+			t.Run(name, func(t *testing.T) {
+				for trial := 0; trial < 20; trial++ {
+					nums1 := randomSlice(rng, size, alphabet)
+					nums2 := randomSlice(rng, rng.Intn(size)+1, alphabet)
+
+					expected := findLengthBruteForce(nums1, nums2)
+					for _, impl := range findLengthImplementations[1:] {
+						if got := impl.fn(nums1, nums2); got != expected {
+							t.Fatalf("%s = %d, brute force = %d for nums1=%v nums2=%v",
+								impl.name, got, expected, nums1, nums2)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+// benchmarkFindLength runs one implementation across representative input
+// sizes and value distributions.
+func benchmarkFindLength(b *testing.B, fn func([]int, []int) int) {
+	sizes := []int{50, 200, 500}
+
+	for _, size := range sizes {
+		for _, dist := range benchmarkDistributions {
+			rng := rand.New(rand.NewSource(int64(size)))
+			nums1 := dist.gen(rng, size)
+			nums2 := dist.gen(rng, size)
+
+			b.Run(fmt.Sprintf("size=%d/%s", size, dist.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					fn(nums1, nums2)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFindLengthBruteForceSuite(b *testing.B) {
+	benchmarkFindLength(b, findLengthBruteForce)
+}
+
+func BenchmarkFindLengthDPSuite(b *testing.B) {
+	benchmarkFindLength(b, findLength)
+}
+
+func BenchmarkFindLengthOptimizedSuite(b *testing.B) {
+	benchmarkFindLength(b, findLengthOptimized)
+}
+
+func BenchmarkFindLengthRollingHashSuite(b *testing.B) {
+	benchmarkFindLength(b, findLengthRollingHash)
+}
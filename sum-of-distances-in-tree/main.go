@@ -170,6 +170,128 @@ func sumOfDistancesInTreeAlt(n int, edges [][]int) []int {
 	return ans
 }
 
+// rerootBalances returns, for each node, the size of the largest remaining
+// component when that node is removed (its "balance"). The node minimizing
+// this value is a centroid, which makes this useful for picking optimal roots
+// in centroid-decomposition use cases. Runs in O(n) by reusing the subtree
+// sizes from the first DFS pass.
+func rerootBalances(n int, edges [][]int) []int {
+	if n == 1 {
+		return []int{0}
+	}
+
+	// Build adjacency list
+	graph := make([][]int, n)
+	for _, edge := range edges {
+		a, b := edge[0], edge[1]
+		graph[a] = append(graph[a], b)
+		graph[b] = append(graph[b], a)
+	}
+
+	// Reuse the subtree sizes computed by the first DFS pass
+	subtreeSize := make([]int, n)
+	subtreeSum := make([]int, n)
+	dfs1(0, -1, graph, subtreeSize, subtreeSum)
+
+	balances := make([]int, n)
+	var dfs func(node, parent int)
+	dfs = func(node, parent int) {
+		// Removing node splits the tree into each child's subtree plus the
+		// component containing the parent (everything above node)
+		largest := n - subtreeSize[node]
+		for _, child := range graph[node] {
+			if child == parent {
+				continue
+			}
+			if subtreeSize[child] > largest {
+				largest = subtreeSize[child]
+			}
+			dfs(child, node)
+		}
+		balances[node] = largest
+	}
+	dfs(0, -1)
+
+	return balances
+}
+
+// Safe variant: validates user-supplied edge lists before running the
+// optimized algorithm. The DFS-based solutions assume a well-formed tree and
+// would recurse forever (or produce wrong sums) on self-loops or parallel
+// edges, so malformed input is rejected with an error instead.
+func sumOfDistancesInTreeSafe(n int, edges [][]int) ([]int, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("n must be positive, got %d", n)
+	}
+	if len(edges) != n-1 {
+		return nil, fmt.Errorf("a tree with %d nodes requires %d edges, got %d", n, n-1, len(edges))
+	}
+
+	seen := make(map[[2]int]bool, len(edges))
+	for _, edge := range edges {
+		if len(edge) != 2 {
+			return nil, fmt.Errorf("edge %v must have exactly two endpoints", edge)
+		}
+		a, b := edge[0], edge[1]
+
+		if a < 0 || a >= n || b < 0 || b >= n {
+			return nil, fmt.Errorf("edge {%d,%d} references a node outside [0,%d)", a, b, n)
+		}
+		if a == b {
+			return nil, fmt.Errorf("self-loop edge {%d,%d} is not allowed in a tree", a, b)
+		}
+
+		// Normalize endpoint order so {a,b} and {b,a} count as duplicates
+		key := [2]int{a, b}
+		if a > b {
+			key = [2]int{b, a}
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate edge {%d,%d}", a, b)
+		}
+		seen[key] = true
+	}
+
+	// With n-1 valid edges, connectivity is what separates a tree from a
+	// cycle plus disconnected nodes, which would break the DFS passes
+	if n > 1 {
+		graph := make([][]int, n)
+		for _, edge := range edges {
+			a, b := edge[0], edge[1]
+			graph[a] = append(graph[a], b)
+			graph[b] = append(graph[b], a)
+		}
+		if reached := calculateReachableCount(0, graph, n); reached != n {
+			return nil, fmt.Errorf("edges do not form a connected tree: only %d of %d nodes reachable", reached, n)
+		}
+	}
+
+	return sumOfDistancesInTree(n, edges), nil
+}
+
+// calculateReachableCount counts nodes reachable from the source using BFS
+func calculateReachableCount(source int, graph [][]int, n int) int {
+	visited := make([]bool, n)
+	queue := []int{source}
+	visited[source] = true
+	count := 1
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, neighbor := range graph[node] {
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				count++
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return count
+}
+
 func main() {
 	// Test cases
 	testCases := []struct {
@@ -221,4 +221,141 @@ func TestConsistencyBetweenImplementations(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+// Test the safe variant's input validation
+func TestSumOfDistancesInTreeSafe(t *testing.T) {
+	t.Run("Valid tree", func(t *testing.T) {
+		result, err := sumOfDistancesInTreeSafe(6, [][]int{{0, 1}, {0, 2}, {2, 3}, {2, 4}, {2, 5}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []int{8, 12, 6, 10, 10, 10}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Single node", func(t *testing.T) {
+		result, err := sumOfDistancesInTreeSafe(1, [][]int{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, []int{0}) {
+			t.Errorf("Expected [0], got %v", result)
+		}
+	})
+
+	t.Run("Self-loop edge", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(3, [][]int{{0, 1}, {2, 2}})
+		if err == nil {
+			t.Error("Expected error for self-loop edge, got nil")
+		}
+	})
+
+	t.Run("Duplicate edge", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(3, [][]int{{0, 1}, {1, 0}})
+		if err == nil {
+			t.Error("Expected error for duplicate edge, got nil")
+		}
+	})
+
+	t.Run("Out of range node", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(3, [][]int{{0, 1}, {1, 5}})
+		if err == nil {
+			t.Error("Expected error for out-of-range node, got nil")
+		}
+	})
+
+	t.Run("Wrong edge count", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(4, [][]int{{0, 1}, {1, 2}})
+		if err == nil {
+			t.Error("Expected error for too few edges, got nil")
+		}
+	})
+
+	t.Run("Cycle with disconnected node", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(4, [][]int{{0, 1}, {1, 2}, {2, 0}})
+		if err == nil {
+			t.Error("Expected error for disconnected graph, got nil")
+		}
+	})
+
+	t.Run("Non-positive n", func(t *testing.T) {
+		_, err := sumOfDistancesInTreeSafe(0, [][]int{})
+		if err == nil {
+			t.Error("Expected error for n=0, got nil")
+		}
+	})
+}
+
+// Test the reroot balance computation
+func TestRerootBalances(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		edges    [][]int
+		expected []int
+	}{
+		{
+			name:     "Single node",
+			n:        1,
+			edges:    [][]int{},
+			expected: []int{0},
+		},
+		{
+			name:     "Two nodes",
+			n:        2,
+			edges:    [][]int{{0, 1}},
+			expected: []int{1, 1},
+		},
+		{
+			name:  "Linear tree",
+			n:     4,
+			edges: [][]int{{0, 1}, {1, 2}, {2, 3}},
+			// Removing an end node leaves 3 connected nodes; removing an
+			// inner node leaves components of sizes {1,2} -> max 2
+			expected: []int{3, 2, 2, 3},
+		},
+		{
+			name:  "Star tree",
+			n:     5,
+			edges: [][]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}},
+			// Removing the hub leaves singletons; removing a leaf leaves 4
+			expected: []int{1, 4, 4, 4, 4},
+		},
+		{
+			name:     "Example tree",
+			n:        6,
+			edges:    [][]int{{0, 1}, {0, 2}, {2, 3}, {2, 4}, {2, 5}},
+			expected: []int{4, 5, 2, 5, 5, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rerootBalances(tt.n, tt.edges)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("rerootBalances() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// The centroid (node with minimum balance) of a path should be its middle
+func TestRerootBalancesCentroid(t *testing.T) {
+	n := 7
+	edges := [][]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}}
+
+	balances := rerootBalances(n, edges)
+
+	centroid := 0
+	for i, balance := range balances {
+		if balance < balances[centroid] {
+			centroid = i
+		}
+	}
+
+	if centroid != 3 {
+		t.Errorf("Expected centroid 3 for a 7-node path, got %d (balances %v)", centroid, balances)
+	}
+}